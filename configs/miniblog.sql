@@ -19,21 +19,91 @@
 /*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
 
 
-DROP TABLE IF EXISTS `post`;
-CREATE TABLE `post`
+DROP TABLE IF EXISTS `api_key`;
+CREATE TABLE `api_key`
+(
+    `id`         bigint unsigned NOT NULL AUTO_INCREMENT,
+    `username`   varchar(255) NOT NULL,
+    `name`       varchar(255) NOT NULL,
+    `prefix`     varchar(32)  NOT NULL,
+    `keyHash`    varchar(64)  NOT NULL,
+    `scopes`     varchar(255) NOT NULL,
+    `expiresAt`  timestamp    NULL DEFAULT NULL,
+    `lastUsedAt` timestamp    NULL DEFAULT NULL,
+    `createdAt`  timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updatedAt`  timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `prefix` (`prefix`),
+    UNIQUE KEY `idx_username_name` (`username`, `name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb3;
+
+DROP TABLE IF EXISTS `identity`;
+CREATE TABLE `identity`
 (
     `id`        bigint unsigned NOT NULL AUTO_INCREMENT,
     `username`  varchar(255) NOT NULL,
-    `postID`    varchar(256) NOT NULL,
-    `title`     varchar(256) NOT NULL,
-    `content`   longtext     NOT NULL,
+    `issuer`    varchar(255) NOT NULL,
+    `subject`   varchar(255) NOT NULL,
     `createdAt` timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
     `updatedAt` timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_issuer_subject` (`issuer`, `subject`),
+    KEY         `idx_identity_username` (`username`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb3;
+
+DROP TABLE IF EXISTS `invitation`;
+CREATE TABLE `invitation`
+(
+    `id`        bigint unsigned NOT NULL AUTO_INCREMENT,
+    `code`      varchar(32)  NOT NULL,
+    `createdBy` varchar(255) NOT NULL,
+    `maxUses`   int          NOT NULL,
+    `uses`      int          NOT NULL DEFAULT 0,
+    `expiresAt` timestamp    NULL DEFAULT NULL,
+    `createdAt` timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updatedAt` timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `code` (`code`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb3;
+
+DROP TABLE IF EXISTS `post`;
+CREATE TABLE `post`
+(
+    `id`          bigint unsigned NOT NULL AUTO_INCREMENT,
+    `username`    varchar(255) NOT NULL,
+    `postID`      varchar(256) NOT NULL,
+    `title`       varchar(256) NOT NULL,
+    `content`     longtext     NOT NULL,
+    `format`      varchar(16)  NOT NULL DEFAULT 'markdown',
+    `contentHTML` longtext     NOT NULL,
+    `createdAt`   timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updatedAt`   timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `status`      varchar(16)  NOT NULL DEFAULT 'published',
+    `publishAt`   timestamp    NULL DEFAULT NULL,
+    `deletedAt`   timestamp    NULL DEFAULT NULL,
+    PRIMARY KEY (`id`),
     UNIQUE KEY `postID` (`postID`),
-    KEY         `idx_username` (`username`)
+    KEY         `idx_username` (`username`),
+    KEY         `idx_post_status` (`status`, `publishAt`),
+    KEY         `idx_post_deletedAt` (`deletedAt`)
 ) ENGINE=InnoDB AUTO_INCREMENT=141 DEFAULT CHARSET=utf8mb3;
 
+DROP TABLE IF EXISTS `post_revision`;
+CREATE TABLE `post_revision`
+(
+    `id`          bigint unsigned NOT NULL AUTO_INCREMENT,
+    `postID`      varchar(256) NOT NULL,
+    `revision`    int          NOT NULL,
+    `username`    varchar(255) NOT NULL,
+    `title`       varchar(256) NOT NULL,
+    `content`     longtext     NOT NULL,
+    `format`      varchar(16)  NOT NULL DEFAULT 'markdown',
+    `contentHash` varchar(64)  NOT NULL,
+    `createdAt`   timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_post_revision` (`postID`, `revision`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb3;
+
 DROP TABLE IF EXISTS `user`;
 CREATE TABLE `user`
 (
@@ -43,10 +113,20 @@ CREATE TABLE `user`
     `nickname`  varchar(30)  NOT NULL,
     `email`     varchar(256) NOT NULL,
     `phone`     varchar(16)  NOT NULL,
+    `role`      varchar(16)  NOT NULL DEFAULT 'user',
+    `failedLogins` int       NOT NULL DEFAULT 0,
+    `lockedUntil`  timestamp NULL DEFAULT NULL,
+    `totpSecret`      varchar(64) NOT NULL DEFAULT '',
+    `totpEnabled`     tinyint(1)  NOT NULL DEFAULT 0,
+    `totpLastCounter` bigint      NOT NULL DEFAULT 0,
+    `recoveryCodes`   text        NULL,
+    `emailVerified`   tinyint(1)  NOT NULL DEFAULT 0,
     `createdAt` timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
     `updatedAt` timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `deletedAt` timestamp    NULL DEFAULT NULL,
     PRIMARY KEY (`id`),
-    UNIQUE KEY `username` (`username`)
+    UNIQUE KEY `username` (`username`),
+    KEY         `idx_user_deletedAt` (`deletedAt`)
 ) ENGINE=InnoDB AUTO_INCREMENT=27 DEFAULT CHARSET=utf8mb3;
 
 
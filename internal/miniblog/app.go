@@ -0,0 +1,186 @@
+package miniblog
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"miniblog/internal/miniblog/biz"
+	"miniblog/internal/miniblog/controller/v1/post"
+	"miniblog/internal/miniblog/controller/v1/user"
+	"miniblog/internal/miniblog/store"
+	"miniblog/internal/pkg/known"
+	"miniblog/internal/pkg/log"
+	"miniblog/internal/pkg/middleware"
+	"miniblog/pkg/auth"
+	"miniblog/pkg/db"
+	"miniblog/pkg/mail"
+	"miniblog/pkg/ratelimit"
+	"miniblog/pkg/token"
+)
+
+// App 是 miniblog 的应用容器，持有服务运行所需的全部依赖：配置、数据库、store、biz、controller 等。
+// App 在 run() 中显式构建，依赖通过参数向下传递，不依赖包级别的全局变量，测试中可以使用 fake 依赖构建多个互不影响的 App
+type App struct {
+	cfg     *viper.Viper
+	db      *gorm.DB // 使用 NewAppWithStore 创建时为 nil
+	store   store.IStore
+	biz     biz.IBiz
+	limiter ratelimit.Limiter
+	hasher  *auth.Hasher  // 根据 `auth.password-hash` 创建，使用旧参数加密的密码会在用户登录时重新加密
+	tokens  *token.Config // 根据 `jwt-secret` 和 `jwt-expire` 创建，用于 token 的签发和解析
+	mail    *mail.Queue   // 异步发送邮件的队列，Close 时等待队列中的邮件发送完成
+
+	userController *user.UserController
+	postController *post.PostController
+}
+
+// NewApp 根据配置创建数据库连接，并依次构建 store、biz 和 controller 层
+func NewApp(cfg *viper.Viper) (*App, error) {
+	instance, err := newDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	app, err := NewAppWithStore(cfg, store.NewStore(instance, cfg.GetDuration("db.query-timeout")))
+	if err != nil {
+		return nil, err
+	}
+	app.db = instance
+
+	return app, nil
+}
+
+// NewAppWithStore 使用指定的 store 构建 App，测试中可以传入 fake 或基于内存数据库的 store
+func NewAppWithStore(cfg *viper.Viper, ds store.IStore) (*App, error) {
+	limiter, err := newRateLimiter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	hashOpts, err := hashOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	hasher, err := auth.NewHasher(hashOpts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth.password-hash: %w", err)
+	}
+
+	tokens, err := token.New(cfg.GetString("jwt-secret"), known.XUsernameKey, cfg.GetDuration("jwt-expire"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwt-secret: %w", err)
+	}
+
+	policy, err := passwordPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	lockout, err := lockoutPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactor, err := twoFactorPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	mailOpts, err := mailOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	mailOpts.Queue = mail.NewQueue(mailOpts.Sender, cfg.GetInt("mail.queue-size"), func(msg *mail.Message, err error) {
+		log.Errorw("Failed to send mail", "to", msg.To, "subject", msg.Subject, "err", err)
+	})
+
+	oidcOpts, err := oidcOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	registration, err := registrationMode(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	b := biz.NewBiz(ds, &biz.Options{
+		Hasher:         hasher,
+		Tokens:         tokens,
+		PasswordPolicy: policy,
+		Lockout:        lockout,
+		TwoFactor:      twoFactor,
+		Mail:           mailOpts,
+		OIDC:           oidcOpts,
+		Registration:   registration,
+	})
+
+	return &App{
+		cfg:            cfg,
+		store:          ds,
+		biz:            b,
+		limiter:        limiter,
+		hasher:         hasher,
+		tokens:         tokens,
+		mail:           mailOpts.Queue,
+		userController: user.New(b),
+		postController: post.New(b),
+	}, nil
+}
+
+// Engine 创建 Gin 引擎，注册全局中间件和所有路由
+func (a *App) Engine() (*gin.Engine, error) {
+	g := gin.New()
+
+	// 使 gin.Context 的 Deadline、Done、Err 和 Value 方法回退到 c.Request.Context()，这样请求的截止时间才能传递到 biz 层和 store 层
+	g.ContextWithFallback = true
+
+	// 只信任配置的反向代理设置的 X-Forwarded-For，否则客户端可以伪造 IP 绕过按 IP 的限流
+	if err := g.SetTrustedProxies(a.cfg.GetStringSlice("trusted-proxies")); err != nil {
+		return nil, fmt.Errorf("invalid trusted-proxies: %w", err)
+	}
+
+	cors, err := corsOptions(a.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// gin.Recover 中间件，用来捕获任何 panic 并恢复
+	middlewares := []gin.HandlerFunc{
+		gin.Recovery(),
+		middleware.NoCache,
+		middleware.Cors(cors),
+		middleware.Secure,
+		middleware.RequestID(),
+		middleware.BodyLimit(int64(a.cfg.GetSizeInBytes("request.max-body-size"))),
+		middleware.Timeout(a.cfg.GetDuration("request.timeout"), routeTimeouts(a.cfg)...),
+	}
+	if a.cfg.GetBool("db.read-your-writes") {
+		middlewares = append(middlewares, middleware.ReadYourWrites())
+	}
+
+	g.Use(middlewares...)
+
+	if err := a.installRouters(g); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// FlushMail 等待队列中的邮件全部发送完成，测试中读取邮件之前需要调用
+func (a *App) FlushMail() {
+	a.mail.Flush()
+}
+
+// Close 释放 App 持有的资源，例如数据库连接。队列中还没有发送的邮件会在关闭前发送完
+func (a *App) Close() error {
+	a.mail.Close()
+
+	if a.db == nil {
+		return nil
+	}
+
+	return db.Close(a.db)
+}
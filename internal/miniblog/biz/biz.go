@@ -1,29 +1,56 @@
 package biz
 
+//go:generate mockgen -destination mock_biz.go -package biz miniblog/internal/miniblog/biz IBiz
+
 import (
+	"miniblog/internal/miniblog/biz/post"
 	"miniblog/internal/miniblog/biz/user"
 	"miniblog/internal/miniblog/store"
+	"miniblog/pkg/auth"
+	"miniblog/pkg/token"
 )
 
 // IBiz 定义了 Biz 层需要实现的方法
 type IBiz interface {
 	Users() user.UserBiz
+	Posts() post.PostBiz
+}
+
+// Options 包含 biz 层的配置项
+type Options struct {
+	Hasher         *auth.Hasher          // 加密和校验密码使用的 Hasher，为 nil 时使用默认参数
+	Tokens         *token.Config         // 签发和解析 token 的配置，为 nil 时使用默认配置
+	PasswordPolicy *auth.PasswordPolicy  // 创建用户、修改和重置密码时使用的密码策略，为 nil 时使用默认策略
+	Lockout        *auth.LockoutPolicy   // 登录失败后的限制策略，为 nil 时使用默认策略
+	TwoFactor      *auth.TwoFactorPolicy // 两步验证的配置，为 nil 时使用默认配置
+	Mail           *user.MailOptions     // 验证邮件和重置密码邮件的配置，为 nil 时丢弃所有邮件
+	OIDC           *user.OIDCOptions     // 通过外部身份提供方登录的配置，为 nil 时不支持 OIDC 登录
+	Registration   string                // 注册模式，可选值：open,invite-only,closed，为空时为 open
 }
 
 // Biz 是 IBiz 的一个具体实现.
 type Biz struct {
-	ds store.IStore
+	ds   store.IStore
+	opts Options
 }
 
 // 确保 Biz 实现了 IBiz 接口
 var _ IBiz = (*Biz)(nil)
 
 // NewBiz 创建一个 IBiz 类型的实例.
-func NewBiz(ds store.IStore) *Biz {
-	return &Biz{ds: ds}
+func NewBiz(ds store.IStore, opts *Options) *Biz {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return &Biz{ds: ds, opts: *opts}
 }
 
 // Users 返回一个实现了 UserBiz 接口的实例.
 func (b *Biz) Users() user.UserBiz {
-	return user.New(b.ds)
+	return user.New(b.ds, &user.Options{Hasher: b.opts.Hasher, Tokens: b.opts.Tokens, PasswordPolicy: b.opts.PasswordPolicy, Lockout: b.opts.Lockout, TwoFactor: b.opts.TwoFactor, Mail: b.opts.Mail, OIDC: b.opts.OIDC, Registration: b.opts.Registration})
+}
+
+// Posts 返回一个实现了 PostBiz 接口的实例.
+func (b *Biz) Posts() post.PostBiz {
+	return post.New(b.ds)
 }
@@ -0,0 +1,228 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: miniblog/internal/miniblog/biz/post (interfaces: PostBiz)
+
+// Package post is a generated GoMock package.
+package post
+
+import (
+	context "context"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPostBiz is a mock of PostBiz interface.
+type MockPostBiz struct {
+	ctrl     *gomock.Controller
+	recorder *MockPostBizMockRecorder
+}
+
+// MockPostBizMockRecorder is the mock recorder for MockPostBiz.
+type MockPostBizMockRecorder struct {
+	mock *MockPostBiz
+}
+
+// NewMockPostBiz creates a new mock instance.
+func NewMockPostBiz(ctrl *gomock.Controller) *MockPostBiz {
+	mock := &MockPostBiz{ctrl: ctrl}
+	mock.recorder = &MockPostBizMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPostBiz) EXPECT() *MockPostBizMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPostBiz) Create(arg0 context.Context, arg1 string, arg2 *v1.CreatePostRequest) (*v1.CreatePostResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.CreatePostResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPostBizMockRecorder) Create(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPostBiz)(nil).Create), arg0, arg1, arg2)
+}
+
+// Delete mocks base method.
+func (m *MockPostBiz) Delete(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPostBizMockRecorder) Delete(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPostBiz)(nil).Delete), arg0, arg1, arg2)
+}
+
+// DiffRevisions mocks base method.
+func (m *MockPostBiz) DiffRevisions(arg0 context.Context, arg1, arg2 string, arg3 *v1.DiffPostRevisionsRequest) (*v1.DiffPostRevisionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffRevisions", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*v1.DiffPostRevisionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffRevisions indicates an expected call of DiffRevisions.
+func (mr *MockPostBizMockRecorder) DiffRevisions(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffRevisions", reflect.TypeOf((*MockPostBiz)(nil).DiffRevisions), arg0, arg1, arg2, arg3)
+}
+
+// Get mocks base method.
+func (m *MockPostBiz) Get(arg0 context.Context, arg1, arg2 string) (*v1.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPostBizMockRecorder) Get(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPostBiz)(nil).Get), arg0, arg1, arg2)
+}
+
+// GetRevision mocks base method.
+func (m *MockPostBiz) GetRevision(arg0 context.Context, arg1, arg2 string, arg3 int) (*v1.PostRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*v1.PostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockPostBizMockRecorder) GetRevision(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockPostBiz)(nil).GetRevision), arg0, arg1, arg2, arg3)
+}
+
+// List mocks base method.
+func (m *MockPostBiz) List(arg0 context.Context, arg1 string, arg2 *v1.ListPostsRequest) (*v1.ListPostsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.ListPostsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPostBizMockRecorder) List(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPostBiz)(nil).List), arg0, arg1, arg2)
+}
+
+// ListDeleted mocks base method.
+func (m *MockPostBiz) ListDeleted(arg0 context.Context, arg1 *v1.ListPostsRequest) (*v1.ListPostsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeleted", arg0, arg1)
+	ret0, _ := ret[0].(*v1.ListPostsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeleted indicates an expected call of ListDeleted.
+func (mr *MockPostBizMockRecorder) ListDeleted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeleted", reflect.TypeOf((*MockPostBiz)(nil).ListDeleted), arg0, arg1)
+}
+
+// ListRevisions mocks base method.
+func (m *MockPostBiz) ListRevisions(arg0 context.Context, arg1, arg2 string, arg3 *v1.ListPostRevisionsRequest) (*v1.ListPostRevisionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*v1.ListPostRevisionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockPostBizMockRecorder) ListRevisions(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockPostBiz)(nil).ListRevisions), arg0, arg1, arg2, arg3)
+}
+
+// PublishDue mocks base method.
+func (m *MockPostBiz) PublishDue(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishDue", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishDue indicates an expected call of PublishDue.
+func (mr *MockPostBizMockRecorder) PublishDue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDue", reflect.TypeOf((*MockPostBiz)(nil).PublishDue), arg0, arg1)
+}
+
+// Purge mocks base method.
+func (m *MockPostBiz) Purge(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockPostBizMockRecorder) Purge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockPostBiz)(nil).Purge), arg0, arg1)
+}
+
+// Restore mocks base method.
+func (m *MockPostBiz) Restore(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockPostBizMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockPostBiz)(nil).Restore), arg0, arg1)
+}
+
+// RestoreRevision mocks base method.
+func (m *MockPostBiz) RestoreRevision(arg0 context.Context, arg1, arg2 string, arg3 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRevision", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreRevision indicates an expected call of RestoreRevision.
+func (mr *MockPostBizMockRecorder) RestoreRevision(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRevision", reflect.TypeOf((*MockPostBiz)(nil).RestoreRevision), arg0, arg1, arg2, arg3)
+}
+
+// Update mocks base method.
+func (m *MockPostBiz) Update(arg0 context.Context, arg1, arg2 string, arg3 *v1.UpdatePostRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPostBizMockRecorder) Update(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPostBiz)(nil).Update), arg0, arg1, arg2, arg3)
+}
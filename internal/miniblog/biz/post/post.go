@@ -0,0 +1,303 @@
+package post
+
+//go:generate mockgen -destination mock_post.go -package post miniblog/internal/miniblog/biz/post PostBiz
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"miniblog/internal/miniblog/store"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/known"
+	"miniblog/internal/pkg/log"
+	"miniblog/internal/pkg/model"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	"miniblog/pkg/render"
+	"strings"
+	"time"
+)
+
+// defaultLimit 是分页查询时每页的默认数量
+const defaultLimit = 20
+
+// PostBiz 定义了 post 模块在 biz 层所实现的方法
+type PostBiz interface {
+	Create(ctx context.Context, username string, req *v1.CreatePostRequest) (*v1.CreatePostResponse, error)
+	Get(ctx context.Context, viewer, postID string) (*v1.Post, error)
+	List(ctx context.Context, viewer string, req *v1.ListPostsRequest) (*v1.ListPostsResponse, error)
+	Update(ctx context.Context, username, postID string, req *v1.UpdatePostRequest) error
+	Delete(ctx context.Context, username, postID string) error
+	ListDeleted(ctx context.Context, req *v1.ListPostsRequest) (*v1.ListPostsResponse, error)
+	Restore(ctx context.Context, postID string) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+	PublishDue(ctx context.Context, now time.Time) (int64, error)
+	ListRevisions(ctx context.Context, viewer, postID string, req *v1.ListPostRevisionsRequest) (*v1.ListPostRevisionsResponse, error)
+	GetRevision(ctx context.Context, viewer, postID string, revision int) (*v1.PostRevision, error)
+	DiffRevisions(ctx context.Context, viewer, postID string, req *v1.DiffPostRevisionsRequest) (*v1.DiffPostRevisionsResponse, error)
+	RestoreRevision(ctx context.Context, username, postID string, revision int) error
+}
+
+type PostBusiness struct {
+	ds store.IStore
+}
+
+// 确保 PostBusiness 实现了 PostBiz 接口
+var _ PostBiz = (*PostBusiness)(nil)
+
+// New 创建 PostBusiness
+func New(ds store.IStore) *PostBusiness {
+	return &PostBusiness{ds: ds}
+}
+
+// Create 以 username 的身份发布一篇博客
+func (b *PostBusiness) Create(ctx context.Context, username string, req *v1.CreatePostRequest) (*v1.CreatePostResponse, error) {
+	// 用户被删除后，删除前签发的 Token 在过期之前仍然有效，不能再用来发布博客
+	if _, err := b.ds.Users().Get(ctx, username); err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, errno.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	postID, err := newPostID()
+	if err != nil {
+		return nil, err
+	}
+
+	format := req.Format
+	if format == "" {
+		format = known.PostFormatMarkdown
+	}
+	postM := &model.PostM{Username: username, PostID: postID, Title: req.Title, Content: req.Content, Format: format}
+	postM.ContentHTML = renderContent(format, req.Content)
+	status := req.Status
+	if status == "" && req.PublishAt == nil {
+		status = known.PostStatusPublished
+	}
+	if err := setStatus(postM, status, req.PublishAt, time.Now()); err != nil {
+		return nil, err
+	}
+
+	err = b.ds.TX(ctx, func(ctx context.Context, tx store.IStore) error {
+		if err := tx.Posts().Create(ctx, postM); err != nil {
+			return err
+		}
+		return tx.PostRevisions().Create(ctx, newRevision(postM, 1, username))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.CreatePostResponse{PostID: postID}, nil
+}
+
+// Get 返回博客的详细信息，未发布的博客只有作者 viewer 可以查看
+func (b *PostBusiness) Get(ctx context.Context, viewer, postID string) (*v1.Post, error) {
+	postM, err := b.getVisiblePost(ctx, viewer, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPost(postM), nil
+}
+
+// List 按创建时间倒序分页返回 viewer 可以看到的博客，即所有已发布的博客和 viewer 自己的博客。req.Username 为空时返回所有用户的博客
+func (b *PostBusiness) List(ctx context.Context, viewer string, req *v1.ListPostsRequest) (*v1.ListPostsResponse, error) {
+	filter := &store.PostFilter{Username: req.Username, Status: req.Status, Viewer: viewer}
+	count, list, err := b.ds.Posts().List(ctx, filter, req.Offset, limit(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return toListPostsResponse(count, list), nil
+}
+
+// Update 修改 username 的博客，只更新 req 中不为 nil 的字段。标题、内容或格式有变化时会创建一个新版本
+func (b *PostBusiness) Update(ctx context.Context, username, postID string, req *v1.UpdatePostRequest) error {
+	postM, err := b.getOwnPost(ctx, username, postID)
+	if err != nil {
+		return err
+	}
+	old := *postM
+
+	if req.Content != nil && *req.Content == "" {
+		return errno.ErrInvalidParam.WithMessage("content must not be empty.")
+	}
+
+	if req.Title != nil {
+		postM.Title = *req.Title
+	}
+	if req.Content != nil {
+		postM.Content = *req.Content
+	}
+	if req.Format != nil {
+		postM.Format = *req.Format
+	}
+	var status string
+	if req.Status != nil {
+		status = *req.Status
+	}
+	if err := setStatus(postM, status, req.PublishAt, time.Now()); err != nil {
+		return err
+	}
+
+	return b.update(ctx, username, &old, postM)
+}
+
+// Delete 删除 username 的博客。博客不会被立即永久删除，在保留期内管理员可以恢复
+func (b *PostBusiness) Delete(ctx context.Context, username, postID string) error {
+	if _, err := b.getOwnPost(ctx, username, postID); err != nil {
+		return err
+	}
+
+	if err := b.ds.Posts().Delete(ctx, postID, time.Now()); err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return errno.ErrPostNotFound
+		}
+		return err
+	}
+
+	log.C(ctx).Infow("Post deleted", "username", username, "postID", postID)
+	return nil
+}
+
+// ListDeleted 按删除时间倒序分页返回已删除但尚未永久删除的博客，req.Username 为空时返回所有用户的博客
+func (b *PostBusiness) ListDeleted(ctx context.Context, req *v1.ListPostsRequest) (*v1.ListPostsResponse, error) {
+	count, list, err := b.ds.Posts().ListDeleted(ctx, req.Username, req.Offset, limit(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return toListPostsResponse(count, list), nil
+}
+
+// Restore 恢复已删除的博客，博客的作者已被删除时需要先恢复作者
+func (b *PostBusiness) Restore(ctx context.Context, postID string) error {
+	postM, err := b.ds.Posts().GetDeleted(ctx, postID)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return errno.ErrPostNotFound
+		}
+		return err
+	}
+
+	if _, err := b.ds.Users().Get(ctx, postM.Username); err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return errno.ErrPostAuthorDeleted
+		}
+		return err
+	}
+
+	if err := b.ds.Posts().Restore(ctx, postID); err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return errno.ErrPostNotFound
+		}
+		return err
+	}
+
+	log.C(ctx).Infow("Post restored", "username", postM.Username, "postID", postID)
+	return nil
+}
+
+// Purge 永久删除在 before 之前删除的博客，返回删除的数量
+func (b *PostBusiness) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return b.ds.Posts().Purge(ctx, before)
+}
+
+// getPost 查询博客，不存在或已删除时返回 ErrPostNotFound
+func (b *PostBusiness) getPost(ctx context.Context, postID string) (*model.PostM, error) {
+	postM, err := b.ds.Posts().Get(ctx, postID)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, errno.ErrPostNotFound
+		}
+		return nil, err
+	}
+	return postM, nil
+}
+
+// getVisiblePost 查询 viewer 可以看到的博客，未发布的博客只有作者可以看到，其他用户查询时返回 ErrPostNotFound
+func (b *PostBusiness) getVisiblePost(ctx context.Context, viewer, postID string) (*model.PostM, error) {
+	postM, err := b.getPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if postM.Status != known.PostStatusPublished && postM.Username != viewer {
+		return nil, errno.ErrPostNotFound
+	}
+	return postM, nil
+}
+
+// getOwnPost 查询 username 的博客，博客属于其他用户时返回 ErrPermissionDenied
+func (b *PostBusiness) getOwnPost(ctx context.Context, username, postID string) (*model.PostM, error) {
+	postM, err := b.getPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if postM.Username != username {
+		return nil, errno.ErrPermissionDenied
+	}
+	return postM, nil
+}
+
+// renderContent 根据内容格式将 content 渲染为 HTML，渲染结果已过滤脚本和危险的属性
+func renderContent(format, content string) string {
+	switch format {
+	case known.PostFormatHTML:
+		return render.Sanitize(content)
+	case known.PostFormatPlain:
+		return render.Plain(content)
+	default:
+		return render.Markdown(content)
+	}
+}
+
+// newPostID 生成一个随机的博客 ID，例如 `post-5wbtq3g2`
+func newPostID() (string, error) {
+	buf := make([]byte, 5)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "post-" + strings.ToLower(base32.StdEncoding.EncodeToString(buf)), nil
+}
+
+// limit 返回分页查询每页的数量，为 0 时使用默认值
+func limit(n int) int {
+	if n <= 0 {
+		return defaultLimit
+	}
+	return n
+}
+
+func toListPostsResponse(count int64, list []*model.PostM) *v1.ListPostsResponse {
+	resp := &v1.ListPostsResponse{TotalCount: count, Posts: make([]*v1.Post, 0, len(list))}
+	for _, postM := range list {
+		resp.Posts = append(resp.Posts, toPost(postM))
+	}
+	return resp
+}
+
+func toPost(postM *model.PostM) *v1.Post {
+	post := &v1.Post{
+		PostID:      postM.PostID,
+		Username:    postM.Username,
+		Title:       postM.Title,
+		Content:     postM.Content,
+		Format:      postM.Format,
+		ContentHTML: postM.ContentHTML,
+		Status:      postM.Status,
+		PublishAt:   postM.PublishAt,
+		CreatedAt:   postM.CreatedAt,
+		UpdatedAt:   postM.UpdatedAt,
+	}
+	// 支持内容格式之前发布的博客没有渲染结果，在读取时渲染
+	if post.ContentHTML == "" && post.Content != "" {
+		post.ContentHTML = renderContent(post.Format, post.Content)
+	}
+	if postM.DeletedAt.Valid {
+		deletedAt := postM.DeletedAt.Time
+		post.DeletedAt = &deletedAt
+	}
+	return post
+}
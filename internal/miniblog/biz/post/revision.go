@@ -0,0 +1,201 @@
+package post
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"miniblog/internal/miniblog/store"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	"miniblog/internal/pkg/model"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	"miniblog/pkg/diff"
+)
+
+// diffContext 是版本差异中每处修改前后保留的上下文行数
+const diffContext = 3
+
+// ListRevisions 按版本号倒序分页返回博客的历史版本，返回的版本不包含内容
+func (b *PostBusiness) ListRevisions(ctx context.Context, viewer, postID string, req *v1.ListPostRevisionsRequest) (*v1.ListPostRevisionsResponse, error) {
+	if _, err := b.getVisiblePost(ctx, viewer, postID); err != nil {
+		return nil, err
+	}
+
+	count, list, err := b.ds.PostRevisions().List(ctx, postID, req.Offset, limit(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &v1.ListPostRevisionsResponse{TotalCount: count, Revisions: make([]*v1.PostRevision, 0, len(list))}
+	for _, revisionM := range list {
+		resp.Revisions = append(resp.Revisions, toPostRevision(revisionM))
+	}
+	return resp, nil
+}
+
+// GetRevision 返回博客的指定版本，包括该版本的内容
+func (b *PostBusiness) GetRevision(ctx context.Context, viewer, postID string, revision int) (*v1.PostRevision, error) {
+	if _, err := b.getVisiblePost(ctx, viewer, postID); err != nil {
+		return nil, err
+	}
+
+	revisionM, err := b.getRevision(ctx, postID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPostRevision(revisionM), nil
+}
+
+// DiffRevisions 按行比较博客的两个版本，返回两个版本的标题和内容的 unified 格式差异
+func (b *PostBusiness) DiffRevisions(ctx context.Context, viewer, postID string, req *v1.DiffPostRevisionsRequest) (*v1.DiffPostRevisionsResponse, error) {
+	if _, err := b.getVisiblePost(ctx, viewer, postID); err != nil {
+		return nil, err
+	}
+
+	from, err := b.getRevision(ctx, postID, req.From)
+	if err != nil {
+		return nil, err
+	}
+
+	var to *model.PostRevisionM
+	if req.To == 0 {
+		to, err = b.ds.PostRevisions().Latest(ctx, postID)
+		if errors.Is(err, store.ErrRecordNotFound) {
+			err = errno.ErrPostRevisionNotFound
+		}
+	} else {
+		to, err = b.getRevision(ctx, postID, req.To)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.DiffPostRevisionsResponse{
+		From:      from.Revision,
+		To:        to.Revision,
+		FromTitle: from.Title,
+		ToTitle:   to.Title,
+		Diff: diff.Unified(fmt.Sprintf("revision %d", from.Revision), fmt.Sprintf("revision %d", to.Revision),
+			diff.Lines(from.Content, to.Content), diffContext),
+	}, nil
+}
+
+// RestoreRevision 将 username 的博客回滚到指定版本。回滚不会删除之后的版本，而是以该版本的标题和内容创建一个新版本
+func (b *PostBusiness) RestoreRevision(ctx context.Context, username, postID string, revision int) error {
+	postM, err := b.getOwnPost(ctx, username, postID)
+	if err != nil {
+		return err
+	}
+
+	revisionM, err := b.getRevision(ctx, postID, revision)
+	if err != nil {
+		return err
+	}
+
+	old := *postM
+	postM.Title, postM.Content, postM.Format = revisionM.Title, revisionM.Content, revisionM.Format
+	if err := b.update(ctx, username, &old, postM); err != nil {
+		return err
+	}
+
+	log.C(ctx).Infow("Post revision restored", "username", username, "postID", postID, "revision", revision)
+	return nil
+}
+
+// update 以 username 的身份将博客从 old 修改为 postM，并重新渲染内容。标题、内容或格式有变化时在同一个事务中创建一个新版本。
+// 两个请求同时修改同一篇博客时会创建相同的版本号，或者基于相同的旧状态修改状态，后提交的请求返回 ErrPostConflict
+func (b *PostBusiness) update(ctx context.Context, username string, old, postM *model.PostM) error {
+	postM.ContentHTML = renderContent(postM.Format, postM.Content)
+
+	err := b.ds.TX(ctx, func(ctx context.Context, tx store.IStore) error {
+		if postM.Title != old.Title || postM.Content != old.Content || postM.Format != old.Format {
+			next, err := nextRevision(ctx, tx, old)
+			if err != nil {
+				return err
+			}
+			if err := tx.PostRevisions().Create(ctx, newRevision(postM, next, username)); err != nil {
+				return err
+			}
+		}
+		if err := tx.Posts().Update(ctx, postM); err != nil {
+			return err
+		}
+
+		if postM.Status == old.Status && equalTime(postM.PublishAt, old.PublishAt) {
+			return nil
+		}
+		// 只有状态仍然为读取时的状态时才修改，避免覆盖后台任务或其他请求对状态的修改
+		ok, err := tx.Posts().UpdateStatus(ctx, postM.PostID, old.Status, postM.Status, postM.PublishAt)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errno.ErrPostConflict
+		}
+		return nil
+	})
+	if errors.Is(err, store.ErrDuplicatedKey) {
+		return errno.ErrPostConflict
+	}
+	return err
+}
+
+// nextRevision 返回博客的下一个版本号。old 是修改前的博客，博客还没有任何版本时（启用版本历史之前发布的博客），
+// 先将 old 保存为第 1 个版本，作者为博客的作者，时间为博客最后一次修改的时间
+func nextRevision(ctx context.Context, tx store.IStore, old *model.PostM) (int, error) {
+	latest, err := tx.PostRevisions().Latest(ctx, old.PostID)
+	if err == nil {
+		return latest.Revision + 1, nil
+	}
+	if !errors.Is(err, store.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	baseline := newRevision(old, 1, old.Username)
+	baseline.CreatedAt = old.UpdatedAt
+	if err := tx.PostRevisions().Create(ctx, baseline); err != nil {
+		return 0, err
+	}
+	return 2, nil
+}
+
+// getRevision 查询博客的指定版本，不存在时返回 ErrPostRevisionNotFound
+func (b *PostBusiness) getRevision(ctx context.Context, postID string, revision int) (*model.PostRevisionM, error) {
+	revisionM, err := b.ds.PostRevisions().Get(ctx, postID, revision)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, errno.ErrPostRevisionNotFound
+		}
+		return nil, err
+	}
+	return revisionM, nil
+}
+
+// newRevision 以 username 为作者，将博客当前的标题、内容和格式保存为版本 revision
+func newRevision(postM *model.PostM, revision int, username string) *model.PostRevisionM {
+	hash := sha256.Sum256([]byte(postM.Content))
+	return &model.PostRevisionM{
+		PostID:      postM.PostID,
+		Revision:    revision,
+		Username:    username,
+		Title:       postM.Title,
+		Content:     postM.Content,
+		Format:      postM.Format,
+		ContentHash: hex.EncodeToString(hash[:]),
+	}
+}
+
+func toPostRevision(revisionM *model.PostRevisionM) *v1.PostRevision {
+	return &v1.PostRevision{
+		Revision:    revisionM.Revision,
+		Username:    revisionM.Username,
+		Title:       revisionM.Title,
+		Content:     revisionM.Content,
+		Format:      revisionM.Format,
+		ContentHash: revisionM.ContentHash,
+		CreatedAt:   revisionM.CreatedAt,
+	}
+}
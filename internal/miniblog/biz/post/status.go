@@ -0,0 +1,90 @@
+package post
+
+import (
+	"context"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/known"
+	"miniblog/internal/pkg/log"
+	"miniblog/internal/pkg/model"
+	"time"
+)
+
+// publishBatchSize 是 PublishDue 每次从数据库中读取的定时博客数量
+const publishBatchSize = 100
+
+// PublishDue 发布所有到达发布时间（不晚于 now）的定时博客，返回由本次调用发布的博客数量。
+// 每篇博客的状态检查和修改在一条 UPDATE 语句中完成，多个实例同时调用时每篇博客只会被其中一个实例发布一次
+func (b *PostBusiness) PublishDue(ctx context.Context, now time.Time) (int64, error) {
+	var published int64
+	for {
+		due, err := b.ds.Posts().ListDue(ctx, now, publishBatchSize)
+		if err != nil {
+			return published, err
+		}
+
+		var n int
+		for _, postM := range due {
+			ok, err := b.ds.Posts().Publish(ctx, postM.PostID, now)
+			if err != nil {
+				return published, err
+			}
+			if ok {
+				n++
+				log.C(ctx).Infow("Post published", "username", postM.Username, "postID", postM.PostID, "publishAt", postM.PublishAt)
+			}
+		}
+		published += int64(n)
+
+		// 本批博客都已被其他实例发布时也停止，避免读取到复制延迟的只读副本时重复读取同一批博客
+		if len(due) < publishBatchSize || n == 0 {
+			return published, nil
+		}
+	}
+}
+
+// setStatus 将博客修改为 status 状态，并根据状态设置发布时间：
+// status 为空时，指定了 publishAt 则修改为定时发布，否则不修改状态；只有定时发布可以指定 publishAt，且需要晚于 now；
+// 立即发布时发布时间为 now，已发布的博客保持原来的发布时间；草稿没有发布时间；归档保持原来的发布时间
+func setStatus(postM *model.PostM, status string, publishAt *time.Time, now time.Time) error {
+	if status == "" {
+		if publishAt == nil {
+			return nil
+		}
+		status = known.PostStatusScheduled
+	}
+	if publishAt != nil && status != known.PostStatusScheduled {
+		return errno.ErrInvalidParam.WithMessage("publishAt can only be set for scheduled posts.")
+	}
+
+	switch status {
+	case known.PostStatusScheduled:
+		if publishAt == nil {
+			if postM.Status != known.PostStatusScheduled {
+				return errno.ErrInvalidParam.WithMessage("publishAt is required for scheduled posts.")
+			}
+			publishAt = postM.PublishAt
+		}
+		if !publishAt.After(now) {
+			return errno.ErrInvalidParam.WithMessage("publishAt must be in the future.")
+		}
+	case known.PostStatusPublished:
+		if postM.Status == known.PostStatusPublished {
+			publishAt = postM.PublishAt
+		} else {
+			publishAt = &now
+		}
+	case known.PostStatusArchived:
+		publishAt = postM.PublishAt
+	}
+
+	postM.Status, postM.PublishAt = status, publishAt
+	return nil
+}
+
+// equalTime 判断两个可以为 nil 的时间是否相同
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,166 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"miniblog/internal/miniblog/store"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/known"
+	"miniblog/internal/pkg/log"
+	"miniblog/internal/pkg/model"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	"miniblog/pkg/auth"
+	"strings"
+	"time"
+)
+
+// lastUsedInterval 是更新 API Key 最近使用时间的最小间隔，避免每个请求都写一次数据库
+const lastUsedInterval = time.Minute
+
+// CreateAPIKey 为 username 创建一个 API Key。返回值中的 Key 只会返回这一次，数据库中只保存哈希值
+func (b *UserBusiness) CreateAPIKey(ctx context.Context, username string, req *v1.CreateAPIKeyRequest) (*v1.CreateAPIKeyResponse, error) {
+	userM, err := b.getUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	for _, scope := range scopes {
+		if scope == known.ScopeUsersAdmin && userM.Role != known.RoleAdmin {
+			return nil, errno.ErrPermissionDenied
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errno.ErrInvalidParam.WithMessage("expiresAt must be in the future.")
+	}
+
+	key, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	keyM := &model.APIKeyM{
+		Username:  username,
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := b.ds.APIKeys().Create(ctx, keyM); err != nil {
+		// prefix 有 48 位随机数，重复的只能是名称
+		if errors.Is(err, store.ErrDuplicatedKey) {
+			return nil, errno.ErrAPIKeyAlreadyExist
+		}
+		return nil, err
+	}
+
+	log.C(ctx).Infow("API key created", "username", username, "prefix", prefix, "scopes", keyM.Scopes)
+	return &v1.CreateAPIKeyResponse{Key: key, APIKey: *toAPIKey(keyM)}, nil
+}
+
+// ListAPIKeys 返回 username 的所有 API Key，不包含 API Key 本身
+func (b *UserBusiness) ListAPIKeys(ctx context.Context, username string) (*v1.ListAPIKeysResponse, error) {
+	keys, err := b.ds.APIKeys().List(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &v1.ListAPIKeysResponse{APIKeys: make([]*v1.APIKey, 0, len(keys))}
+	for _, keyM := range keys {
+		resp.APIKeys = append(resp.APIKeys, toAPIKey(keyM))
+	}
+	return resp, nil
+}
+
+// RevokeAPIKey 删除 username 前缀为 prefix 的 API Key，删除后立即失效
+func (b *UserBusiness) RevokeAPIKey(ctx context.Context, username, prefix string) error {
+	if err := b.ds.APIKeys().Delete(ctx, username, prefix); err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return errno.ErrAPIKeyNotFound
+		}
+		return err
+	}
+
+	log.C(ctx).Infow("API key revoked", "username", username, "prefix", prefix)
+	return nil
+}
+
+// AuthenticateAPIKey 校验 key 是否有效且被授予了 scope，成功时返回 API Key 所属的用户名。
+// posts:write 同时包含 posts:read
+func (b *UserBusiness) AuthenticateAPIKey(ctx context.Context, key, scope string) (string, error) {
+	prefix, ok := auth.ParseAPIKey(key)
+	if !ok {
+		return "", errno.ErrAPIKeyInvalid
+	}
+
+	keyM, err := b.ds.APIKeys().Get(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return "", errno.ErrAPIKeyInvalid
+		}
+		return "", err
+	}
+
+	now := time.Now()
+	if !auth.CompareAPIKey(keyM.KeyHash, key) || (keyM.ExpiresAt != nil && !keyM.ExpiresAt.After(now)) {
+		return "", errno.ErrAPIKeyInvalid
+	}
+
+	if !hasScope(strings.Split(keyM.Scopes, ","), scope) {
+		return "", errno.ErrInsufficientScope
+	}
+
+	// 最近使用时间只用于展示，更新失败不影响本次请求
+	if keyM.LastUsedAt == nil || now.Sub(*keyM.LastUsedAt) >= lastUsedInterval {
+		if err := b.ds.APIKeys().Touch(ctx, prefix, now); err != nil {
+			log.C(ctx).Errorw("Failed to update last used time of API key", "prefix", prefix, "err", err)
+		}
+	}
+
+	return keyM.Username, nil
+}
+
+// normalizeScopes 校验 scopes 中的每一项都是 known.Scopes 中的值，并去除重复项
+func normalizeScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	ret := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !contains(known.Scopes, scope) {
+			return nil, errno.ErrInvalidParam.WithMessage("scope %q is invalid, must be one of %s.", scope, strings.Join(known.Scopes, ", "))
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			ret = append(ret, scope)
+		}
+	}
+	if len(ret) == 0 {
+		return nil, errno.ErrInvalidParam.WithMessage("scopes must not be empty.")
+	}
+	return ret, nil
+}
+
+// hasScope 判断 granted 是否包含 scope
+func hasScope(granted []string, scope string) bool {
+	for _, s := range granted {
+		if s == scope || (s == known.ScopePostsWrite && scope == known.ScopePostsRead) {
+			return true
+		}
+	}
+	return false
+}
+
+func toAPIKey(keyM *model.APIKeyM) *v1.APIKey {
+	return &v1.APIKey{
+		Name:       keyM.Name,
+		Prefix:     keyM.Prefix,
+		Scopes:     strings.Split(keyM.Scopes, ","),
+		ExpiresAt:  keyM.ExpiresAt,
+		LastUsedAt: keyM.LastUsedAt,
+		CreatedAt:  keyM.CreatedAt,
+	}
+}
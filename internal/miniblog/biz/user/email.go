@@ -0,0 +1,237 @@
+package user
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"miniblog/internal/miniblog/store"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	"miniblog/internal/pkg/model"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	"miniblog/pkg/mail"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 邮件中的 token 的用途
+const (
+	verifyEmailPurpose   = "verify-email"
+	passwordResetPurpose = "password-reset"
+)
+
+// 邮件模板名，对应 templates 目录下的文件名
+const (
+	verifyEmailTemplate   = "verify_email"
+	passwordResetTemplate = "password_reset"
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+// defaultTemplates 是内置的邮件模板，可以通过 MailOptions.Templates 替换
+var defaultTemplates = func() *mail.Templates {
+	t, err := mail.ParseTemplates(templatesFS, "templates/*.tmpl")
+	if err != nil {
+		panic(err)
+	}
+	return t
+}()
+
+// CheckTemplates 检查 t 中是否包含发送验证邮件和重置密码邮件需要的所有模板
+func CheckTemplates(t *mail.Templates) error {
+	for _, name := range []string{verifyEmailTemplate, passwordResetTemplate} {
+		if !t.Has(name) {
+			return fmt.Errorf("template %s.tmpl is missing", name)
+		}
+	}
+	return nil
+}
+
+// MailOptions 包含发送验证邮件和重置密码邮件的配置项
+type MailOptions struct {
+	Sender              mail.Sender
+	Queue               *mail.Queue     // 用于异步发送重置密码邮件，为 nil 时使用 Sender 同步发送
+	Templates           *mail.Templates // 需要包含 verify_email 和 password_reset 两个模板，为 nil 时使用内置模板
+	BaseURL             string          // 邮件中链接的地址前缀，例如 https://blog.example.com
+	VerifyEmailExpire   time.Duration   // 验证邮件中链接的有效期
+	PasswordResetExpire time.Duration   // 重置密码邮件中链接的有效期
+}
+
+// DefaultMailOptions 返回默认的邮件配置：使用内置模板并丢弃所有邮件，验证链接 24 小时内有效，重置密码链接 1 小时内有效
+func DefaultMailOptions() *MailOptions {
+	return &MailOptions{
+		Sender:              mail.NewWriterSender(io.Discard, "miniblog <noreply@localhost>"),
+		Templates:           defaultTemplates,
+		BaseURL:             "http://127.0.0.1:8080",
+		VerifyEmailExpire:   24 * time.Hour,
+		PasswordResetExpire: time.Hour,
+	}
+}
+
+// mailData 是渲染邮件模板时使用的数据
+type mailData struct {
+	Username string
+	Nickname string
+	Email    string
+	Token    string
+	Link     string
+	Expire   time.Duration
+}
+
+// SendVerificationEmail 向 username 的邮箱发送一封包含验证链接的邮件
+func (b *UserBusiness) SendVerificationEmail(ctx context.Context, username string) error {
+	userM, err := b.getUser(ctx, username)
+	if err != nil {
+		return err
+	}
+	if userM.EmailVerified {
+		return errno.ErrEmailAlreadyVerified
+	}
+
+	if err := b.sendMail(ctx, b.mail.Sender, userM, verifyEmailPurpose, fingerprint(userM.Email), b.mail.VerifyEmailExpire); err != nil {
+		log.C(ctx).Errorw("Failed to send verification email", "username", username, "err", err)
+		return errno.ErrSendMail
+	}
+	return nil
+}
+
+// VerifyEmail 校验验证邮件中的 token，通过后将 username 的邮箱标记为已验证。验证成功后 token 随之失效
+func (b *UserBusiness) VerifyEmail(ctx context.Context, username string, req *v1.VerifyEmailRequest) error {
+	name, fp, err := b.tokens.ParsePurpose(req.Token, verifyEmailPurpose)
+	if err != nil || name != username {
+		return errno.ErrOneTimeTokenInvalid
+	}
+
+	userM, err := b.ds.Users().Get(ctx, username)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return errno.ErrOneTimeTokenInvalid
+		}
+		return err
+	}
+	// 邮箱已经验证过，或者发送验证邮件后邮箱发生了变化
+	if userM.EmailVerified || fp != fingerprint(userM.Email) {
+		return errno.ErrOneTimeTokenInvalid
+	}
+
+	// 只在邮箱仍然是 token 对应的邮箱时更新 emailVerified 列，userM 可能是从副本中读到的旧数据
+	verified, err := b.ds.Users().VerifyEmail(ctx, username, userM.Email)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return errno.ErrOneTimeTokenInvalid
+	}
+
+	log.C(ctx).Infow("Email verified", "username", username)
+	return nil
+}
+
+// RequestPasswordReset 向使用 email 的所有用户发送重置密码邮件。
+// 无论邮箱是否存在、邮件是否发送成功都返回成功，并且邮件通过 Queue 在后台发送，避免通过响应内容或响应时间枚举邮箱
+func (b *UserBusiness) RequestPasswordReset(ctx context.Context, req *v1.PasswordResetRequest) error {
+	users, err := b.ds.Users().ListByEmail(ctx, req.Email)
+	if err != nil {
+		return err
+	}
+
+	var sender mail.Sender = b.mail.Sender
+	if b.mail.Queue != nil {
+		sender = b.mail.Queue
+	}
+	for _, userM := range users {
+		if err := b.sendMail(ctx, sender, userM, passwordResetPurpose, fingerprint(userM.Password), b.mail.PasswordResetExpire); err != nil {
+			log.C(ctx).Errorw("Failed to send password reset email", "username", userM.Username, "err", err)
+		}
+	}
+	return nil
+}
+
+// ResetPassword 校验重置密码邮件中的 token，通过后将密码修改为符合密码策略的新密码。
+// token 绑定了签发时的密码密文，密码修改后 token 随之失效，因此每个 token 只能使用一次
+func (b *UserBusiness) ResetPassword(ctx context.Context, req *v1.ConfirmPasswordResetRequest) error {
+	username, fp, err := b.tokens.ParsePurpose(req.Token, passwordResetPurpose)
+	if err != nil {
+		return errno.ErrOneTimeTokenInvalid
+	}
+
+	userM, err := b.ds.Users().Get(ctx, username)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return errno.ErrOneTimeTokenInvalid
+		}
+		return err
+	}
+	if fp != fingerprint(userM.Password) {
+		return errno.ErrOneTimeTokenInvalid
+	}
+
+	if err := b.validatePassword("newPassword", req.NewPassword, userM.Username, userM.Email); err != nil {
+		return err
+	}
+
+	hashed, err := b.hasher.Encrypt(req.NewPassword)
+	if err != nil {
+		return err
+	}
+	reset, err := b.ds.Users().ResetPassword(ctx, userM.Username, userM.Password, hashed)
+	if err != nil {
+		return err
+	}
+	// 同一个 token 被并发使用时，只有一个请求会成功
+	if !reset {
+		return errno.ErrOneTimeTokenInvalid
+	}
+
+	log.C(ctx).Infow("Password reset", "username", userM.Username)
+	return nil
+}
+
+// sendMail 签发用途为 purpose 的 token，并使用对应的模板通过 sender 向 userM 的邮箱发送邮件
+func (b *UserBusiness) sendMail(ctx context.Context, sender mail.Sender, userM *model.UserM, purpose, fp string, expire time.Duration) error {
+	t, err := b.tokens.SignPurpose(userM.Username, purpose, fp, expire)
+	if err != nil {
+		return err
+	}
+
+	var name, link string
+	switch purpose {
+	case verifyEmailPurpose:
+		name = verifyEmailTemplate
+		link = b.link("/verify-email", url.Values{"username": {userM.Username}, "token": {t}})
+	default:
+		name = passwordResetTemplate
+		link = b.link("/reset-password", url.Values{"token": {t}})
+	}
+
+	msg, err := b.mail.Templates.Render(name, userM.Email, &mailData{
+		Username: userM.Username,
+		Nickname: userM.Nickname,
+		Email:    userM.Email,
+		Token:    t,
+		Link:     link,
+		Expire:   expire,
+	})
+	if err != nil {
+		return err
+	}
+
+	return sender.Send(ctx, msg)
+}
+
+// link 返回邮件中指向 BaseURL 下 path 页面的链接
+func (b *UserBusiness) link(path string, query url.Values) string {
+	return strings.TrimSuffix(b.mail.BaseURL, "/") + path + "?" + query.Encode()
+}
+
+// fingerprint 返回 s 的摘要，用于将 token 绑定到签发时的状态，状态变化后 token 失效
+func fingerprint(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,86 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"miniblog/internal/miniblog/store"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	"miniblog/internal/pkg/model"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	"time"
+)
+
+// CreateInvitation 生成一个邀请码，username 为生成邀请码的管理员
+func (b *UserBusiness) CreateInvitation(ctx context.Context, username string, req *v1.CreateInvitationRequest) (*v1.Invitation, error) {
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errno.ErrInvalidParam.WithMessage("expiresAt must be in the future.")
+	}
+
+	maxUses := req.MaxUses
+	if maxUses <= 0 {
+		maxUses = 1
+	}
+
+	code, err := newInvitationCode()
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := &model.InvitationM{Code: code, CreatedBy: username, MaxUses: maxUses, ExpiresAt: req.ExpiresAt}
+	if err := b.ds.Invitations().Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	log.C(ctx).Infow("Invitation created", "createdBy", username, "maxUses", maxUses, "expiresAt", req.ExpiresAt)
+	return toInvitation(invitation), nil
+}
+
+// ListInvitations 返回所有邀请码，包括已过期和已用完的邀请码
+func (b *UserBusiness) ListInvitations(ctx context.Context) (*v1.ListInvitationsResponse, error) {
+	invitations, err := b.ds.Invitations().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &v1.ListInvitationsResponse{Invitations: make([]*v1.Invitation, 0, len(invitations))}
+	for _, invitation := range invitations {
+		resp.Invitations = append(resp.Invitations, toInvitation(invitation))
+	}
+	return resp, nil
+}
+
+// DeleteInvitation 删除邀请码，删除后不能再使用该邀请码注册
+func (b *UserBusiness) DeleteInvitation(ctx context.Context, code string) error {
+	if err := b.ds.Invitations().Delete(ctx, code); err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return errno.ErrInvitationNotFound
+		}
+		return err
+	}
+
+	log.C(ctx).Infow("Invitation deleted", "code", code)
+	return nil
+}
+
+// newInvitationCode 生成一个 16 位的随机邀请码，只包含大写字母和数字，方便手动输入
+func newInvitationCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.EncodeToString(buf), nil
+}
+
+func toInvitation(invitation *model.InvitationM) *v1.Invitation {
+	return &v1.Invitation{
+		Code:      invitation.Code,
+		CreatedBy: invitation.CreatedBy,
+		MaxUses:   invitation.MaxUses,
+		Uses:      invitation.Uses,
+		ExpiresAt: invitation.ExpiresAt,
+		CreatedAt: invitation.CreatedAt,
+	}
+}
@@ -0,0 +1,153 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"miniblog/internal/miniblog/store"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	"miniblog/internal/pkg/model"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	"miniblog/pkg/db"
+	"time"
+)
+
+// Login 校验用户名和密码，成功后签发 JWT Token。
+//
+//   - 用户不存在时同样会比较一次密码，并返回与密码错误相同的错误，避免通过响应内容和响应时间枚举用户名
+//   - 连续登录失败后，按照 lockout 策略限制再次尝试的时间，达到阈值后临时锁定账户。限制期间即使密码正确也会拒绝登录，
+//     但同样只比较一次假的密文并返回与密码错误相同的错误，避免通过状态码或响应时间判断用户名是否存在
+//   - 校验密码之前先通过 ReserveLoginAttempt 原子地预留一次尝试，按失败处理计数和锁定，校验通过后再清零。
+//     同时发起的多次猜测也会依次计入失败次数并受到限制，而不是都在锁定生效之前完成校验
+//   - 如果数据库中的密文使用的加密算法或参数已过时，会使用当前配置重新加密并保存，用户无需重置密码
+//   - 开启了两步验证的用户通过密码校验后不会签发 token，而是返回一个短期有效的 challenge，需要通过 LoginTwoFactor 完成登录
+func (b *UserBusiness) Login(ctx context.Context, req *v1.LoginRequest) (*v1.LoginResponse, error) {
+	userM, err := b.ds.Users().Get(db.WithPrimary(ctx), req.Username)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			_ = b.hasher.CompareDummy(req.Password)
+			return nil, errno.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	attempts, reserved, err := b.reserveAttempt(ctx, userM.Username, now)
+	if err != nil && !errors.Is(err, store.ErrRecordNotFound) {
+		return nil, err
+	}
+	if !reserved {
+		_ = b.hasher.CompareDummy(req.Password)
+		return nil, errno.ErrInvalidCredentials
+	}
+
+	rehash, err := b.hasher.Compare(userM.Password, req.Password)
+	if err != nil {
+		b.loginFailed(ctx, userM.Username, attempts, now)
+		return nil, errno.ErrInvalidCredentials
+	}
+
+	// 连续失败的次数在完成两步验证后才清零，避免已知密码时通过反复登录绕过验证码的失败次数限制
+	if userM.TOTPEnabled {
+		b.releaseAttempt(ctx, userM)
+		if rehash {
+			b.rehashPassword(ctx, userM, req.Password)
+		}
+
+		return b.twoFactorChallenge(userM)
+	}
+	b.loginSucceeded(ctx, userM, req.Password, rehash)
+
+	t, err := b.tokens.Sign(userM.Username)
+	if err != nil {
+		return nil, errno.ErrSignToken
+	}
+
+	return &v1.LoginResponse{Token: t}, nil
+}
+
+// checkLocked 检查账户当前是否禁止登录。账户锁定期间不会校验密码，避免继续猜测。
+// 返回的错误中包含解锁时间，只能返回给已经证明知道密码的请求，例如两步登录
+func (b *UserBusiness) checkLocked(userM *model.UserM, now time.Time) error {
+	if !b.lockout.Enabled || userM.LockedUntil == nil || !now.Before(*userM.LockedUntil) {
+		return nil
+	}
+
+	if b.lockout.Threshold > 0 && userM.FailedLogins >= b.lockout.Threshold {
+		return errno.ErrAccountLocked.WithMessage("Account is temporarily locked due to too many failed login attempts, please try again after %s.",
+			userM.LockedUntil.Format(time.RFC3339))
+	}
+
+	wait := userM.LockedUntil.Sub(now).Round(time.Second)
+	if wait < time.Second {
+		wait = time.Second
+	}
+	return errno.ErrLoginThrottled.WithMessage("Too many failed login attempts, please try again in %s.", wait)
+}
+
+// reserveAttempt 在校验密码或验证码之前为 username 预留一次尝试，并按照失败的情况计数和锁定。
+// 账户被锁定时 reserved 为 false；lockout 未开启时不做任何记录，总是返回 true
+func (b *UserBusiness) reserveAttempt(ctx context.Context, username string, now time.Time) (attempts int, reserved bool, err error) {
+	if !b.lockout.Enabled {
+		return 0, true, nil
+	}
+
+	return b.ds.Users().ReserveLoginAttempt(ctx, username, now, func(attempts int) time.Time {
+		until, _ := b.lockout.LockedUntil(attempts, now)
+		return until
+	})
+}
+
+// releaseAttempt 撤销 reserveAttempt 预留的尝试，失败次数和锁定时间恢复为 userM 中的值。失败时只记录日志
+func (b *UserBusiness) releaseAttempt(ctx context.Context, userM *model.UserM) {
+	if !b.lockout.Enabled {
+		return
+	}
+
+	if err := b.ds.Users().ReleaseLoginAttempt(ctx, userM.Username, userM.LockedUntil); err != nil {
+		log.C(ctx).Errorw("Failed to release login attempt", "username", userM.Username, "err", err)
+	}
+}
+
+// loginFailed 记录一次登录失败。失败次数和锁定时间已经在 reserveAttempt 中更新，这里只在账户被锁定时记录日志
+func (b *UserBusiness) loginFailed(ctx context.Context, username string, attempts int, now time.Time) {
+	if until, locked := b.lockout.LockedUntil(attempts, now); locked {
+		log.C(ctx).Warnw("Account locked due to too many failed login attempts", "username", username, "attempts", attempts, "until", until)
+	}
+}
+
+// loginSucceeded 清零连续登录失败的次数，并在密文过时的情况下重新加密密码。失败时只记录日志，不影响登录
+func (b *UserBusiness) loginSucceeded(ctx context.Context, userM *model.UserM, password string, rehash bool) {
+	if rehash {
+		b.rehashPassword(ctx, userM, password)
+	}
+
+	// lockout 开启时 reserveAttempt 总是增加了失败次数，需要清零
+	if !b.lockout.Enabled && userM.FailedLogins == 0 && userM.LockedUntil == nil {
+		return
+	}
+	if err := b.ds.Users().ResetFailedLogins(ctx, userM.Username); err != nil {
+		log.C(ctx).Errorw("Failed to reset failed logins", "username", userM.Username, "err", err)
+	}
+}
+
+// rehashPassword 使用当前配置重新加密密码，只在密码没有被修改时保存。失败时只记录日志，不影响登录
+func (b *UserBusiness) rehashPassword(ctx context.Context, userM *model.UserM, password string) {
+	hashed, err := b.hasher.Encrypt(password)
+	if err != nil {
+		log.C(ctx).Errorw("Failed to rehash password", "username", userM.Username, "err", err)
+		return
+	}
+
+	updated, err := b.ds.Users().UpdatePassword(ctx, userM.Username, userM.Password, hashed)
+	if err != nil {
+		log.C(ctx).Errorw("Failed to save rehashed password", "username", userM.Username, "err", err)
+		return
+	}
+	if !updated {
+		log.C(ctx).Infow("Password was changed during login, skip saving rehashed password", "username", userM.Username)
+		return
+	}
+
+	log.C(ctx).Infow("Password rehashed with the current parameters", "username", userM.Username)
+}
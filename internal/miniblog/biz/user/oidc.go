@@ -0,0 +1,226 @@
+package user
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"miniblog/internal/miniblog/store"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/known"
+	"miniblog/internal/pkg/log"
+	"miniblog/internal/pkg/model"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	"miniblog/pkg/oidc"
+	"time"
+)
+
+// oidcPurpose 是 OIDC 登录 session 的用途，session 中保存了 state 和 PKCE code verifier
+const oidcPurpose = "login-oidc"
+
+// OIDCProvider 定义了 OIDC 登录需要的 IdP 操作，由 oidc.Provider 实现
+type OIDCProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	Exchange(ctx context.Context, code, verifier, nonce string) (*oidc.Claims, error)
+}
+
+// OIDCOptions 包含通过外部身份提供方登录的配置项，Provider 为 nil 时不支持 OIDC 登录
+type OIDCOptions struct {
+	Provider      OIDCProvider
+	AutoProvision bool          // 外部身份首次登录且无法绑定已有用户时，使用 IdP 提供的用户名自动创建用户
+	LinkByEmail   bool          // 外部身份首次登录时，自动绑定邮箱相同的唯一用户，要求 IdP 和 miniblog 都已验证该邮箱
+	AdminGroups   []string      // 映射为管理员的用户组，不为空时每次登录都会按照 IdP 返回的用户组同步用户的角色
+	SessionExpire time.Duration // 从发起登录到完成回调的有效期
+}
+
+// DefaultOIDCOptions 返回默认的 OIDC 配置，默认不支持 OIDC 登录
+func DefaultOIDCOptions() *OIDCOptions {
+	return &OIDCOptions{SessionExpire: 10 * time.Minute}
+}
+
+// StartOIDCLogin 发起 OIDC 登录，返回 IdP 的授权地址和保存了 state、PKCE code verifier 的 session
+func (b *UserBusiness) StartOIDCLogin(ctx context.Context) (*v1.OIDCLoginResponse, error) {
+	if b.oidc.Provider == nil {
+		return nil, errno.ErrOIDCDisabled
+	}
+
+	state, err := oidc.NewState()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := oidc.NewVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	// session 由客户端保存并在回调时提交，服务端无需保存登录状态。state 同时用作 ID Token 的 nonce
+	session, err := b.tokens.SignPurpose(state, oidcPurpose, verifier, b.oidc.SessionExpire)
+	if err != nil {
+		return nil, errno.ErrSignToken
+	}
+
+	authURL, err := b.oidc.Provider.AuthCodeURL(ctx, state, state, verifier)
+	if err != nil {
+		log.C(ctx).Errorw("Failed to build OIDC authorization url", "err", err)
+		return nil, errno.ErrOIDCProvider
+	}
+
+	return &v1.OIDCLoginResponse{AuthURL: authURL, Session: session}, nil
+}
+
+// LoginOIDC 使用 IdP 回调的授权码完成 OIDC 登录，成功后签发 JWT Token。
+// 外部身份按照以下顺序对应到 miniblog 用户：已绑定的用户、邮箱相同的唯一用户（LinkByEmail）、自动创建的用户（AutoProvision）。
+// 开启了两步验证的用户与密码登录一样，需要通过 LoginTwoFactor 完成登录
+func (b *UserBusiness) LoginOIDC(ctx context.Context, req *v1.OIDCCallbackRequest) (*v1.LoginResponse, error) {
+	if b.oidc.Provider == nil {
+		return nil, errno.ErrOIDCDisabled
+	}
+
+	state, verifier, err := b.tokens.ParsePurpose(req.Session, oidcPurpose)
+	if err != nil || subtle.ConstantTimeCompare([]byte(state), []byte(req.State)) != 1 {
+		return nil, errno.ErrOIDCLoginFailed
+	}
+
+	claims, err := b.oidc.Provider.Exchange(ctx, req.Code, verifier, state)
+	if err != nil {
+		log.C(ctx).Warnw("Failed to exchange OIDC authorization code", "err", err)
+		return nil, errno.ErrOIDCLoginFailed
+	}
+
+	userM, err := b.oidcUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.syncRole(ctx, userM, claims.Groups); err != nil {
+		return nil, err
+	}
+
+	if userM.TOTPEnabled {
+		return b.twoFactorChallenge(userM)
+	}
+
+	t, err := b.tokens.Sign(userM.Username)
+	if err != nil {
+		return nil, errno.ErrSignToken
+	}
+
+	log.C(ctx).Infow("User logged in with OIDC", "username", userM.Username, "subject", claims.Subject)
+	return &v1.LoginResponse{Token: t}, nil
+}
+
+// oidcUser 返回外部身份对应的用户，首次登录时按照配置绑定已有用户或创建新用户
+func (b *UserBusiness) oidcUser(ctx context.Context, claims *oidc.Claims) (*model.UserM, error) {
+	identity, err := b.ds.Identities().Get(ctx, claims.Issuer, claims.Subject)
+	if err == nil {
+		userM, err := b.ds.Users().Get(ctx, identity.Username)
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, errno.ErrOIDCAccountNotLinked
+		}
+		return userM, err
+	}
+	if !errors.Is(err, store.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// 只有 IdP 和 miniblog 都验证过的邮箱才能用于绑定。IdP 未验证时，任何人都可以在 IdP 中填写他人的邮箱来接管账户；
+	// 本地未验证时，攻击者可以先用他人的邮箱注册，等邮箱的主人首次通过 OIDC 登录时接管其外部身份
+	if b.oidc.LinkByEmail && claims.Email != "" && claims.EmailVerified {
+		users, err := b.ds.Users().ListByEmail(ctx, claims.Email)
+		if err != nil {
+			return nil, err
+		}
+		if len(users) == 1 && users[0].EmailVerified {
+			return users[0], b.linkIdentity(ctx, b.ds, users[0].Username, claims)
+		}
+	}
+
+	if !b.oidc.AutoProvision || claims.Username == "" || !govalidator.IsAlphanumeric(claims.Username) {
+		return nil, errno.ErrOIDCAccountNotLinked
+	}
+
+	nickname := claims.Name
+	if nickname == "" || len(nickname) > 30 {
+		nickname = claims.Username
+	}
+	// 自动创建的用户没有可用的密码，需要时可以通过重置密码设置
+	password, err := oidc.NewVerifier()
+	if err != nil {
+		return nil, err
+	}
+	hashed, err := b.hasher.Encrypt(password)
+	if err != nil {
+		return nil, err
+	}
+	userM := &model.UserM{
+		Username:      claims.Username,
+		Password:      hashed,
+		Nickname:      nickname,
+		Email:         claims.Email,
+		Role:          known.RoleUser,
+		EmailVerified: claims.Email != "" && claims.EmailVerified,
+	}
+
+	err = b.ds.TX(ctx, func(ctx context.Context, tx store.IStore) error {
+		if err := tx.Users().Create(ctx, userM); err != nil {
+			return err
+		}
+		return b.linkIdentity(ctx, tx, userM.Username, claims)
+	})
+	if err != nil {
+		// 同名用户已存在时不能自动绑定，需要该用户通过其他方式绑定
+		if errors.Is(err, store.ErrDuplicatedKey) {
+			return nil, errno.ErrUserAlreadyExist
+		}
+		return nil, err
+	}
+
+	log.C(ctx).Infow("User provisioned from OIDC identity", "username", userM.Username, "subject", claims.Subject)
+	return userM, nil
+}
+
+// linkIdentity 将外部身份绑定到 username
+func (b *UserBusiness) linkIdentity(ctx context.Context, ds store.IStore, username string, claims *oidc.Claims) error {
+	identity := &model.IdentityM{Username: username, Issuer: claims.Issuer, Subject: claims.Subject}
+	if err := ds.Identities().Create(ctx, identity); err != nil {
+		return err
+	}
+
+	log.C(ctx).Infow("OIDC identity linked", "username", username, "issuer", claims.Issuer, "subject", claims.Subject)
+	return nil
+}
+
+// syncRole 按照用户组同步 userM 的角色：属于 AdminGroups 中任一用户组时为管理员，否则为普通用户
+func (b *UserBusiness) syncRole(ctx context.Context, userM *model.UserM, groups []string) error {
+	if len(b.oidc.AdminGroups) == 0 {
+		return nil
+	}
+
+	role := known.RoleUser
+	for _, group := range groups {
+		if contains(b.oidc.AdminGroups, group) {
+			role = known.RoleAdmin
+			break
+		}
+	}
+	if userM.Role == role {
+		return nil
+	}
+
+	log.C(ctx).Infow("Role synchronized from OIDC groups", "username", userM.Username, "from", userM.Role, "to", role)
+	if err := b.ds.Users().SetRole(ctx, userM.Username, role); err != nil {
+		return err
+	}
+	userM.Role = role
+	return nil
+}
+
+// contains 判断 list 中是否包含 s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package user
+
+import (
+	"errors"
+	"miniblog/internal/pkg/errno"
+	"miniblog/pkg/auth"
+)
+
+// ruleErrnos 定义了密码策略规则与错误码之间的映射关系
+var ruleErrnos = map[string]*errno.Errno{
+	auth.RuleMinLength: errno.ErrPasswordTooShort,
+	auth.RuleMaxLength: errno.ErrPasswordTooLong,
+	auth.RuleUpper:     errno.ErrPasswordTooWeak,
+	auth.RuleLower:     errno.ErrPasswordTooWeak,
+	auth.RuleDigit:     errno.ErrPasswordTooWeak,
+	auth.RuleSymbol:    errno.ErrPasswordTooWeak,
+	auth.RuleUserInfo:  errno.ErrPasswordContainsUserInfo,
+	auth.RuleBreached:  errno.ErrPasswordBreached,
+}
+
+// validatePassword 按照密码策略校验请求字段 field 中的密码，返回的错误信息中包含字段名和未通过的规则，
+// 创建用户、修改密码和重置密码都需要经过该校验
+func (b *UserBusiness) validatePassword(field, password string, userInfo ...string) error {
+	err := b.policy.Validate(b.hasher, password, userInfo...)
+	if err == nil {
+		return nil
+	}
+
+	var policyErr *auth.PolicyError
+	if !errors.As(err, &policyErr) {
+		return err
+	}
+
+	e, ok := ruleErrnos[policyErr.Rule]
+	if !ok {
+		e = errno.ErrInvalidParam
+	}
+	return e.WithMessage("%s %s.", field, policyErr.Message)
+}
@@ -0,0 +1,111 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"miniblog/internal/miniblog/store"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	"miniblog/internal/pkg/model"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	"time"
+)
+
+// defaultLimit 是分页查询时每页的默认数量
+const defaultLimit = 20
+
+// Delete 删除 username，同时删除该用户的所有博客并吊销所有 API Key。
+// 用户和博客不会被立即永久删除，在保留期内管理员可以恢复，恢复用户时会一起恢复删除用户时删除的博客
+func (b *UserBusiness) Delete(ctx context.Context, username string) error {
+	// 用户和博客使用相同的删除时间，恢复用户时据此找到一起删除的博客。
+	// 截断到秒，避免不同数据库时间精度不同导致读取的删除时间与写入的不一致
+	now := time.Now().Truncate(time.Second)
+
+	err := b.ds.TX(ctx, func(ctx context.Context, tx store.IStore) error {
+		if err := tx.Users().Delete(ctx, username, now); err != nil {
+			return err
+		}
+		if err := tx.Posts().DeleteByUser(ctx, username, now); err != nil {
+			return err
+		}
+		// 恢复用户后需要重新创建 API Key
+		return tx.APIKeys().DeleteByUser(ctx, username)
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return errno.ErrUserNotFound
+		}
+		return err
+	}
+
+	log.C(ctx).Infow("User deleted", "username", username)
+	return nil
+}
+
+// ListDeleted 按删除时间倒序分页返回已删除但尚未永久删除的用户
+func (b *UserBusiness) ListDeleted(ctx context.Context, req *v1.ListUsersRequest) (*v1.ListUsersResponse, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	count, list, err := b.ds.Users().ListDeleted(ctx, req.Offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &v1.ListUsersResponse{TotalCount: count, Users: make([]*v1.User, 0, len(list))}
+	for _, userM := range list {
+		resp.Users = append(resp.Users, toUser(userM))
+	}
+	return resp, nil
+}
+
+// Restore 恢复已删除的用户，以及删除该用户时一起删除的博客
+func (b *UserBusiness) Restore(ctx context.Context, username string) error {
+	userM, err := b.ds.Users().GetDeleted(ctx, username)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return errno.ErrUserNotFound
+		}
+		return err
+	}
+
+	err = b.ds.TX(ctx, func(ctx context.Context, tx store.IStore) error {
+		if err := tx.Users().Restore(ctx, username); err != nil {
+			return err
+		}
+		return tx.Posts().RestoreByUser(ctx, username, userM.DeletedAt.Time)
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return errno.ErrUserNotFound
+		}
+		return err
+	}
+
+	log.C(ctx).Infow("User restored", "username", username)
+	return nil
+}
+
+// Purge 永久删除在 before 之前删除的用户，以及这些用户的博客、API Key 和绑定的外部身份，返回删除的用户数量
+func (b *UserBusiness) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return b.ds.Users().Purge(ctx, before)
+}
+
+func toUser(userM *model.UserM) *v1.User {
+	user := &v1.User{
+		Username:  userM.Username,
+		Nickname:  userM.Nickname,
+		Email:     userM.Email,
+		Phone:     userM.Phone,
+		Role:      userM.Role,
+		CreatedAt: userM.CreatedAt,
+		UpdatedAt: userM.UpdatedAt,
+	}
+	if userM.DeletedAt.Valid {
+		deletedAt := userM.DeletedAt.Time
+		user.DeletedAt = &deletedAt
+	}
+	return user
+}
@@ -0,0 +1,243 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"miniblog/internal/miniblog/store"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/known"
+	"miniblog/internal/pkg/log"
+	"miniblog/internal/pkg/model"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	"miniblog/pkg/auth"
+	"miniblog/pkg/db"
+	"strings"
+	"time"
+)
+
+// challengePurpose 是两步登录 challenge 的用途，challenge 不能作为 JWT Token 访问接口
+const challengePurpose = "login-2fa"
+
+// LoginTwoFactor 使用 Login 返回的 challenge 和验证码（或恢复码）完成两步登录，成功后签发 JWT Token。
+// 验证码错误与密码错误一样会计入连续登录失败的次数
+func (b *UserBusiness) LoginTwoFactor(ctx context.Context, req *v1.LoginTwoFactorRequest) (*v1.LoginResponse, error) {
+	username, _, err := b.tokens.ParsePurpose(req.Challenge, challengePurpose)
+	if err != nil {
+		return nil, errno.ErrChallengeInvalid
+	}
+
+	// 与 Login 一样从主库读取连续失败的次数和锁定时间
+	userM, err := b.ds.Users().Get(db.WithPrimary(ctx), username)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, errno.ErrChallengeInvalid
+		}
+		return nil, err
+	}
+	if !userM.TOTPEnabled {
+		return nil, errno.ErrChallengeInvalid
+	}
+
+	now := time.Now()
+	if err := b.checkLocked(userM, now); err != nil {
+		return nil, err
+	}
+
+	// 与 Login 一样在校验验证码之前预留一次尝试，同时提交的多个验证码同样受到限制
+	attempts, reserved, err := b.reserveAttempt(ctx, userM.Username, now)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, errno.ErrChallengeInvalid
+		}
+		return nil, err
+	}
+	if !reserved {
+		return nil, errno.ErrLoginThrottled
+	}
+
+	if err := b.verifyTwoFactorCode(ctx, userM, req.Code); err != nil {
+		if errors.Is(err, errno.ErrTwoFactorCodeInvalid) {
+			b.loginFailed(ctx, userM.Username, attempts, now)
+		} else {
+			b.releaseAttempt(ctx, userM)
+		}
+		return nil, err
+	}
+	b.loginSucceeded(ctx, userM, "", false)
+
+	t, err := b.tokens.Sign(userM.Username)
+	if err != nil {
+		return nil, errno.ErrSignToken
+	}
+
+	return &v1.LoginResponse{Token: t}, nil
+}
+
+// twoFactorChallenge 为通过了第一步认证的 userM 签发两步登录的 challenge
+func (b *UserBusiness) twoFactorChallenge(userM *model.UserM) (*v1.LoginResponse, error) {
+	challenge, err := b.tokens.SignPurpose(userM.Username, challengePurpose, "", b.twoFactor.ChallengeExpire)
+	if err != nil {
+		return nil, errno.ErrSignToken
+	}
+	return &v1.LoginResponse{TwoFactorRequired: true, Challenge: challenge}, nil
+}
+
+// EnrollTwoFactor 为 username 生成新的 TOTP 密钥。密钥需要通过 ConfirmTwoFactor 校验一次验证码后才会生效，
+// 重复调用会覆盖尚未确认的密钥
+func (b *UserBusiness) EnrollTwoFactor(ctx context.Context, username string) (*v1.EnrollTwoFactorResponse, error) {
+	userM, err := b.getUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if userM.TOTPEnabled {
+		return nil, errno.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, uri, err := auth.GenerateTOTP(b.twoFactor.Issuer, userM.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	userM.TOTPSecret, userM.TOTPLastCounter, userM.RecoveryCodes = secret, 0, ""
+	if err := b.ds.Users().UpdateTwoFactor(ctx, userM); err != nil {
+		return nil, err
+	}
+
+	return &v1.EnrollTwoFactorResponse{Secret: secret, URI: uri}, nil
+}
+
+// ConfirmTwoFactor 校验验证器应用生成的第一个验证码，通过后开启两步验证并返回一次性恢复码
+func (b *UserBusiness) ConfirmTwoFactor(ctx context.Context, username string, req *v1.TwoFactorCodeRequest) (*v1.ConfirmTwoFactorResponse, error) {
+	userM, err := b.getUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if userM.TOTPEnabled {
+		return nil, errno.ErrTwoFactorAlreadyEnabled
+	}
+	if userM.TOTPSecret == "" {
+		return nil, errno.ErrTwoFactorNotEnrolled
+	}
+
+	counter, ok := auth.ValidateTOTP(req.Code, userM.TOTPSecret, time.Now(), userM.TOTPLastCounter)
+	if !ok {
+		return nil, errno.ErrTwoFactorCodeInvalid
+	}
+
+	codes, hashes, err := auth.GenerateRecoveryCodes(b.twoFactor.RecoveryCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	userM.TOTPEnabled, userM.TOTPLastCounter, userM.RecoveryCodes = true, counter, strings.Join(hashes, ",")
+	if err := b.ds.Users().UpdateTwoFactor(ctx, userM); err != nil {
+		return nil, err
+	}
+
+	log.C(ctx).Infow("Two-factor authentication enabled", "username", username)
+	return &v1.ConfirmTwoFactorResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTwoFactor 校验验证码（或恢复码）后关闭两步验证。require-for-admins 开启时，管理员不能关闭两步验证
+func (b *UserBusiness) DisableTwoFactor(ctx context.Context, username string, req *v1.TwoFactorCodeRequest) error {
+	userM, err := b.getUser(ctx, username)
+	if err != nil {
+		return err
+	}
+	if !userM.TOTPEnabled {
+		return errno.ErrTwoFactorNotEnrolled
+	}
+	if b.twoFactor.RequireForAdmins && userM.Role == known.RoleAdmin {
+		return errno.ErrTwoFactorRequired
+	}
+
+	if err := b.verifyTwoFactorCode(ctx, userM, req.Code); err != nil {
+		return err
+	}
+
+	return b.resetTwoFactor(ctx, userM)
+}
+
+// ResetTwoFactor 无需验证码直接关闭 username 的两步验证，用于用户同时丢失了验证器和恢复码的情况，只在命令行中提供
+func (b *UserBusiness) ResetTwoFactor(ctx context.Context, username string) error {
+	userM, err := b.getUser(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	return b.resetTwoFactor(ctx, userM)
+}
+
+// resetTwoFactor 清除 userM 的 TOTP 密钥和恢复码
+func (b *UserBusiness) resetTwoFactor(ctx context.Context, userM *model.UserM) error {
+	userM.TOTPEnabled, userM.TOTPSecret, userM.TOTPLastCounter, userM.RecoveryCodes = false, "", 0, ""
+	if err := b.ds.Users().UpdateTwoFactor(ctx, userM); err != nil {
+		return err
+	}
+
+	log.C(ctx).Infow("Two-factor authentication disabled", "username", userM.Username)
+	return nil
+}
+
+// verifyTwoFactorCode 校验 code 是否为 userM 当前有效的 TOTP 验证码或未使用的恢复码。
+// 验证码和恢复码都只能使用一次，校验通过的同时会在数据库中将其标记为已使用
+func (b *UserBusiness) verifyTwoFactorCode(ctx context.Context, userM *model.UserM, code string) error {
+	if auth.IsTOTPCode(code) {
+		counter, ok := auth.ValidateTOTP(code, userM.TOTPSecret, time.Now(), userM.TOTPLastCounter)
+		if !ok {
+			return errno.ErrTwoFactorCodeInvalid
+		}
+
+		used, err := b.ds.Users().UseTOTPCounter(ctx, userM.Username, counter)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return errno.ErrTwoFactorCodeInvalid
+		}
+		userM.TOTPLastCounter = counter
+		return nil
+	}
+
+	hash := auth.HashRecoveryCode(code)
+	var remaining []string
+	found := false
+	for _, h := range strings.Split(userM.RecoveryCodes, ",") {
+		if h == "" {
+			continue
+		}
+		if h == hash && !found {
+			found = true
+			continue
+		}
+		remaining = append(remaining, h)
+	}
+	if !found {
+		return errno.ErrTwoFactorCodeInvalid
+	}
+
+	codes := strings.Join(remaining, ",")
+	used, err := b.ds.Users().UpdateRecoveryCodes(ctx, userM.Username, userM.RecoveryCodes, codes)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errno.ErrTwoFactorCodeInvalid
+	}
+	userM.RecoveryCodes = codes
+
+	log.C(ctx).Infow("Recovery code used", "username", userM.Username, "remaining", len(remaining))
+	return nil
+}
+
+// getUser 查询 username 的数据库记录，不存在时返回 errno.ErrUserNotFound
+func (b *UserBusiness) getUser(ctx context.Context, username string) (*model.UserM, error) {
+	userM, err := b.ds.Users().Get(ctx, username)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return nil, errno.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return userM, nil
+}
@@ -1,44 +1,255 @@
 package user
 
+//go:generate mockgen -destination mock_user.go -package user miniblog/internal/miniblog/biz/user UserBiz
+
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"github.com/jinzhu/copier"
 	"miniblog/internal/miniblog/store"
 	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/known"
 	"miniblog/internal/pkg/log"
 	"miniblog/internal/pkg/model"
 	v1 "miniblog/pkg/api/miniblog/v1"
-	"regexp"
+	"miniblog/pkg/auth"
+	"miniblog/pkg/token"
+	"time"
 )
 
 // UserBiz 定义了 user 模块在 biz 层所实现的方法
 type UserBiz interface {
 	Create(ctx context.Context, req *v1.CreateUserRequest) error
+	Login(ctx context.Context, req *v1.LoginRequest) (*v1.LoginResponse, error)
+	ChangePassword(ctx context.Context, username string, req *v1.ChangePasswordRequest) error
+	Unlock(ctx context.Context, username string) error
+	CheckAdmin(ctx context.Context, username string) error
+	SetRole(ctx context.Context, username, role string) error
+	LoginTwoFactor(ctx context.Context, req *v1.LoginTwoFactorRequest) (*v1.LoginResponse, error)
+	EnrollTwoFactor(ctx context.Context, username string) (*v1.EnrollTwoFactorResponse, error)
+	ConfirmTwoFactor(ctx context.Context, username string, req *v1.TwoFactorCodeRequest) (*v1.ConfirmTwoFactorResponse, error)
+	DisableTwoFactor(ctx context.Context, username string, req *v1.TwoFactorCodeRequest) error
+	ResetTwoFactor(ctx context.Context, username string) error
+	SendVerificationEmail(ctx context.Context, username string) error
+	VerifyEmail(ctx context.Context, username string, req *v1.VerifyEmailRequest) error
+	RequestPasswordReset(ctx context.Context, req *v1.PasswordResetRequest) error
+	ResetPassword(ctx context.Context, req *v1.ConfirmPasswordResetRequest) error
+	CreateAPIKey(ctx context.Context, username string, req *v1.CreateAPIKeyRequest) (*v1.CreateAPIKeyResponse, error)
+	ListAPIKeys(ctx context.Context, username string) (*v1.ListAPIKeysResponse, error)
+	RevokeAPIKey(ctx context.Context, username, prefix string) error
+	AuthenticateAPIKey(ctx context.Context, key, scope string) (string, error)
+	StartOIDCLogin(ctx context.Context) (*v1.OIDCLoginResponse, error)
+	LoginOIDC(ctx context.Context, req *v1.OIDCCallbackRequest) (*v1.LoginResponse, error)
+	CreateInvitation(ctx context.Context, username string, req *v1.CreateInvitationRequest) (*v1.Invitation, error)
+	ListInvitations(ctx context.Context) (*v1.ListInvitationsResponse, error)
+	DeleteInvitation(ctx context.Context, code string) error
+	Delete(ctx context.Context, username string) error
+	ListDeleted(ctx context.Context, req *v1.ListUsersRequest) (*v1.ListUsersResponse, error)
+	Restore(ctx context.Context, username string) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Options 包含 user 模块的配置项，为 nil 的字段使用默认值
+type Options struct {
+	Hasher         *auth.Hasher          // 加密和校验密码使用的 Hasher
+	Tokens         *token.Config         // 签发和解析 token 的配置，为 nil 时使用随机密钥
+	PasswordPolicy *auth.PasswordPolicy  // 创建用户、修改和重置密码时使用的密码策略
+	Lockout        *auth.LockoutPolicy   // 登录失败后的限制策略
+	TwoFactor      *auth.TwoFactorPolicy // 两步验证的配置
+	Mail           *MailOptions          // 验证邮件和重置密码邮件的配置
+	OIDC           *OIDCOptions          // 通过外部身份提供方登录的配置
+	Registration   string                // 注册模式，可选值：open,invite-only,closed，为空时为 open
 }
 
 type UserBusiness struct {
-	ds store.IStore
+	ds           store.IStore
+	hasher       *auth.Hasher
+	tokens       *token.Config
+	policy       *auth.PasswordPolicy
+	lockout      *auth.LockoutPolicy
+	twoFactor    *auth.TwoFactorPolicy
+	mail         *MailOptions
+	oidc         *OIDCOptions
+	registration string
 }
 
 // 确保 UserBusiness 实现了 UserBiz 接口
 var _ UserBiz = (*UserBusiness)(nil)
 
-func New(ds store.IStore) *UserBusiness {
-	return &UserBusiness{ds: ds}
+// New 创建 UserBusiness，opts 为 nil 时使用默认配置
+func New(ds store.IStore, opts *Options) *UserBusiness {
+	b := &UserBusiness{ds: ds, hasher: auth.DefaultHasher(), policy: auth.DefaultPasswordPolicy(), lockout: auth.DefaultLockoutPolicy(), twoFactor: auth.DefaultTwoFactorPolicy(), mail: DefaultMailOptions(), oidc: DefaultOIDCOptions(), registration: known.RegistrationOpen}
+	if opts != nil && opts.Hasher != nil {
+		b.hasher = opts.Hasher
+	}
+	if opts != nil && opts.Tokens != nil {
+		b.tokens = opts.Tokens
+	}
+	if opts != nil && opts.PasswordPolicy != nil {
+		b.policy = opts.PasswordPolicy
+	}
+	if opts != nil && opts.Lockout != nil {
+		b.lockout = opts.Lockout
+	}
+	if opts != nil && opts.TwoFactor != nil {
+		b.twoFactor = opts.TwoFactor
+	}
+	if opts != nil && opts.Mail != nil {
+		b.mail = opts.Mail
+	}
+	if opts != nil && opts.OIDC != nil {
+		b.oidc = opts.OIDC
+	}
+	if opts != nil && opts.Registration != "" {
+		b.registration = opts.Registration
+	}
+	if b.tokens == nil {
+		b.tokens = randomTokens()
+	}
+	if b.mail.Templates == nil {
+		mailOpts := *b.mail
+		mailOpts.Templates = defaultTemplates
+		b.mail = &mailOpts
+	}
+	return b
 }
 
+// randomTokens 使用随机密钥创建 token.Config，签发的 token 只能由同一个 UserBusiness 校验。
+// 只在没有通过 Options 指定 Tokens 时使用，服务启动时总是使用 `jwt-secret` 创建的 Tokens
+func randomTokens() *token.Config {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	tokens, _ := token.New(hex.EncodeToString(key), "", 0)
+	return tokens
+}
+
+// Create 创建一个新的用户。registration 为 closed 时禁止注册；为 invite-only 时需要提供有效的邀请码，
+// 邀请码的使用次数与用户在同一个事务中更新，创建用户失败时不会消耗邀请码
 func (b *UserBusiness) Create(ctx context.Context, req *v1.CreateUserRequest) error {
+	switch b.registration {
+	case known.RegistrationClosed:
+		return errno.ErrRegistrationClosed
+	case known.RegistrationInviteOnly:
+		if req.InviteCode == "" {
+			return errno.ErrInvitationInvalid
+		}
+	}
+
+	if err := b.validatePassword("password", req.Password, req.Username, req.Email); err != nil {
+		return err
+	}
+
 	var userModel model.UserM
 	err := copier.Copy(&userModel, req)
 	if err != nil {
 		log.Errorw("copy CreateUserRequest to UserM fail", "err", err)
 	}
+	userModel.Role = known.RoleUser
+	if userModel.Password, err = b.hasher.Encrypt(req.Password); err != nil {
+		return err
+	}
 
-	if err := b.ds.Users().Create(ctx, &userModel); err != nil {
-		if match, _ := regexp.MatchString("Duplicate entry '.*' for key 'username'", err.Error()); match {
+	if b.registration == known.RegistrationInviteOnly {
+		err = b.ds.TX(ctx, func(ctx context.Context, tx store.IStore) error {
+			used, err := tx.Invitations().Use(ctx, req.InviteCode, time.Now())
+			if err != nil {
+				return err
+			}
+			if !used {
+				return errno.ErrInvitationInvalid
+			}
+			return tx.Users().Create(ctx, &userModel)
+		})
+	} else {
+		err = b.ds.Users().Create(ctx, &userModel)
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrDuplicatedKey) {
 			return errno.ErrUserAlreadyExist
 		}
 		return err
 	}
+
+	// 验证邮件发送失败不影响创建用户，用户可以通过 `POST /v1/users/:name/verify-email` 重新发送
+	if err := b.sendMail(ctx, b.mail.Sender, &userModel, verifyEmailPurpose, fingerprint(userModel.Email), b.mail.VerifyEmailExpire); err != nil {
+		log.C(ctx).Errorw("Failed to send verification email", "username", userModel.Username, "err", err)
+	}
+	return nil
+}
+
+// ChangePassword 校验旧密码后，将 username 的密码修改为符合密码策略的新密码
+func (b *UserBusiness) ChangePassword(ctx context.Context, username string, req *v1.ChangePasswordRequest) error {
+	userM, err := b.ds.Users().Get(ctx, username)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return errno.ErrUserNotFound
+		}
+		return err
+	}
+
+	if _, err := b.hasher.Compare(userM.Password, req.OldPassword); err != nil {
+		return errno.ErrPasswordIncorrect
+	}
+
+	if err := b.validatePassword("newPassword", req.NewPassword, userM.Username, userM.Email); err != nil {
+		return err
+	}
+
+	if userM.Password, err = b.hasher.Encrypt(req.NewPassword); err != nil {
+		return err
+	}
+
+	return b.ds.Users().Update(ctx, userM)
+}
+
+// Unlock 清零 username 连续登录失败的次数并解除锁定
+func (b *UserBusiness) Unlock(ctx context.Context, username string) error {
+	if err := b.ds.Users().ResetFailedLogins(ctx, username); err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return errno.ErrUserNotFound
+		}
+		return err
+	}
+
+	log.C(ctx).Infow("Account unlocked", "username", username)
+	return nil
+}
+
+// CheckAdmin 检查 username 是否可以执行管理操作：必须是管理员，且在 require-for-admins 开启时已经开启了两步验证
+func (b *UserBusiness) CheckAdmin(ctx context.Context, username string) error {
+	userM, err := b.ds.Users().Get(ctx, username)
+	if err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return errno.ErrPermissionDenied
+		}
+		return err
+	}
+
+	if userM.Role != known.RoleAdmin {
+		return errno.ErrPermissionDenied
+	}
+	if b.twoFactor.RequireForAdmins && !userM.TOTPEnabled {
+		return errno.ErrTwoFactorRequired
+	}
+	return nil
+}
+
+// SetRole 设置 username 的角色，role 可选值：user,admin
+func (b *UserBusiness) SetRole(ctx context.Context, username, role string) error {
+	if role != known.RoleUser && role != known.RoleAdmin {
+		return errno.ErrInvalidParam.WithMessage("role must be one of %s, %s.", known.RoleUser, known.RoleAdmin)
+	}
+
+	// 只更新 role 列，不会覆盖同时修改的密码、锁定状态等
+	if err := b.ds.Users().SetRole(ctx, username, role); err != nil {
+		if errors.Is(err, store.ErrRecordNotFound) {
+			return errno.ErrUserNotFound
+		}
+		return err
+	}
 	return nil
 }
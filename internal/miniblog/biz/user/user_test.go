@@ -0,0 +1,232 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"miniblog/internal/miniblog/store"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/model"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	"miniblog/pkg/auth"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newCreateUserRequest() *v1.CreateUserRequest {
+	return &v1.CreateUserRequest{
+		Username: "alice",
+		Password: "miniblog1234",
+		Nickname: "alice",
+		Email:    "alice@example.com",
+		Phone:    "13800000000",
+	}
+}
+
+func TestUserBusiness_Create(t *testing.T) {
+	errDB := errors.New("connection refused")
+
+	tests := []struct {
+		name     string
+		storeErr error
+		wantErr  error
+	}{
+		{name: "ok"},
+		{name: "duplicate username", storeErr: store.ErrDuplicatedKey, wantErr: errno.ErrUserAlreadyExist},
+		{name: "store error", storeErr: errDB, wantErr: errDB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			users := store.NewMockUserStore(ctrl)
+			users.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, user *model.UserM) error {
+				if user.Username != "alice" || user.Email != "alice@example.com" {
+					t.Errorf("unexpected user: %+v", user)
+				}
+				if _, err := auth.DefaultHasher().Compare(user.Password, "miniblog1234"); err != nil {
+					t.Errorf("password is not hashed: %v", err)
+				}
+				return tt.storeErr
+			})
+			ds := store.NewMockIStore(ctrl)
+			ds.EXPECT().Users().Return(users).AnyTimes()
+
+			err := New(ds, nil).Create(context.Background(), newCreateUserRequest())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unexpected error: want %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestUserBusiness_CreateRejectsWeakPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		wantErr  error
+	}{
+		{name: "too short", password: "abc123", wantErr: errno.ErrPasswordTooShort},
+		{name: "too long", password: strings.Repeat("a", 65), wantErr: errno.ErrPasswordTooLong},
+		{name: "contains username", password: "alice-in-wonderland", wantErr: errno.ErrPasswordContainsUserInfo},
+		{name: "breached", password: "password123", wantErr: errno.ErrPasswordBreached},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			// 密码不满足策略时不会访问 store
+			ds := store.NewMockIStore(ctrl)
+
+			req := newCreateUserRequest()
+			req.Password = tt.password
+			err := New(ds, nil).Create(context.Background(), req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unexpected error: want %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestUserBusiness_ChangePassword(t *testing.T) {
+	hashed, err := auth.DefaultHasher().Encrypt("miniblog1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		req        *v1.ChangePasswordRequest
+		getErr     error
+		wantUpdate bool
+		wantErr    error
+	}{
+		{name: "ok", req: &v1.ChangePasswordRequest{OldPassword: "miniblog1234", NewPassword: "correct horse battery staple"}, wantUpdate: true},
+		{name: "user not found", req: &v1.ChangePasswordRequest{OldPassword: "miniblog1234", NewPassword: "correct horse battery staple"}, getErr: store.ErrRecordNotFound, wantErr: errno.ErrUserNotFound},
+		{name: "wrong old password", req: &v1.ChangePasswordRequest{OldPassword: "wrong-password", NewPassword: "correct horse battery staple"}, wantErr: errno.ErrPasswordIncorrect},
+		{name: "weak new password", req: &v1.ChangePasswordRequest{OldPassword: "miniblog1234", NewPassword: "alice@example.com1"}, wantErr: errno.ErrPasswordContainsUserInfo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			users := store.NewMockUserStore(ctrl)
+			users.EXPECT().Get(gomock.Any(), "alice").DoAndReturn(func(ctx context.Context, username string) (*model.UserM, error) {
+				if tt.getErr != nil {
+					return nil, tt.getErr
+				}
+				return &model.UserM{ID: 1, Username: "alice", Email: "alice@example.com", Password: hashed}, nil
+			})
+			if tt.wantUpdate {
+				users.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, user *model.UserM) error {
+					if _, err := auth.DefaultHasher().Compare(user.Password, tt.req.NewPassword); err != nil {
+						t.Errorf("password was not updated: %v", err)
+					}
+					return nil
+				})
+			}
+			ds := store.NewMockIStore(ctrl)
+			ds.EXPECT().Users().Return(users).AnyTimes()
+
+			err := New(ds, nil).ChangePassword(context.Background(), "alice", tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unexpected error: want %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestUserBusiness_Login(t *testing.T) {
+	old, err := auth.NewHasher(&auth.HashOptions{Algorithm: auth.AlgorithmBcrypt, BcryptCost: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 使用 cost 为 4 的 bcrypt 加密，与默认参数不同，登录时需要重新加密
+	outdated, _ := old.Encrypt("miniblog1234")
+
+	lockout := &auth.LockoutPolicy{Enabled: true, Threshold: 3, Duration: time.Hour, Delay: time.Second, MaxDelay: time.Minute}
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name          string
+		password      string
+		user          model.UserM
+		getErr        error
+		wantLock      time.Duration // 预留尝试时按失败处理设置的锁定时长，0 表示不会预留尝试
+		wantRehash    bool
+		wantReset     bool // 是否清零连续失败的次数
+		wantRelease   bool // 是否撤销预留的尝试
+		wantChallenge bool
+		wantErr       error
+	}{
+		{name: "ok with rehash", password: "miniblog1234", wantLock: time.Second, wantRehash: true, wantReset: true},
+		{name: "user not found", password: "miniblog1234", getErr: store.ErrRecordNotFound, wantErr: errno.ErrInvalidCredentials},
+		{name: "wrong password", password: "wrong-password", wantLock: time.Second, wantErr: errno.ErrInvalidCredentials},
+		{name: "wrong password reaches threshold", password: "wrong-password", user: model.UserM{FailedLogins: 2}, wantLock: time.Hour, wantErr: errno.ErrInvalidCredentials},
+		// 限制登录期间即使密码正确也返回与密码错误相同的错误，且不会再增加失败次数
+		{name: "throttled", password: "miniblog1234", user: model.UserM{FailedLogins: 1, LockedUntil: &future}, wantErr: errno.ErrInvalidCredentials},
+		{name: "locked", password: "miniblog1234", user: model.UserM{FailedLogins: 3, LockedUntil: &future}, wantErr: errno.ErrInvalidCredentials},
+		// 开启两步验证的用户通过密码校验后只返回 challenge，连续失败的次数在完成两步验证后才清零
+		{name: "two-factor challenge", password: "miniblog1234", user: model.UserM{TOTPEnabled: true, FailedLogins: 1}, wantLock: 2 * time.Second, wantRehash: true, wantRelease: true, wantChallenge: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			users := store.NewMockUserStore(ctrl)
+			users.EXPECT().Get(gomock.Any(), "alice").DoAndReturn(func(ctx context.Context, username string) (*model.UserM, error) {
+				if tt.getErr != nil {
+					return nil, tt.getErr
+				}
+				user := tt.user
+				user.ID, user.Username, user.Password = 1, "alice", outdated
+				return &user, nil
+			})
+			if tt.getErr == nil {
+				users.EXPECT().ReserveLoginAttempt(gomock.Any(), "alice", gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, username string, now time.Time, lockUntil func(int) time.Time) (int, bool, error) {
+						if tt.user.LockedUntil != nil && now.Before(*tt.user.LockedUntil) {
+							return 0, false, nil
+						}
+						attempts := tt.user.FailedLogins + 1
+						if d := lockUntil(attempts).Sub(now); d != tt.wantLock {
+							t.Errorf("unexpected lock duration: want %v, got %v", tt.wantLock, d)
+						}
+						return attempts, true, nil
+					})
+			}
+			if tt.wantRehash {
+				users.EXPECT().UpdatePassword(gomock.Any(), "alice", outdated, gomock.Any()).DoAndReturn(func(ctx context.Context, username, old, password string) (bool, error) {
+					if rehash, err := auth.DefaultHasher().Compare(password, tt.password); err != nil || rehash {
+						t.Errorf("rehashed password is invalid: rehash=%v, err=%v", rehash, err)
+					}
+					return true, nil
+				})
+			}
+			if tt.wantReset {
+				users.EXPECT().ResetFailedLogins(gomock.Any(), "alice").Return(nil)
+			}
+			if tt.wantRelease {
+				users.EXPECT().ReleaseLoginAttempt(gomock.Any(), "alice", tt.user.LockedUntil).Return(nil)
+			}
+			ds := store.NewMockIStore(ctrl)
+			ds.EXPECT().Users().Return(users).AnyTimes()
+
+			resp, err := New(ds, &Options{Lockout: lockout}).Login(context.Background(), &v1.LoginRequest{Username: "alice", Password: tt.password})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unexpected error: want %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && tt.wantChallenge != (resp.Token == "" && resp.TwoFactorRequired && resp.Challenge != "") {
+				t.Fatalf("unexpected login response: %+v", resp)
+			}
+			if err == nil && !tt.wantChallenge && resp.Token == "" {
+				t.Fatal("token is empty")
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package post
+
+import (
+	"context"
+	"github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	pb "miniblog/pkg/proto/miniblog/v1"
+)
+
+// Create 以当前登录用户的身份发布一篇博客
+func (ctrl *PostController) Create(ctx *gin.Context) {
+	log.C(ctx).Infow("Create post function called")
+
+	var req v1.CreatePostRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	resp, err := ctrl.b.Posts().Create(ctx, currentUser(ctx), &req)
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// CreatePost 是 Create 的 gRPC 版本，以当前登录用户的身份发布一篇博客
+func (ctrl *PostController) CreatePost(ctx context.Context, r *pb.CreatePostRequest) (*pb.CreatePostResponse, error) {
+	log.C(ctx).Infow("CreatePost gRPC function called")
+
+	req := v1.CreatePostRequest{Title: r.Title, Content: r.Content, Format: r.Format, Status: r.Status, PublishAt: fromUnix(r.PublishAt)}
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	resp, err := ctrl.b.Posts().Create(ctx, currentUser(ctx), &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreatePostResponse{PostId: resp.PostID}, nil
+}
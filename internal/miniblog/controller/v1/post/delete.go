@@ -0,0 +1,32 @@
+package post
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/log"
+	pb "miniblog/pkg/proto/miniblog/v1"
+)
+
+// Delete 删除当前登录用户的博客。删除的博客在保留期内可以由管理员恢复
+func (ctrl *PostController) Delete(ctx *gin.Context) {
+	log.C(ctx).Infow("Delete post function called")
+
+	if err := ctrl.b.Posts().Delete(ctx, currentUser(ctx), ctx.Param("postID")); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, nil)
+}
+
+// DeletePost 是 Delete 的 gRPC 版本，删除当前登录用户的博客
+func (ctrl *PostController) DeletePost(ctx context.Context, r *pb.DeletePostRequest) (*pb.DeletePostResponse, error) {
+	log.C(ctx).Infow("DeletePost gRPC function called")
+
+	if err := ctrl.b.Posts().Delete(ctx, currentUser(ctx), r.PostId); err != nil {
+		return nil, err
+	}
+
+	return &pb.DeletePostResponse{}, nil
+}
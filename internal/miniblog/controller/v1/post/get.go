@@ -0,0 +1,34 @@
+package post
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/log"
+	pb "miniblog/pkg/proto/miniblog/v1"
+)
+
+// Get 返回博客的详细信息，未发布的博客只有作者可以查看
+func (ctrl *PostController) Get(ctx *gin.Context) {
+	log.C(ctx).Infow("Get post function called")
+
+	resp, err := ctrl.b.Posts().Get(ctx, currentUser(ctx), ctx.Param("postID"))
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// GetPost 是 Get 的 gRPC 版本，返回博客的详细信息
+func (ctrl *PostController) GetPost(ctx context.Context, r *pb.GetPostRequest) (*pb.GetPostResponse, error) {
+	log.C(ctx).Infow("GetPost gRPC function called")
+
+	resp, err := ctrl.b.Posts().Get(ctx, currentUser(ctx), r.PostId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetPostResponse{Post: toPBPost(resp)}, nil
+}
@@ -0,0 +1,53 @@
+package post
+
+import (
+	"context"
+	"github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	pb "miniblog/pkg/proto/miniblog/v1"
+)
+
+// List 按创建时间倒序分页列出已发布的博客和当前登录用户自己的博客，可以通过 username 和 status 参数过滤
+func (ctrl *PostController) List(ctx *gin.Context) {
+	log.C(ctx).Infow("List post function called")
+
+	var req v1.ListPostsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	resp, err := ctrl.b.Posts().List(ctx, currentUser(ctx), &req)
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// ListPosts 是 List 的 gRPC 版本，按创建时间倒序分页列出博客
+func (ctrl *PostController) ListPosts(ctx context.Context, r *pb.ListPostsRequest) (*pb.ListPostsResponse, error) {
+	log.C(ctx).Infow("ListPosts gRPC function called")
+
+	req := v1.ListPostsRequest{Username: r.Username, Status: r.Status, Offset: int(r.Offset), Limit: int(r.Limit)}
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	resp, err := ctrl.b.Posts().List(ctx, currentUser(ctx), &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBListPostsResponse(resp), nil
+}
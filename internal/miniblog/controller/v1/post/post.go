@@ -0,0 +1,78 @@
+package post
+
+import (
+	"context"
+	"miniblog/internal/miniblog/biz"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/known"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	pb "miniblog/pkg/proto/miniblog/v1"
+	"time"
+)
+
+// PostController post 模块在 Controller 层的实现，用来处理博客模块的请求。
+// 同时实现了 gRPC MiniBlogServer 接口中博客相关的方法，与 UserController 一起注册为 MiniBlog 服务
+type PostController struct {
+	b biz.IBiz
+}
+
+// New 创建一个 PostController，biz 层实例由调用方注入
+func New(b biz.IBiz) *PostController {
+	return &PostController{b: b}
+}
+
+// currentUser 返回当前登录用户（由认证中间件或拦截器注入 context）的用户名
+func currentUser(ctx context.Context) string {
+	username, _ := ctx.Value(known.XUsernameKey).(string)
+	return username
+}
+
+// checkAdmin 检查当前登录用户是否为管理员，且按照配置开启了两步验证
+func (ctrl *PostController) checkAdmin(ctx context.Context) error {
+	current := currentUser(ctx)
+	if current == "" {
+		return errno.ErrPermissionDenied
+	}
+
+	return ctrl.b.Users().CheckAdmin(ctx, current)
+}
+
+// toPBPost 将 v1.Post 转换为 gRPC 的 Post，时间转换为 Unix 秒级时间戳
+func toPBPost(post *v1.Post) *pb.Post {
+	ret := &pb.Post{
+		PostId:      post.PostID,
+		Username:    post.Username,
+		Title:       post.Title,
+		Content:     post.Content,
+		Format:      post.Format,
+		ContentHtml: post.ContentHTML,
+		Status:      post.Status,
+		CreatedAt:   post.CreatedAt.Unix(),
+		UpdatedAt:   post.UpdatedAt.Unix(),
+	}
+	if post.PublishAt != nil {
+		ret.PublishAt = post.PublishAt.Unix()
+	}
+	if post.DeletedAt != nil {
+		ret.DeletedAt = post.DeletedAt.Unix()
+	}
+	return ret
+}
+
+// fromUnix 将 gRPC 请求中的 Unix 秒级时间戳转换为时间，0 表示未设置，返回 nil
+func fromUnix(sec int64) *time.Time {
+	if sec == 0 {
+		return nil
+	}
+	t := time.Unix(sec, 0)
+	return &t
+}
+
+// toPBListPostsResponse 将 v1.ListPostsResponse 转换为 gRPC 的 ListPostsResponse
+func toPBListPostsResponse(resp *v1.ListPostsResponse) *pb.ListPostsResponse {
+	posts := make([]*pb.Post, 0, len(resp.Posts))
+	for _, post := range resp.Posts {
+		posts = append(posts, toPBPost(post))
+	}
+	return &pb.ListPostsResponse{TotalCount: resp.TotalCount, Posts: posts}
+}
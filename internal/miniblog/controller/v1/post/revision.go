@@ -0,0 +1,187 @@
+package post
+
+import (
+	"context"
+	"github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	pb "miniblog/pkg/proto/miniblog/v1"
+	"strconv"
+)
+
+// ListRevisions 按版本号倒序分页列出博客的历史版本
+func (ctrl *PostController) ListRevisions(ctx *gin.Context) {
+	log.C(ctx).Infow("List post revisions function called")
+
+	var req v1.ListPostRevisionsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	resp, err := ctrl.b.Posts().ListRevisions(ctx, currentUser(ctx), ctx.Param("postID"), &req)
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// ListPostRevisions 是 ListRevisions 的 gRPC 版本，按版本号倒序分页列出博客的历史版本
+func (ctrl *PostController) ListPostRevisions(ctx context.Context, r *pb.ListPostRevisionsRequest) (*pb.ListPostRevisionsResponse, error) {
+	log.C(ctx).Infow("ListPostRevisions gRPC function called")
+
+	req := v1.ListPostRevisionsRequest{Offset: int(r.Offset), Limit: int(r.Limit)}
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	resp, err := ctrl.b.Posts().ListRevisions(ctx, currentUser(ctx), r.PostId, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*pb.PostRevision, 0, len(resp.Revisions))
+	for _, revision := range resp.Revisions {
+		revisions = append(revisions, toPBPostRevision(revision))
+	}
+	return &pb.ListPostRevisionsResponse{TotalCount: resp.TotalCount, Revisions: revisions}, nil
+}
+
+// GetRevision 返回博客的指定版本，包括该版本的内容
+func (ctrl *PostController) GetRevision(ctx *gin.Context) {
+	log.C(ctx).Infow("Get post revision function called")
+
+	revision, err := revisionParam(ctx)
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	resp, err := ctrl.b.Posts().GetRevision(ctx, currentUser(ctx), ctx.Param("postID"), revision)
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// GetPostRevision 是 GetRevision 的 gRPC 版本，返回博客的指定版本
+func (ctrl *PostController) GetPostRevision(ctx context.Context, r *pb.GetPostRevisionRequest) (*pb.GetPostRevisionResponse, error) {
+	log.C(ctx).Infow("GetPostRevision gRPC function called")
+
+	resp, err := ctrl.b.Posts().GetRevision(ctx, currentUser(ctx), r.PostId, int(r.Revision))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetPostRevisionResponse{Revision: toPBPostRevision(resp)}, nil
+}
+
+// Diff 按行比较博客的两个版本，to 参数为空时与最新版本比较
+func (ctrl *PostController) Diff(ctx *gin.Context) {
+	log.C(ctx).Infow("Diff post revisions function called")
+
+	var req v1.DiffPostRevisionsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	resp, err := ctrl.b.Posts().DiffRevisions(ctx, currentUser(ctx), ctx.Param("postID"), &req)
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// DiffPostRevisions 是 Diff 的 gRPC 版本，按行比较博客的两个版本
+func (ctrl *PostController) DiffPostRevisions(ctx context.Context, r *pb.DiffPostRevisionsRequest) (*pb.DiffPostRevisionsResponse, error) {
+	log.C(ctx).Infow("DiffPostRevisions gRPC function called")
+
+	req := v1.DiffPostRevisionsRequest{From: int(r.From), To: int(r.To)}
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	resp, err := ctrl.b.Posts().DiffRevisions(ctx, currentUser(ctx), r.PostId, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.DiffPostRevisionsResponse{
+		From:      int32(resp.From),
+		To:        int32(resp.To),
+		FromTitle: resp.FromTitle,
+		ToTitle:   resp.ToTitle,
+		Diff:      resp.Diff,
+	}, nil
+}
+
+// RestoreRevision 将当前登录用户的博客回滚到指定版本
+func (ctrl *PostController) RestoreRevision(ctx *gin.Context) {
+	log.C(ctx).Infow("Restore post revision function called")
+
+	revision, err := revisionParam(ctx)
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	if err := ctrl.b.Posts().RestoreRevision(ctx, currentUser(ctx), ctx.Param("postID"), revision); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, nil)
+}
+
+// RestorePostRevision 是 RestoreRevision 的 gRPC 版本，将当前登录用户的博客回滚到指定版本
+func (ctrl *PostController) RestorePostRevision(ctx context.Context, r *pb.RestorePostRevisionRequest) (*pb.RestorePostRevisionResponse, error) {
+	log.C(ctx).Infow("RestorePostRevision gRPC function called")
+
+	if err := ctrl.b.Posts().RestoreRevision(ctx, currentUser(ctx), r.PostId, int(r.Revision)); err != nil {
+		return nil, err
+	}
+
+	return &pb.RestorePostRevisionResponse{}, nil
+}
+
+// revisionParam 解析路径中的版本号，版本号不是正整数时返回 ErrPostRevisionNotFound
+func revisionParam(ctx *gin.Context) (int, error) {
+	revision, err := strconv.Atoi(ctx.Param("rev"))
+	if err != nil || revision <= 0 {
+		return 0, errno.ErrPostRevisionNotFound
+	}
+	return revision, nil
+}
+
+// toPBPostRevision 将 v1.PostRevision 转换为 gRPC 的 PostRevision
+func toPBPostRevision(revision *v1.PostRevision) *pb.PostRevision {
+	return &pb.PostRevision{
+		Revision:    int32(revision.Revision),
+		Username:    revision.Username,
+		Title:       revision.Title,
+		Content:     revision.Content,
+		Format:      revision.Format,
+		ContentHash: revision.ContentHash,
+		CreatedAt:   revision.CreatedAt.Unix(),
+	}
+}
@@ -0,0 +1,94 @@
+package post
+
+import (
+	"context"
+	"github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	pb "miniblog/pkg/proto/miniblog/v1"
+)
+
+// ListDeleted 列出已删除但尚未永久删除的博客，只有管理员可以调用
+func (ctrl *PostController) ListDeleted(ctx *gin.Context) {
+	log.C(ctx).Infow("List deleted posts function called")
+
+	if err := ctrl.checkAdmin(ctx); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	var req v1.ListPostsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	resp, err := ctrl.b.Posts().ListDeleted(ctx, &req)
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// ListDeletedPosts 是 ListDeleted 的 gRPC 版本，列出已删除但尚未永久删除的博客
+func (ctrl *PostController) ListDeletedPosts(ctx context.Context, r *pb.ListPostsRequest) (*pb.ListPostsResponse, error) {
+	log.C(ctx).Infow("ListDeletedPosts gRPC function called")
+
+	if err := ctrl.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	req := v1.ListPostsRequest{Username: r.Username, Offset: int(r.Offset), Limit: int(r.Limit)}
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	resp, err := ctrl.b.Posts().ListDeleted(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBListPostsResponse(resp), nil
+}
+
+// Restore 恢复已删除的博客，只有管理员可以调用
+func (ctrl *PostController) Restore(ctx *gin.Context) {
+	log.C(ctx).Infow("Restore post function called")
+
+	if err := ctrl.checkAdmin(ctx); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	if err := ctrl.b.Posts().Restore(ctx, ctx.Param("postID")); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, nil)
+}
+
+// RestorePost 是 Restore 的 gRPC 版本，恢复已删除的博客
+func (ctrl *PostController) RestorePost(ctx context.Context, r *pb.RestorePostRequest) (*pb.RestorePostResponse, error) {
+	log.C(ctx).Infow("RestorePost gRPC function called")
+
+	if err := ctrl.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := ctrl.b.Posts().Restore(ctx, r.PostId); err != nil {
+		return nil, err
+	}
+
+	return &pb.RestorePostResponse{}, nil
+}
@@ -0,0 +1,65 @@
+package post
+
+import (
+	"context"
+	"github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	pb "miniblog/pkg/proto/miniblog/v1"
+)
+
+// Update 修改当前登录用户的博客
+func (ctrl *PostController) Update(ctx *gin.Context) {
+	log.C(ctx).Infow("Update post function called")
+
+	var req v1.UpdatePostRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	if err := ctrl.b.Posts().Update(ctx, currentUser(ctx), ctx.Param("postID"), &req); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, nil)
+}
+
+// UpdatePost 是 Update 的 gRPC 版本，修改当前登录用户的博客，为空的字段不更新
+func (ctrl *PostController) UpdatePost(ctx context.Context, r *pb.UpdatePostRequest) (*pb.UpdatePostResponse, error) {
+	log.C(ctx).Infow("UpdatePost gRPC function called")
+
+	var req v1.UpdatePostRequest
+	if r.Title != "" {
+		req.Title = &r.Title
+	}
+	if r.Content != "" {
+		req.Content = &r.Content
+	}
+	if r.Format != "" {
+		req.Format = &r.Format
+	}
+	if r.Status != "" {
+		req.Status = &r.Status
+	}
+	req.PublishAt = fromUnix(r.PublishAt)
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	if err := ctrl.b.Posts().Update(ctx, currentUser(ctx), r.PostId, &req); err != nil {
+		return nil, err
+	}
+
+	return &pb.UpdatePostResponse{}, nil
+}
@@ -0,0 +1,155 @@
+package user
+
+import (
+	"context"
+	"github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	pb "miniblog/pkg/proto/miniblog/v1"
+	"time"
+)
+
+// CreateAPIKey 为当前登录用户创建一个 API Key，API Key 只会在响应中返回这一次
+func (ctrl *UserController) CreateAPIKey(ctx *gin.Context) {
+	log.C(ctx).Infow("Create API key function called")
+
+	if err := checkOwner(ctx, ctx.Param("name")); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	var req v1.CreateAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	resp, err := ctrl.b.Users().CreateAPIKey(ctx, ctx.Param("name"), &req)
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// CreateUserAPIKey 是 CreateAPIKey 的 gRPC 版本，为当前登录用户创建一个 API Key
+func (ctrl *UserController) CreateUserAPIKey(ctx context.Context, r *pb.CreateUserAPIKeyRequest) (*pb.CreateUserAPIKeyResponse, error) {
+	log.C(ctx).Infow("CreateUserAPIKey gRPC function called")
+
+	if err := checkOwner(ctx, r.Username); err != nil {
+		return nil, err
+	}
+
+	req := v1.CreateAPIKeyRequest{Name: r.Name, Scopes: r.Scopes}
+	if r.ExpiresAt != 0 {
+		expiresAt := time.Unix(r.ExpiresAt, 0)
+		req.ExpiresAt = &expiresAt
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	resp, err := ctrl.b.Users().CreateAPIKey(ctx, r.Username, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateUserAPIKeyResponse{Key: resp.Key, ApiKey: toPBAPIKey(&resp.APIKey)}, nil
+}
+
+// ListAPIKeys 列出当前登录用户的 API Key，不包含 API Key 本身
+func (ctrl *UserController) ListAPIKeys(ctx *gin.Context) {
+	log.C(ctx).Infow("List API keys function called")
+
+	if err := checkOwner(ctx, ctx.Param("name")); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	resp, err := ctrl.b.Users().ListAPIKeys(ctx, ctx.Param("name"))
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// ListUserAPIKeys 是 ListAPIKeys 的 gRPC 版本，列出当前登录用户的 API Key
+func (ctrl *UserController) ListUserAPIKeys(ctx context.Context, r *pb.ListUserAPIKeysRequest) (*pb.ListUserAPIKeysResponse, error) {
+	log.C(ctx).Infow("ListUserAPIKeys gRPC function called")
+
+	if err := checkOwner(ctx, r.Username); err != nil {
+		return nil, err
+	}
+
+	resp, err := ctrl.b.Users().ListAPIKeys(ctx, r.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*pb.APIKey, 0, len(resp.APIKeys))
+	for _, key := range resp.APIKeys {
+		keys = append(keys, toPBAPIKey(key))
+	}
+	return &pb.ListUserAPIKeysResponse{ApiKeys: keys}, nil
+}
+
+// RevokeAPIKey 删除当前登录用户的一个 API Key，删除后立即失效
+func (ctrl *UserController) RevokeAPIKey(ctx *gin.Context) {
+	log.C(ctx).Infow("Revoke API key function called")
+
+	if err := checkOwner(ctx, ctx.Param("name")); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	if err := ctrl.b.Users().RevokeAPIKey(ctx, ctx.Param("name"), ctx.Param("prefix")); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, nil)
+}
+
+// RevokeUserAPIKey 是 RevokeAPIKey 的 gRPC 版本，删除当前登录用户的一个 API Key
+func (ctrl *UserController) RevokeUserAPIKey(ctx context.Context, r *pb.RevokeUserAPIKeyRequest) (*pb.RevokeUserAPIKeyResponse, error) {
+	log.C(ctx).Infow("RevokeUserAPIKey gRPC function called")
+
+	if err := checkOwner(ctx, r.Username); err != nil {
+		return nil, err
+	}
+
+	if err := ctrl.b.Users().RevokeAPIKey(ctx, r.Username, r.Prefix); err != nil {
+		return nil, err
+	}
+
+	return &pb.RevokeUserAPIKeyResponse{}, nil
+}
+
+// toPBAPIKey 将 v1.APIKey 转换为 gRPC 的 APIKey，时间转换为 Unix 秒级时间戳
+func toPBAPIKey(key *v1.APIKey) *pb.APIKey {
+	ret := &pb.APIKey{
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		CreatedAt: key.CreatedAt.Unix(),
+	}
+	if key.ExpiresAt != nil {
+		ret.ExpiresAt = key.ExpiresAt.Unix()
+	}
+	if key.LastUsedAt != nil {
+		ret.LastUsedAt = key.LastUsedAt.Unix()
+	}
+	return ret
+}
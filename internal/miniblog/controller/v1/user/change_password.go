@@ -0,0 +1,64 @@
+package user
+
+import (
+	"context"
+	"github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	pb "miniblog/pkg/proto/miniblog/v1"
+)
+
+// ChangePassword 修改当前登录用户的密码
+func (ctrl *UserController) ChangePassword(ctx *gin.Context) {
+	log.C(ctx).Infow("Change password function called")
+
+	if err := checkOwner(ctx, ctx.Param("name")); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	var req v1.ChangePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	if err := ctrl.b.Users().ChangePassword(ctx, ctx.Param("name"), &req); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, nil)
+}
+
+// ChangeUserPassword 是 ChangePassword 的 gRPC 版本，修改当前登录用户的密码
+func (ctrl *UserController) ChangeUserPassword(ctx context.Context, r *pb.ChangeUserPasswordRequest) (*pb.ChangeUserPasswordResponse, error) {
+	log.C(ctx).Infow("ChangeUserPassword gRPC function called")
+
+	if err := checkOwner(ctx, r.Username); err != nil {
+		return nil, err
+	}
+
+	req := v1.ChangePasswordRequest{
+		OldPassword: r.OldPassword,
+		NewPassword: r.NewPassword,
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	if err := ctrl.b.Users().ChangePassword(ctx, r.Username, &req); err != nil {
+		return nil, err
+	}
+
+	return &pb.ChangeUserPasswordResponse{}, nil
+}
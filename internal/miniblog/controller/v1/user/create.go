@@ -1,12 +1,14 @@
 package user
 
 import (
+	"context"
 	"github.com/asaskevich/govalidator"
 	"github.com/gin-gonic/gin"
 	"miniblog/internal/pkg/core"
 	"miniblog/internal/pkg/errno"
 	"miniblog/internal/pkg/log"
 	v1 "miniblog/pkg/api/miniblog/v1"
+	pb "miniblog/pkg/proto/miniblog/v1"
 )
 
 // Create 创建一个新的用户
@@ -33,3 +35,27 @@ func (ctrl *UserController) Create(ctx *gin.Context) {
 
 	core.WriteResponse(ctx, nil, nil)
 }
+
+// CreateUser 是 Create 的 gRPC 版本，创建一个新的用户
+func (ctrl *UserController) CreateUser(ctx context.Context, r *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+	log.C(ctx).Infow("CreateUser gRPC function called")
+
+	req := v1.CreateUserRequest{
+		Username:   r.Username,
+		Password:   r.Password,
+		Nickname:   r.Nickname,
+		Email:      r.Email,
+		Phone:      r.Phone,
+		InviteCode: r.InviteCode,
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.SetMessage(err.Error())
+	}
+
+	if err := ctrl.b.Users().Create(ctx, &req); err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateUserResponse{}, nil
+}
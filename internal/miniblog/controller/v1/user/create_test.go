@@ -0,0 +1,125 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+	"miniblog/internal/miniblog/biz"
+	userbiz "miniblog/internal/miniblog/biz/user"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	pb "miniblog/pkg/proto/miniblog/v1"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newCreateUserRequest() *v1.CreateUserRequest {
+	return &v1.CreateUserRequest{
+		Username: "alice",
+		Password: "miniblog1234",
+		Nickname: "alice",
+		Email:    "alice@example.com",
+		Phone:    "13800000000",
+	}
+}
+
+// newController 创建一个使用 mock biz 的 UserController
+func newController(t *testing.T) (*UserController, *userbiz.MockUserBiz) {
+	ctrl := gomock.NewController(t)
+
+	users := userbiz.NewMockUserBiz(ctrl)
+	b := biz.NewMockIBiz(ctrl)
+	b.EXPECT().Users().Return(users).AnyTimes()
+
+	return New(b), users
+}
+
+func TestUserController_Create(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	invalid := newCreateUserRequest()
+	invalid.Phone = "123"
+
+	tests := []struct {
+		name     string
+		body     any
+		bizCalls int
+		bizErr   error
+		wantHTTP int
+		wantCode string
+	}{
+		{name: "ok", body: newCreateUserRequest(), bizCalls: 1, wantHTTP: http.StatusOK},
+		{name: "bind error", body: "{", wantHTTP: errno.ErrBind.HTTP, wantCode: errno.ErrBind.Code},
+		{name: "invalid phone", body: invalid, wantHTTP: errno.ErrInvalidParam.HTTP, wantCode: errno.ErrInvalidParam.Code},
+		{name: "user already exist", body: newCreateUserRequest(), bizCalls: 1, bizErr: errno.ErrUserAlreadyExist, wantHTTP: 400, wantCode: errno.ErrUserAlreadyExist.Code},
+		{name: "unknown error", body: newCreateUserRequest(), bizCalls: 1, bizErr: errors.New("boom"), wantHTTP: 500, wantCode: errno.InternalServerError.Code},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, users := newController(t)
+			users.EXPECT().Create(gomock.Any(), gomock.Any()).Return(tt.bizErr).Times(tt.bizCalls)
+
+			var body []byte
+			if s, ok := tt.body.(string); ok {
+				body = []byte(s)
+			} else {
+				body, _ = json.Marshal(tt.body)
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/v1/users", bytes.NewReader(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			ctrl.Create(c)
+
+			if w.Code != tt.wantHTTP {
+				t.Fatalf("unexpected http status: want %d, got %d, body: %s", tt.wantHTTP, w.Code, w.Body.String())
+			}
+			if tt.wantCode != "" {
+				var resp core.ErrResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Fatalf("unexpected error code: want %q, got %q", tt.wantCode, resp.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestUserController_CreateUser(t *testing.T) {
+	ctrl, users := newController(t)
+	users.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req *v1.CreateUserRequest) error {
+		if *req != *newCreateUserRequest() {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		return nil
+	})
+
+	r := newCreateUserRequest()
+	_, err := ctrl.CreateUser(context.Background(), &pb.CreateUserRequest{
+		Username: r.Username,
+		Password: r.Password,
+		Nickname: r.Nickname,
+		Email:    r.Email,
+		Phone:    r.Phone,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 参数校验失败时不会调用 biz 层
+	_, err = ctrl.CreateUser(context.Background(), &pb.CreateUserRequest{Username: "alice"})
+	var e *errno.Errno
+	if !errors.As(err, &e) || e.Code != errno.ErrInvalidParam.Code {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package user
+
+import (
+	"context"
+	"github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/known"
+	"miniblog/internal/pkg/log"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	pb "miniblog/pkg/proto/miniblog/v1"
+	"time"
+)
+
+// CreateInvitation 生成一个邀请码，只有管理员可以调用
+func (ctrl *UserController) CreateInvitation(ctx *gin.Context) {
+	log.C(ctx).Infow("Create invitation function called")
+
+	if err := ctrl.checkAdmin(ctx); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	var req v1.CreateInvitationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	resp, err := ctrl.b.Users().CreateInvitation(ctx, ctx.GetString(known.XUsernameKey), &req)
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// CreateUserInvitation 是 CreateInvitation 的 gRPC 版本，生成一个邀请码
+func (ctrl *UserController) CreateUserInvitation(ctx context.Context, r *pb.CreateUserInvitationRequest) (*pb.CreateUserInvitationResponse, error) {
+	log.C(ctx).Infow("CreateUserInvitation gRPC function called")
+
+	if err := ctrl.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	req := v1.CreateInvitationRequest{MaxUses: int(r.MaxUses)}
+	if r.ExpiresAt != 0 {
+		expiresAt := time.Unix(r.ExpiresAt, 0)
+		req.ExpiresAt = &expiresAt
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	current, _ := ctx.Value(known.XUsernameKey).(string)
+	resp, err := ctrl.b.Users().CreateInvitation(ctx, current, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateUserInvitationResponse{Invitation: toPBInvitation(resp)}, nil
+}
+
+// ListInvitations 列出所有邀请码，只有管理员可以调用
+func (ctrl *UserController) ListInvitations(ctx *gin.Context) {
+	log.C(ctx).Infow("List invitations function called")
+
+	if err := ctrl.checkAdmin(ctx); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	resp, err := ctrl.b.Users().ListInvitations(ctx)
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// ListUserInvitations 是 ListInvitations 的 gRPC 版本，列出所有邀请码
+func (ctrl *UserController) ListUserInvitations(ctx context.Context, r *pb.ListUserInvitationsRequest) (*pb.ListUserInvitationsResponse, error) {
+	log.C(ctx).Infow("ListUserInvitations gRPC function called")
+
+	if err := ctrl.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := ctrl.b.Users().ListInvitations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := make([]*pb.Invitation, 0, len(resp.Invitations))
+	for _, invitation := range resp.Invitations {
+		invitations = append(invitations, toPBInvitation(invitation))
+	}
+	return &pb.ListUserInvitationsResponse{Invitations: invitations}, nil
+}
+
+// DeleteInvitation 删除一个邀请码，只有管理员可以调用
+func (ctrl *UserController) DeleteInvitation(ctx *gin.Context) {
+	log.C(ctx).Infow("Delete invitation function called")
+
+	if err := ctrl.checkAdmin(ctx); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	if err := ctrl.b.Users().DeleteInvitation(ctx, ctx.Param("code")); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, nil)
+}
+
+// DeleteUserInvitation 是 DeleteInvitation 的 gRPC 版本，删除一个邀请码
+func (ctrl *UserController) DeleteUserInvitation(ctx context.Context, r *pb.DeleteUserInvitationRequest) (*pb.DeleteUserInvitationResponse, error) {
+	log.C(ctx).Infow("DeleteUserInvitation gRPC function called")
+
+	if err := ctrl.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := ctrl.b.Users().DeleteInvitation(ctx, r.Code); err != nil {
+		return nil, err
+	}
+
+	return &pb.DeleteUserInvitationResponse{}, nil
+}
+
+// toPBInvitation 将 v1.Invitation 转换为 gRPC 的 Invitation，时间转换为 Unix 秒级时间戳
+func toPBInvitation(invitation *v1.Invitation) *pb.Invitation {
+	ret := &pb.Invitation{
+		Code:      invitation.Code,
+		CreatedBy: invitation.CreatedBy,
+		MaxUses:   int32(invitation.MaxUses),
+		Uses:      int32(invitation.Uses),
+		CreatedAt: invitation.CreatedAt.Unix(),
+	}
+	if invitation.ExpiresAt != nil {
+		ret.ExpiresAt = invitation.ExpiresAt.Unix()
+	}
+	return ret
+}
@@ -0,0 +1,57 @@
+package user
+
+import (
+	"context"
+	"github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	pb "miniblog/pkg/proto/miniblog/v1"
+)
+
+// Login 登录 miniblog 并返回一个 JWT Token。开启了两步验证的用户返回 challenge，需要调用 LoginTwoFactor 完成登录
+func (ctrl *UserController) Login(ctx *gin.Context) {
+	log.C(ctx).Infow("Login function called")
+
+	var req v1.LoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	resp, err := ctrl.b.Users().Login(ctx, &req)
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// LoginUser 是 Login 的 gRPC 版本，登录 miniblog 并返回一个 JWT Token，开启了两步验证的用户返回 challenge
+func (ctrl *UserController) LoginUser(ctx context.Context, r *pb.LoginUserRequest) (*pb.LoginUserResponse, error) {
+	log.C(ctx).Infow("LoginUser gRPC function called")
+
+	req := v1.LoginRequest{
+		Username: r.Username,
+		Password: r.Password,
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	resp, err := ctrl.b.Users().Login(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.LoginUserResponse{Token: resp.Token, TwoFactorRequired: resp.TwoFactorRequired, Challenge: resp.Challenge}, nil
+}
@@ -0,0 +1,83 @@
+package user
+
+import (
+	"context"
+	"github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	pb "miniblog/pkg/proto/miniblog/v1"
+)
+
+// StartOIDCLogin 发起 OIDC 登录，返回 IdP 的授权地址和需要在回调时提交的 session
+func (ctrl *UserController) StartOIDCLogin(ctx *gin.Context) {
+	log.C(ctx).Infow("Start OIDC login function called")
+
+	resp, err := ctrl.b.Users().StartOIDCLogin(ctx)
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// StartUserOIDCLogin 是 StartOIDCLogin 的 gRPC 版本，发起 OIDC 登录
+func (ctrl *UserController) StartUserOIDCLogin(ctx context.Context, r *pb.StartUserOIDCLoginRequest) (*pb.StartUserOIDCLoginResponse, error) {
+	log.C(ctx).Infow("StartUserOIDCLogin gRPC function called")
+
+	resp, err := ctrl.b.Users().StartOIDCLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.StartUserOIDCLoginResponse{AuthUrl: resp.AuthURL, Session: resp.Session}, nil
+}
+
+// LoginOIDC 使用 IdP 回调的授权码完成 OIDC 登录并返回一个 JWT Token，开启了两步验证的用户返回 challenge
+func (ctrl *UserController) LoginOIDC(ctx *gin.Context) {
+	log.C(ctx).Infow("Login OIDC function called")
+
+	var req v1.OIDCCallbackRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	resp, err := ctrl.b.Users().LoginOIDC(ctx, &req)
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// LoginUserOIDC 是 LoginOIDC 的 gRPC 版本，完成 OIDC 登录并返回一个 JWT Token
+func (ctrl *UserController) LoginUserOIDC(ctx context.Context, r *pb.LoginUserOIDCRequest) (*pb.LoginUserResponse, error) {
+	log.C(ctx).Infow("LoginUserOIDC gRPC function called")
+
+	req := v1.OIDCCallbackRequest{
+		Code:    r.Code,
+		State:   r.State,
+		Session: r.Session,
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	resp, err := ctrl.b.Users().LoginOIDC(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.LoginUserResponse{Token: resp.Token, TwoFactorRequired: resp.TwoFactorRequired, Challenge: resp.Challenge}, nil
+}
@@ -0,0 +1,94 @@
+package user
+
+import (
+	"context"
+	"github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	pb "miniblog/pkg/proto/miniblog/v1"
+)
+
+// PasswordReset 向使用该邮箱的用户发送重置密码邮件。无论邮箱是否存在都返回成功
+func (ctrl *UserController) PasswordReset(ctx *gin.Context) {
+	log.C(ctx).Infow("Password reset function called")
+
+	var req v1.PasswordResetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	if err := ctrl.b.Users().RequestPasswordReset(ctx, &req); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, nil)
+}
+
+// ResetUserPassword 是 PasswordReset 的 gRPC 版本，向使用该邮箱的用户发送重置密码邮件
+func (ctrl *UserController) ResetUserPassword(ctx context.Context, r *pb.ResetUserPasswordRequest) (*pb.ResetUserPasswordResponse, error) {
+	log.C(ctx).Infow("ResetUserPassword gRPC function called")
+
+	req := v1.PasswordResetRequest{Email: r.Email}
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	if err := ctrl.b.Users().RequestPasswordReset(ctx, &req); err != nil {
+		return nil, err
+	}
+
+	return &pb.ResetUserPasswordResponse{}, nil
+}
+
+// ConfirmPasswordReset 校验重置密码邮件中的 token，通过后设置新密码
+func (ctrl *UserController) ConfirmPasswordReset(ctx *gin.Context) {
+	log.C(ctx).Infow("Confirm password reset function called")
+
+	var req v1.ConfirmPasswordResetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	if err := ctrl.b.Users().ResetPassword(ctx, &req); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, nil)
+}
+
+// ConfirmUserPasswordReset 是 ConfirmPasswordReset 的 gRPC 版本，校验重置密码邮件中的 token 并设置新密码
+func (ctrl *UserController) ConfirmUserPasswordReset(ctx context.Context, r *pb.ConfirmUserPasswordResetRequest) (*pb.ConfirmUserPasswordResetResponse, error) {
+	log.C(ctx).Infow("ConfirmUserPasswordReset gRPC function called")
+
+	req := v1.ConfirmPasswordResetRequest{
+		Token:       r.Token,
+		NewPassword: r.NewPassword,
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	if err := ctrl.b.Users().ResetPassword(ctx, &req); err != nil {
+		return nil, err
+	}
+
+	return &pb.ConfirmUserPasswordResetResponse{}, nil
+}
@@ -0,0 +1,147 @@
+package user
+
+import (
+	"context"
+	"github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	pb "miniblog/pkg/proto/miniblog/v1"
+)
+
+// Delete 删除用户及其所有博客，用户本人或管理员可以调用。删除的用户在保留期内可以由管理员恢复
+func (ctrl *UserController) Delete(ctx *gin.Context) {
+	log.C(ctx).Infow("Delete user function called")
+
+	if err := ctrl.checkOwnerOrAdmin(ctx, ctx.Param("name")); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	if err := ctrl.b.Users().Delete(ctx, ctx.Param("name")); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, nil)
+}
+
+// DeleteUser 是 Delete 的 gRPC 版本，删除用户及其所有博客
+func (ctrl *UserController) DeleteUser(ctx context.Context, r *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	log.C(ctx).Infow("DeleteUser gRPC function called")
+
+	if err := ctrl.checkOwnerOrAdmin(ctx, r.Username); err != nil {
+		return nil, err
+	}
+
+	if err := ctrl.b.Users().Delete(ctx, r.Username); err != nil {
+		return nil, err
+	}
+
+	return &pb.DeleteUserResponse{}, nil
+}
+
+// ListDeleted 列出已删除但尚未永久删除的用户，只有管理员可以调用
+func (ctrl *UserController) ListDeleted(ctx *gin.Context) {
+	log.C(ctx).Infow("List deleted users function called")
+
+	if err := ctrl.checkAdmin(ctx); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	var req v1.ListUsersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	resp, err := ctrl.b.Users().ListDeleted(ctx, &req)
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// ListDeletedUsers 是 ListDeleted 的 gRPC 版本，列出已删除但尚未永久删除的用户
+func (ctrl *UserController) ListDeletedUsers(ctx context.Context, r *pb.ListDeletedUsersRequest) (*pb.ListDeletedUsersResponse, error) {
+	log.C(ctx).Infow("ListDeletedUsers gRPC function called")
+
+	if err := ctrl.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	req := v1.ListUsersRequest{Offset: int(r.Offset), Limit: int(r.Limit)}
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	resp, err := ctrl.b.Users().ListDeleted(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*pb.User, 0, len(resp.Users))
+	for _, user := range resp.Users {
+		users = append(users, toPBUser(user))
+	}
+	return &pb.ListDeletedUsersResponse{TotalCount: resp.TotalCount, Users: users}, nil
+}
+
+// Restore 恢复已删除的用户，以及删除该用户时一起删除的博客，只有管理员可以调用
+func (ctrl *UserController) Restore(ctx *gin.Context) {
+	log.C(ctx).Infow("Restore user function called")
+
+	if err := ctrl.checkAdmin(ctx); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	if err := ctrl.b.Users().Restore(ctx, ctx.Param("name")); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, nil)
+}
+
+// RestoreUser 是 Restore 的 gRPC 版本，恢复已删除的用户
+func (ctrl *UserController) RestoreUser(ctx context.Context, r *pb.RestoreUserRequest) (*pb.RestoreUserResponse, error) {
+	log.C(ctx).Infow("RestoreUser gRPC function called")
+
+	if err := ctrl.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := ctrl.b.Users().Restore(ctx, r.Username); err != nil {
+		return nil, err
+	}
+
+	return &pb.RestoreUserResponse{}, nil
+}
+
+// toPBUser 将 v1.User 转换为 gRPC 的 User，时间转换为 Unix 秒级时间戳
+func toPBUser(user *v1.User) *pb.User {
+	ret := &pb.User{
+		Username:  user.Username,
+		Nickname:  user.Nickname,
+		Email:     user.Email,
+		Phone:     user.Phone,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt.Unix(),
+		UpdatedAt: user.UpdatedAt.Unix(),
+	}
+	if user.DeletedAt != nil {
+		ret.DeletedAt = user.DeletedAt.Unix()
+	}
+	return ret
+}
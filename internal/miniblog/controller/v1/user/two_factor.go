@@ -0,0 +1,189 @@
+package user
+
+import (
+	"context"
+	"github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	pb "miniblog/pkg/proto/miniblog/v1"
+)
+
+// LoginTwoFactor 使用 Login 返回的 challenge 和验证码（或恢复码）完成两步登录，并返回一个 JWT Token
+func (ctrl *UserController) LoginTwoFactor(ctx *gin.Context) {
+	log.C(ctx).Infow("Login two-factor function called")
+
+	var req v1.LoginTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	resp, err := ctrl.b.Users().LoginTwoFactor(ctx, &req)
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// LoginUserTwoFactor 是 LoginTwoFactor 的 gRPC 版本，完成两步登录并返回一个 JWT Token
+func (ctrl *UserController) LoginUserTwoFactor(ctx context.Context, r *pb.LoginUserTwoFactorRequest) (*pb.LoginUserResponse, error) {
+	log.C(ctx).Infow("LoginUserTwoFactor gRPC function called")
+
+	req := v1.LoginTwoFactorRequest{
+		Challenge: r.Challenge,
+		Code:      r.Code,
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	resp, err := ctrl.b.Users().LoginTwoFactor(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.LoginUserResponse{Token: resp.Token}, nil
+}
+
+// EnrollTwoFactor 为当前登录用户生成新的 TOTP 密钥，返回密钥和 `otpauth://` URI
+func (ctrl *UserController) EnrollTwoFactor(ctx *gin.Context) {
+	log.C(ctx).Infow("Enroll two-factor function called")
+
+	if err := checkOwner(ctx, ctx.Param("name")); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	resp, err := ctrl.b.Users().EnrollTwoFactor(ctx, ctx.Param("name"))
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// EnrollUserTwoFactor 是 EnrollTwoFactor 的 gRPC 版本，为当前登录用户生成新的 TOTP 密钥
+func (ctrl *UserController) EnrollUserTwoFactor(ctx context.Context, r *pb.EnrollUserTwoFactorRequest) (*pb.EnrollUserTwoFactorResponse, error) {
+	log.C(ctx).Infow("EnrollUserTwoFactor gRPC function called")
+
+	if err := checkOwner(ctx, r.Username); err != nil {
+		return nil, err
+	}
+
+	resp, err := ctrl.b.Users().EnrollTwoFactor(ctx, r.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.EnrollUserTwoFactorResponse{Secret: resp.Secret, Uri: resp.URI}, nil
+}
+
+// ConfirmTwoFactor 校验验证器应用生成的第一个验证码，通过后为当前登录用户开启两步验证并返回一次性恢复码
+func (ctrl *UserController) ConfirmTwoFactor(ctx *gin.Context) {
+	log.C(ctx).Infow("Confirm two-factor function called")
+
+	if err := checkOwner(ctx, ctx.Param("name")); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	var req v1.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	resp, err := ctrl.b.Users().ConfirmTwoFactor(ctx, ctx.Param("name"), &req)
+	if err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, resp)
+}
+
+// ConfirmUserTwoFactor 是 ConfirmTwoFactor 的 gRPC 版本，校验第一个验证码并开启两步验证
+func (ctrl *UserController) ConfirmUserTwoFactor(ctx context.Context, r *pb.ConfirmUserTwoFactorRequest) (*pb.ConfirmUserTwoFactorResponse, error) {
+	log.C(ctx).Infow("ConfirmUserTwoFactor gRPC function called")
+
+	if err := checkOwner(ctx, r.Username); err != nil {
+		return nil, err
+	}
+
+	req := v1.TwoFactorCodeRequest{Code: r.Code}
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	resp, err := ctrl.b.Users().ConfirmTwoFactor(ctx, r.Username, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ConfirmUserTwoFactorResponse{RecoveryCodes: resp.RecoveryCodes}, nil
+}
+
+// DisableTwoFactor 校验验证码（或恢复码）后关闭当前登录用户的两步验证
+func (ctrl *UserController) DisableTwoFactor(ctx *gin.Context) {
+	log.C(ctx).Infow("Disable two-factor function called")
+
+	if err := checkOwner(ctx, ctx.Param("name")); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	var req v1.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	if err := ctrl.b.Users().DisableTwoFactor(ctx, ctx.Param("name"), &req); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, nil)
+}
+
+// DisableUserTwoFactor 是 DisableTwoFactor 的 gRPC 版本，校验验证码后关闭两步验证
+func (ctrl *UserController) DisableUserTwoFactor(ctx context.Context, r *pb.DisableUserTwoFactorRequest) (*pb.DisableUserTwoFactorResponse, error) {
+	log.C(ctx).Infow("DisableUserTwoFactor gRPC function called")
+
+	if err := checkOwner(ctx, r.Username); err != nil {
+		return nil, err
+	}
+
+	req := v1.TwoFactorCodeRequest{Code: r.Code}
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	if err := ctrl.b.Users().DisableTwoFactor(ctx, r.Username, &req); err != nil {
+		return nil, err
+	}
+
+	return &pb.DisableUserTwoFactorResponse{}, nil
+}
@@ -0,0 +1,41 @@
+package user
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/log"
+	pb "miniblog/pkg/proto/miniblog/v1"
+)
+
+// Unlock 解除因连续登录失败而被锁定的账户，只有管理员可以调用
+func (ctrl *UserController) Unlock(ctx *gin.Context) {
+	log.C(ctx).Infow("Unlock user function called")
+
+	if err := ctrl.checkAdmin(ctx); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	if err := ctrl.b.Users().Unlock(ctx, ctx.Param("name")); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, nil)
+}
+
+// UnlockUser 是 Unlock 的 gRPC 版本，解除因连续登录失败而被锁定的账户
+func (ctrl *UserController) UnlockUser(ctx context.Context, r *pb.UnlockUserRequest) (*pb.UnlockUserResponse, error) {
+	log.C(ctx).Infow("UnlockUser gRPC function called")
+
+	if err := ctrl.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := ctrl.b.Users().Unlock(ctx, r.Username); err != nil {
+		return nil, err
+	}
+
+	return &pb.UnlockUserResponse{}, nil
+}
@@ -1,15 +1,51 @@
 package user
 
 import (
+	"context"
 	"miniblog/internal/miniblog/biz"
-	"miniblog/internal/miniblog/store"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/known"
+	pb "miniblog/pkg/proto/miniblog/v1"
 )
 
-// UserController user 模块在 Controller 层的实现，用来处理用户模块的请求
+// UserController user 模块在 Controller 层的实现，用来处理用户模块的请求。
+// 同时实现了 gRPC 的 MiniBlogServer 接口，HTTP 和 gRPC 请求共用同一个 biz 层
 type UserController struct {
 	b biz.IBiz
+	pb.UnimplementedMiniBlogServer
 }
 
-func New(ds store.IStore) *UserController {
-	return &UserController{biz.NewBiz(ds)}
+// 确保 UserController 实现了 pb.MiniBlogServer 接口
+var _ pb.MiniBlogServer = (*UserController)(nil)
+
+// New 创建一个 UserController，biz 层实例由调用方注入
+func New(b biz.IBiz) *UserController {
+	return &UserController{b: b}
+}
+
+// checkOwner 检查当前登录用户（由认证中间件或拦截器注入 context）是否为 username 本人
+func checkOwner(ctx context.Context, username string) error {
+	if current, _ := ctx.Value(known.XUsernameKey).(string); current == "" || current != username {
+		return errno.ErrPermissionDenied
+	}
+	return nil
+}
+
+// checkAdmin 检查当前登录用户是否为管理员，且按照配置开启了两步验证
+func (ctrl *UserController) checkAdmin(ctx context.Context) error {
+	current, _ := ctx.Value(known.XUsernameKey).(string)
+	if current == "" {
+		return errno.ErrPermissionDenied
+	}
+
+	return ctrl.b.Users().CheckAdmin(ctx, current)
+}
+
+// checkOwnerOrAdmin 检查当前登录用户是否为 username 本人或者管理员
+func (ctrl *UserController) checkOwnerOrAdmin(ctx context.Context, username string) error {
+	if err := checkOwner(ctx, username); err == nil {
+		return nil
+	}
+
+	return ctrl.checkAdmin(ctx)
 }
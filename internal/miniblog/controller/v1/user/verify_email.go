@@ -0,0 +1,83 @@
+package user
+
+import (
+	"context"
+	"github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	pb "miniblog/pkg/proto/miniblog/v1"
+)
+
+// SendVerificationEmail 向当前登录用户的邮箱发送一封包含验证链接的邮件
+func (ctrl *UserController) SendVerificationEmail(ctx *gin.Context) {
+	log.C(ctx).Infow("Send verification email function called")
+
+	if err := checkOwner(ctx, ctx.Param("name")); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	if err := ctrl.b.Users().SendVerificationEmail(ctx, ctx.Param("name")); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, nil)
+}
+
+// SendUserVerificationEmail 是 SendVerificationEmail 的 gRPC 版本，向当前登录用户的邮箱发送验证邮件
+func (ctrl *UserController) SendUserVerificationEmail(ctx context.Context, r *pb.SendUserVerificationEmailRequest) (*pb.SendUserVerificationEmailResponse, error) {
+	log.C(ctx).Infow("SendUserVerificationEmail gRPC function called")
+
+	if err := checkOwner(ctx, r.Username); err != nil {
+		return nil, err
+	}
+
+	if err := ctrl.b.Users().SendVerificationEmail(ctx, r.Username); err != nil {
+		return nil, err
+	}
+
+	return &pb.SendUserVerificationEmailResponse{}, nil
+}
+
+// VerifyEmail 校验验证邮件中的 token，通过后将用户的邮箱标记为已验证。token 本身即为凭证，无需登录
+func (ctrl *UserController) VerifyEmail(ctx *gin.Context) {
+	log.C(ctx).Infow("Verify email function called")
+
+	var req v1.VerifyEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		core.WriteResponse(ctx, errno.ErrBind, nil)
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		core.WriteResponse(ctx, errno.ErrInvalidParam.WithMessage("%s", err), nil)
+		return
+	}
+
+	if err := ctrl.b.Users().VerifyEmail(ctx, ctx.Param("name"), &req); err != nil {
+		core.WriteResponse(ctx, err, nil)
+		return
+	}
+
+	core.WriteResponse(ctx, nil, nil)
+}
+
+// VerifyUserEmail 是 VerifyEmail 的 gRPC 版本，校验验证邮件中的 token 并将邮箱标记为已验证
+func (ctrl *UserController) VerifyUserEmail(ctx context.Context, r *pb.VerifyUserEmailRequest) (*pb.VerifyUserEmailResponse, error) {
+	log.C(ctx).Infow("VerifyUserEmail gRPC function called")
+
+	req := v1.VerifyEmailRequest{Token: r.Token}
+	if _, err := govalidator.ValidateStruct(req); err != nil {
+		return nil, errno.ErrInvalidParam.WithMessage("%s", err)
+	}
+
+	if err := ctrl.b.Users().VerifyEmail(ctx, r.Username, &req); err != nil {
+		return nil, err
+	}
+
+	return &pb.VerifyUserEmailResponse{}, nil
+}
@@ -0,0 +1,87 @@
+package miniblog
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	"miniblog/internal/miniblog/controller/v1/post"
+	"miniblog/internal/miniblog/controller/v1/user"
+	"miniblog/internal/pkg/interceptor"
+	"miniblog/internal/pkg/known"
+	"miniblog/internal/pkg/log"
+	pb "miniblog/pkg/proto/miniblog/v1"
+	"net"
+)
+
+// publicMethods 定义了无需认证即可调用的 gRPC 方法
+var publicMethods = []string{
+	pb.MiniBlog_CreateUser_FullMethodName,
+	pb.MiniBlog_LoginUser_FullMethodName,
+	pb.MiniBlog_LoginUserTwoFactor_FullMethodName,
+	pb.MiniBlog_VerifyUserEmail_FullMethodName,
+	pb.MiniBlog_ResetUserPassword_FullMethodName,
+	pb.MiniBlog_ConfirmUserPasswordReset_FullMethodName,
+	pb.MiniBlog_StartUserOIDCLogin_FullMethodName,
+	pb.MiniBlog_LoginUserOIDC_FullMethodName,
+}
+
+// methodScopes 定义了可以使用 API Key 调用的 gRPC 方法及其要求的 scope，与 HTTP 路由保持一致
+var methodScopes = map[string]string{
+	pb.MiniBlog_UnlockUser_FullMethodName:           known.ScopeUsersAdmin,
+	pb.MiniBlog_CreateUserInvitation_FullMethodName: known.ScopeUsersAdmin,
+	pb.MiniBlog_ListUserInvitations_FullMethodName:  known.ScopeUsersAdmin,
+	pb.MiniBlog_DeleteUserInvitation_FullMethodName: known.ScopeUsersAdmin,
+	pb.MiniBlog_DeleteUser_FullMethodName:           known.ScopeUsersAdmin,
+	pb.MiniBlog_ListDeletedUsers_FullMethodName:     known.ScopeUsersAdmin,
+	pb.MiniBlog_RestoreUser_FullMethodName:          known.ScopeUsersAdmin,
+	pb.MiniBlog_CreatePost_FullMethodName:           known.ScopePostsWrite,
+	pb.MiniBlog_GetPost_FullMethodName:              known.ScopePostsRead,
+	pb.MiniBlog_ListPosts_FullMethodName:            known.ScopePostsRead,
+	pb.MiniBlog_UpdatePost_FullMethodName:           known.ScopePostsWrite,
+	pb.MiniBlog_DeletePost_FullMethodName:           known.ScopePostsWrite,
+	pb.MiniBlog_ListDeletedPosts_FullMethodName:     known.ScopeUsersAdmin,
+	pb.MiniBlog_RestorePost_FullMethodName:          known.ScopeUsersAdmin,
+	pb.MiniBlog_ListPostRevisions_FullMethodName:    known.ScopePostsRead,
+	pb.MiniBlog_GetPostRevision_FullMethodName:      known.ScopePostsRead,
+	pb.MiniBlog_DiffPostRevisions_FullMethodName:    known.ScopePostsRead,
+	pb.MiniBlog_RestorePostRevision_FullMethodName:  known.ScopePostsWrite,
+}
+
+// miniBlogServer 组合了各个模块的 controller，作为一个整体注册为 MiniBlog 服务。
+// UserController 嵌入了 pb.UnimplementedMiniBlogServer，其余 controller 实现的方法会覆盖其中对应的默认实现
+type miniBlogServer struct {
+	*user.UserController
+	*post.PostController
+}
+
+// startGRPCServer 创建并启动 gRPC 服务，gRPC 服务与 HTTP 服务共用 App 中的 store 和 biz 层
+func (a *App) startGRPCServer(addr string) (*grpc.Server, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Errorw("Failed to listen", "err", err)
+		return nil, err
+	}
+
+	// 拦截器的执行顺序与注册顺序一致：先注入 RequestID，再转换错误码，最后做认证
+	interceptors := []grpc.UnaryServerInterceptor{
+		interceptor.RequestID(),
+		interceptor.Errno(),
+		interceptor.Authn(a.tokens, a.biz.Users(), methodScopes, publicMethods...),
+	}
+	if a.cfg.GetBool("db.read-your-writes") {
+		interceptors = append(interceptors, interceptor.ReadYourWrites())
+	}
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+
+	pb.RegisterMiniBlogServer(server, &miniBlogServer{UserController: a.userController, PostController: a.postController})
+	// 注册 reflection 服务，方便使用 grpcurl 等工具调试
+	reflection.Register(server)
+
+	log.Infow("Start to listening the incoming requests on grpc address", "addr", addr)
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			log.Fatalw(err.Error())
+		}
+	}()
+
+	return server, nil
+}
@@ -1,15 +1,25 @@
 package miniblog
 
 import (
+	"context"
 	"fmt"
+	"github.com/gin-gonic/gin"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"miniblog/internal/miniblog/store"
+	"gorm.io/gorm"
+	userbiz "miniblog/internal/miniblog/biz/user"
+	"miniblog/internal/pkg/known"
 	"miniblog/internal/pkg/log"
+	"miniblog/internal/pkg/middleware"
+	"miniblog/pkg/auth"
 	"miniblog/pkg/db"
+	"miniblog/pkg/mail"
+	"miniblog/pkg/oidc"
+	"miniblog/pkg/ratelimit"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 /**
@@ -65,6 +75,9 @@ func initConfig() {
 		log.Errorw("Failed to read viper configuration file", "err", err)
 	}
 
+	// 从文件和环境变量中解析密码等敏感配置，并在日志中对其脱敏
+	cobra.CheckErr(resolveSecrets(viper.GetViper()))
+
 	// 打印 viper 当前使用的配置文件，方便 Debug
 	log.Debugw("Using config file", "file", viper.ConfigFileUsed())
 }
@@ -81,23 +94,306 @@ func logOptions() *log.Options {
 	}
 }
 
-// initStore 读取 db 配置，创建 gorm.DB 实例，并初始化 MiniBlog store 层
-func initStore() error {
-	dbOptions := &db.MySqlOptions{
-		Host:                  viper.GetString("db.host"),
-		Username:              viper.GetString("db.username"),
-		Password:              viper.GetString("db.password"),
-		Database:              viper.GetString("db.database"),
-		MaxIdleConnections:    viper.GetInt("db.max-idle-connections"),
-		MaxOpenConnections:    viper.GetInt("db.max-open-connections"),
-		MaxConnectionLifeTime: viper.GetDuration("db.max-connection-life-time"),
-		LogLevel:              viper.GetInt("db.log-level"),
-	}
-
-	if instance, err := db.NewMySql(dbOptions); err != nil {
-		return err
-	} else {
-		store.NewStore(instance)
+// corsOptions 从 cfg 中读取跨域配置，构建 `*middleware.CorsOptions` 并返回。
+// `allow-origins` 包含 `*` 时不允许同时开启 `allow-credentials`，否则任意站点都可以携带用户凭证访问接口
+func corsOptions(cfg *viper.Viper) (*middleware.CorsOptions, error) {
+	opts := &middleware.CorsOptions{
+		AllowOrigins:     cfg.GetStringSlice("cors.allow-origins"),
+		AllowMethods:     cfg.GetStringSlice("cors.allow-methods"),
+		AllowHeaders:     cfg.GetStringSlice("cors.allow-headers"),
+		ExposeHeaders:    cfg.GetStringSlice("cors.expose-headers"),
+		AllowCredentials: cfg.GetBool("cors.allow-credentials"),
+		MaxAge:           cfg.GetDuration("cors.max-age"),
+	}
+
+	if opts.AllowCredentials {
+		for _, origin := range opts.AllowOrigins {
+			if strings.TrimSpace(origin) == "*" {
+				return nil, fmt.Errorf("invalid cors: allow-origins must list explicit origins when allow-credentials is enabled")
+			}
+		}
+	}
+
+	return opts, nil
+}
+
+// routeTimeouts 从 cfg 中读取按路由配置的请求超时时间
+func routeTimeouts(cfg *viper.Viper) []middleware.RouteTimeout {
+	var routes []middleware.RouteTimeout
+	if err := cfg.UnmarshalKey("request.route-timeouts", &routes); err != nil {
+		log.Errorw("Failed to parse route timeouts", "err", err)
 		return nil
 	}
+	return routes
+}
+
+// newDB 读取 db 配置，根据 db.type 创建对应数据库的 gorm.DB 实例
+func newDB(cfg *viper.Viper) (*gorm.DB, error) {
+	dbType, host := cfg.GetString("db.type"), cfg.GetString("db.host")
+
+	var open func(ctx context.Context) (*gorm.DB, error)
+	switch dbType {
+	case "", db.TypeMySQL:
+		dbType = db.TypeMySQL
+		opts := &db.MySqlOptions{
+			Host:                  host,
+			Username:              cfg.GetString("db.username"),
+			Password:              cfg.GetString("db.password"),
+			Database:              cfg.GetString("db.database"),
+			MaxIdleConnections:    cfg.GetInt("db.max-idle-connections"),
+			MaxOpenConnections:    cfg.GetInt("db.max-open-connections"),
+			MaxConnectionLifeTime: cfg.GetDuration("db.max-connection-life-time"),
+			LogLevel:              cfg.GetInt("db.log-level"),
+			Replicas:              cfg.GetStringSlice("db.replicas"),
+			MaxReplicaLag:         cfg.GetDuration("db.max-replica-lag"),
+			ReplicaCheckInterval:  cfg.GetDuration("db.replica-check-interval"),
+		}
+		open = func(ctx context.Context) (*gorm.DB, error) { return db.NewMySql(ctx, opts) }
+	case db.TypePostgres:
+		opts := &db.PostgresOptions{
+			Host:                  host,
+			Username:              cfg.GetString("db.username"),
+			Password:              cfg.GetString("db.password"),
+			Database:              cfg.GetString("db.database"),
+			SSLMode:               cfg.GetString("db.ssl-mode"),
+			MaxIdleConnections:    cfg.GetInt("db.max-idle-connections"),
+			MaxOpenConnections:    cfg.GetInt("db.max-open-connections"),
+			MaxConnectionLifeTime: cfg.GetDuration("db.max-connection-life-time"),
+			LogLevel:              cfg.GetInt("db.log-level"),
+			Replicas:              cfg.GetStringSlice("db.replicas"),
+			MaxReplicaLag:         cfg.GetDuration("db.max-replica-lag"),
+			ReplicaCheckInterval:  cfg.GetDuration("db.replica-check-interval"),
+		}
+		open = func(ctx context.Context) (*gorm.DB, error) { return db.NewPostgres(ctx, opts) }
+	default:
+		return nil, fmt.Errorf("unsupported database type: %q", dbType)
+	}
+
+	// 指定了 `--wait-for-db` 时不限制重试次数，直到连接成功或者超过最长等待时间
+	maxRetries := cfg.GetInt("db.connect-retries")
+	if cfg.GetBool("db.wait-for-db") {
+		maxRetries = -1
+	}
+	retry := &db.RetryOptions{
+		MaxRetries:     maxRetries,
+		InitialBackoff: cfg.GetDuration("db.connect-backoff"),
+		MaxBackoff:     cfg.GetDuration("db.connect-max-backoff"),
+		MaxWait:        cfg.GetDuration("db.startup-wait"),
+		OnRetry: func(attempt int, err error, backoff time.Duration) {
+			log.Warnw("Failed to connect to database, retrying", "type", dbType, "host", host, "attempt", attempt, "backoff", backoff, "err", err)
+		},
+	}
+
+	log.Infow("Connecting to database", "type", dbType, "host", host, "database", cfg.GetString("db.database"))
+	instance, err := db.Connect(context.Background(), open, retry)
+	if err != nil {
+		return nil, err
+	}
+	log.Infow("Connected to database", "type", dbType, "host", host)
+
+	return instance, nil
+}
+
+// newRateLimiter 根据 ratelimit.backend 配置创建限流后端，目前只内置了内存实现
+func newRateLimiter(cfg *viper.Viper) (ratelimit.Limiter, error) {
+	switch backend := cfg.GetString("ratelimit.backend"); backend {
+	case "", "memory":
+		return ratelimit.NewMemoryLimiter(), nil
+	default:
+		return nil, fmt.Errorf("unsupported rate limit backend: %q", backend)
+	}
+}
+
+// rateLimitMiddlewares 读取 `ratelimit.groups.<group>` 中配置的限流策略，返回需要挂载到该路由分组上的中间件。
+// 按用户名限流的策略需要在认证中间件设置用户名之后才能生效，所以单独通过 users 返回，需要挂载在认证中间件之后，
+// 挂载在不需要认证的路由上时退化为按 IP 限流。authenticated 为 false 表示该分组没有需要认证的路由，此时不允许配置按用户名限流的策略。
+// 策略无法解析或者 limit、period 不是正数时报错，避免配置错误导致登录等接口悄悄失去限流。未开启限流或者该分组没有配置策略时，返回空切片
+func rateLimitMiddlewares(cfg *viper.Viper, limiter ratelimit.Limiter, group string, authenticated bool) (before, users []gin.HandlerFunc, err error) {
+	if !cfg.GetBool("ratelimit.enabled") {
+		return nil, nil, nil
+	}
+
+	var policies []ratelimit.Policy
+	if err := cfg.UnmarshalKey("ratelimit.groups."+group, &policies); err != nil {
+		return nil, nil, fmt.Errorf("invalid ratelimit.groups.%s: %w", group, err)
+	}
+
+	var beforeAuthn, afterAuthn []ratelimit.Policy
+	for _, policy := range policies {
+		if policy.Limit <= 0 || policy.Period <= 0 {
+			return nil, nil, fmt.Errorf("invalid ratelimit.groups.%s: policy %q must have a positive limit and period", group, policy.Name)
+		}
+		if policy.Name == "" {
+			policy.Name = group
+		}
+
+		if policy.Key != ratelimit.KeyUsername {
+			beforeAuthn = append(beforeAuthn, policy)
+			continue
+		}
+		if !authenticated {
+			return nil, nil, fmt.Errorf("invalid ratelimit.groups.%s: policy %q is keyed by username, but the group has no authenticated routes", group, policy.Name)
+		}
+		afterAuthn = append(afterAuthn, policy)
+	}
+
+	if len(beforeAuthn) > 0 {
+		before = []gin.HandlerFunc{middleware.RateLimit(limiter, beforeAuthn...)}
+	}
+	if len(afterAuthn) > 0 {
+		users = []gin.HandlerFunc{middleware.RateLimit(limiter, afterAuthn...)}
+	}
+	return before, users, nil
+}
+
+// passwordPolicy 从 `auth.password-policy` 中读取密码策略，未配置的选项使用默认值
+func passwordPolicy(cfg *viper.Viper) (*auth.PasswordPolicy, error) {
+	policy := auth.DefaultPasswordPolicy()
+	if err := cfg.UnmarshalKey("auth.password-policy", policy); err != nil {
+		return nil, fmt.Errorf("invalid auth.password-policy: %w", err)
+	}
+	if policy.MaxLength > 0 && policy.MinLength > policy.MaxLength {
+		return nil, fmt.Errorf("invalid auth.password-policy: min-length %d is greater than max-length %d", policy.MinLength, policy.MaxLength)
+	}
+
+	return policy, nil
+}
+
+// lockoutPolicy 从 `auth.lockout` 中读取登录失败后的限制策略，未配置的选项使用默认值
+func lockoutPolicy(cfg *viper.Viper) (*auth.LockoutPolicy, error) {
+	policy := auth.DefaultLockoutPolicy()
+	if err := cfg.UnmarshalKey("auth.lockout", policy); err != nil {
+		return nil, fmt.Errorf("invalid auth.lockout: %w", err)
+	}
+	if policy.Enabled && policy.Threshold > 0 && policy.Duration <= 0 {
+		return nil, fmt.Errorf("invalid auth.lockout: duration must be positive when threshold is set")
+	}
+
+	return policy, nil
+}
+
+// twoFactorPolicy 从 `auth.two-factor` 中读取两步验证的配置，未配置的选项使用默认值
+func twoFactorPolicy(cfg *viper.Viper) (*auth.TwoFactorPolicy, error) {
+	policy := auth.DefaultTwoFactorPolicy()
+	if err := cfg.UnmarshalKey("auth.two-factor", policy); err != nil {
+		return nil, fmt.Errorf("invalid auth.two-factor: %w", err)
+	}
+	if policy.ChallengeExpire <= 0 {
+		return nil, fmt.Errorf("invalid auth.two-factor: challenge-expire must be positive")
+	}
+	if policy.RecoveryCodes <= 0 {
+		return nil, fmt.Errorf("invalid auth.two-factor: recovery-codes must be positive")
+	}
+
+	return policy, nil
+}
+
+// mailOptions 读取 `mail` 中的配置，创建发送验证邮件和重置密码邮件使用的 Sender 和模板
+func mailOptions(cfg *viper.Viper) (*userbiz.MailOptions, error) {
+	opts := userbiz.DefaultMailOptions()
+	if v := cfg.GetString("mail.base-url"); v != "" {
+		opts.BaseURL = v
+	}
+	if v := cfg.GetDuration("mail.verify-email-expire"); v > 0 {
+		opts.VerifyEmailExpire = v
+	}
+	if v := cfg.GetDuration("mail.password-reset-expire"); v > 0 {
+		opts.PasswordResetExpire = v
+	}
+
+	from := cfg.GetString("mail.from")
+	if from == "" {
+		from = "miniblog <noreply@localhost>"
+	}
+
+	switch driver := cfg.GetString("mail.driver"); driver {
+	case "", "stdout":
+		opts.Sender = mail.NewWriterSender(os.Stdout, from)
+	case "file":
+		sender, err := mail.NewFileSender(cfg.GetString("mail.dir"), from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mail.dir: %w", err)
+		}
+		opts.Sender = sender
+	case "smtp":
+		sender, err := mail.NewSMTPSender(&mail.SMTPOptions{
+			Host:     cfg.GetString("mail.smtp.host"),
+			Port:     cfg.GetInt("mail.smtp.port"),
+			Username: cfg.GetString("mail.smtp.username"),
+			Password: cfg.GetString("mail.smtp.password"),
+			TLS:      cfg.GetString("mail.smtp.tls"),
+			From:     from,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid mail.smtp: %w", err)
+		}
+		opts.Sender = sender
+	default:
+		return nil, fmt.Errorf("unsupported mail driver: %q", driver)
+	}
+
+	if dir := cfg.GetString("mail.template-dir"); dir != "" {
+		templates, err := mail.ParseTemplates(os.DirFS(dir), "*.tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("invalid mail.template-dir: %w", err)
+		}
+		if err := userbiz.CheckTemplates(templates); err != nil {
+			return nil, fmt.Errorf("invalid mail.template-dir: %w", err)
+		}
+		opts.Templates = templates
+	}
+
+	return opts, nil
+}
+
+// oidcOptions 读取 `auth.oidc` 中的配置，enabled 为 false 时不支持 OIDC 登录
+func oidcOptions(cfg *viper.Viper) (*userbiz.OIDCOptions, error) {
+	opts := userbiz.DefaultOIDCOptions()
+	if !cfg.GetBool("auth.oidc.enabled") {
+		return opts, nil
+	}
+
+	provider, err := oidc.New(oidc.Options{
+		Issuer:        cfg.GetString("auth.oidc.issuer"),
+		ClientID:      cfg.GetString("auth.oidc.client-id"),
+		ClientSecret:  cfg.GetString("auth.oidc.client-secret"),
+		RedirectURL:   cfg.GetString("auth.oidc.redirect-url"),
+		Scopes:        cfg.GetStringSlice("auth.oidc.scopes"),
+		UsernameClaim: cfg.GetString("auth.oidc.username-claim"),
+		GroupsClaim:   cfg.GetString("auth.oidc.groups-claim"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth.oidc: %w", err)
+	}
+
+	opts.Provider = provider
+	opts.AutoProvision = cfg.GetBool("auth.oidc.auto-provision")
+	opts.LinkByEmail = cfg.GetBool("auth.oidc.link-by-email")
+	opts.AdminGroups = cfg.GetStringSlice("auth.oidc.admin-groups")
+	if v := cfg.GetDuration("auth.oidc.session-expire"); v > 0 {
+		opts.SessionExpire = v
+	}
+	return opts, nil
+}
+
+// registrationMode 读取 `registration.mode`，为空时为 open
+func registrationMode(cfg *viper.Viper) (string, error) {
+	switch mode := cfg.GetString("registration.mode"); mode {
+	case "":
+		return known.RegistrationOpen, nil
+	case known.RegistrationOpen, known.RegistrationInviteOnly, known.RegistrationClosed:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid registration.mode %q, must be one of %s, %s, %s",
+			mode, known.RegistrationOpen, known.RegistrationInviteOnly, known.RegistrationClosed)
+	}
+}
+
+// hashOptions 从 `auth.password-hash` 中读取密码加密的算法和参数，未配置的选项使用默认值
+func hashOptions(cfg *viper.Viper) (*auth.HashOptions, error) {
+	opts := auth.DefaultHashOptions()
+	if err := cfg.UnmarshalKey("auth.password-hash", opts); err != nil {
+		return nil, fmt.Errorf("invalid auth.password-hash: %w", err)
+	}
+	return opts, nil
 }
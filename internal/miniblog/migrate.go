@@ -0,0 +1,36 @@
+package miniblog
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"miniblog/internal/miniblog/store"
+	"miniblog/internal/pkg/log"
+	"miniblog/pkg/db"
+)
+
+// newMigrateCommand 创建 `miniblog migrate` 子命令，根据 model 的定义在 db.type 指定的数据库中创建或更新数据表
+func newMigrateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "migrate",
+		Short:        "Create or update the database tables",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Init(logOptions())
+			defer log.Sync()
+
+			instance, err := newDB(viper.GetViper())
+			if err != nil {
+				return err
+			}
+			defer db.Close(instance)
+
+			if err := store.Migrate(instance); err != nil {
+				return err
+			}
+
+			log.Infow("Database tables migrated", "type", viper.GetString("db.type"))
+			return nil
+		},
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"miniblog/internal/pkg/log"
-	"miniblog/internal/pkg/middleware"
 	"miniblog/pkg/version/verflag"
 	"net/http"
 	"os"
@@ -63,6 +62,15 @@ Find more miniblog information at:
 	// Cobra 也支持本地标志，本地标志只能在其所绑定的命令上使用
 	cmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 
+	// 启动时一直等待数据库可用（直到超过 db.startup-wait），适用于数据库与服务同时启动的场景，例如 docker-compose 和 Kubernetes
+	cmd.Flags().Bool("wait-for-db", false, "Keep retrying to connect to the database until db.startup-wait elapses.")
+	_ = viper.BindPFlag("db.wait-for-db", cmd.Flags().Lookup("wait-for-db"))
+
+	// 添加子命令
+	cmd.AddCommand(newMigrateCommand())
+	cmd.AddCommand(newUserCommand())
+	cmd.AddCommand(newTrashCommand())
+
 	// 添加 --version 版本信息
 	verflag.AddFlags(cmd.PersistentFlags())
 
@@ -72,34 +80,24 @@ Find more miniblog information at:
 // run 函数是实际的业务代码入口函数
 func run() error {
 
-	// 初始化 store 层
-	if err := initStore(); err != nil {
+	// release 模式下禁止使用默认密码和密钥
+	if err := checkSecrets(viper.GetViper()); err != nil {
 		return err
 	}
 
+	// 构建应用容器，依次初始化数据库、store、biz 和 controller 层，密码加密参数和 token 的签发密钥也由 App 持有
+	app, err := NewApp(viper.GetViper())
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
 	// 设置 Gin 模式
 	gin.SetMode(viper.GetString("runmode"))
 
-	// 创建 Gin 引擎
-	g := gin.New()
-
-	// gin.Recover 中间件，用来捕获任何 panic 并恢复
-	middlewares := []gin.HandlerFunc{gin.Recovery(), middleware.NoCache, middleware.Cors, middleware.Secure, middleware.RequestID()}
-
-	g.Use(middlewares...)
-
-	//// 注册 404 Handler，将结果序列化为 JSON 格式放入 ResponseBody 中
-	//g.NoRoute(func(ctx *gin.Context) {
-	//	core.WriteResponse(ctx, errno.ErrPageNotFound, nil)
-	//})
-	//
-	//// 注册 /health Handler
-	//g.GET("/health", func(ctx *gin.Context) {
-	//	log.C(ctx).Infow("Health function called")
-	//	core.WriteResponse(ctx, nil, gin.H{"status": "OK"})
-	//})
-
-	if err := installRouters(g); err != nil {
+	// 创建 Gin 引擎，并注册中间件和路由
+	g, err := app.Engine()
+	if err != nil {
 		return err
 	}
 
@@ -119,6 +117,18 @@ func run() error {
 		}
 	}()
 
+	// 启动 gRPC 服务
+	grpcServer, err := app.startGRPCServer(viper.GetString("grpc.addr"))
+	if err != nil {
+		return err
+	}
+
+	// 启动回收站的定期清理任务和定时发布任务，服务退出时停止
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	app.startPurgeJob(jobCtx)
+	app.startPublishJob(jobCtx)
+
 	// 等待中断信号，优雅的关闭服务器（10s 超时）
 	quit := make(chan os.Signal, 1)
 	// 此处不阻塞。kill 默认会发送 SIGINT 信号；kill -2 发送 SIGTERM 信号（或 Ctrl+C）；kill -9 会发送 SIGKILL 信号，但无法被捕获，所以不添加在此处
@@ -136,6 +146,7 @@ func run() error {
 		log.Errorw("Insecure Server forced to shutdown", "err", err)
 		return err
 	}
+	grpcServer.GracefulStop()
 
 	log.Infow("Server existing")
 
@@ -0,0 +1,36 @@
+package miniblog
+
+import (
+	"context"
+	"miniblog/internal/pkg/log"
+	"time"
+)
+
+// startPublishJob 在后台每隔 `post.publish-interval` 发布到达发布时间的定时博客，ctx 被取消时停止。
+// 发布时通过条件更新认领博客，多个实例同时运行时每篇博客只会被发布一次，因此不需要选主或加锁
+func (a *App) startPublishJob(ctx context.Context) {
+	interval := a.cfg.GetDuration("post.publish-interval")
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			published, err := a.biz.Posts().PublishDue(ctx, time.Now())
+			if err != nil {
+				log.Errorw("Failed to publish scheduled posts", "err", err)
+			} else if published > 0 {
+				log.Infow("Scheduled posts published", "count", published)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
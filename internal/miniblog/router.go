@@ -2,14 +2,15 @@ package miniblog
 
 import (
 	"github.com/gin-gonic/gin"
-	"miniblog/internal/miniblog/controller/v1/user"
-	"miniblog/internal/miniblog/store"
 	"miniblog/internal/pkg/core"
 	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/known"
 	"miniblog/internal/pkg/log"
+	"miniblog/internal/pkg/middleware"
 )
 
-func installRouters(engine *gin.Engine) error {
+// installRouters 注册 miniblog 的所有 HTTP 路由
+func (a *App) installRouters(engine *gin.Engine) error {
 	// 注册 404 Handler
 	engine.NoRoute(func(ctx *gin.Context) {
 		core.WriteResponse(ctx, errno.ErrPageNotFound, nil)
@@ -21,15 +22,95 @@ func installRouters(engine *gin.Engine) error {
 		core.WriteResponse(ctx, nil, gin.H{"status": "OK"})
 	})
 
-	userController := user.New(store.DataStore)
+	// 各路由分组的限流策略在 `ratelimit.groups` 中配置，按用户名限流的策略只能用于有认证路由的分组
+	loginLimit, _, err := rateLimitMiddlewares(a.cfg, a.limiter, "login", false)
+	if err != nil {
+		return err
+	}
+	usersLimit, usersUserLimit, err := rateLimitMiddlewares(a.cfg, a.limiter, "users", true)
+	if err != nil {
+		return err
+	}
+	passwordResetLimit, _, err := rateLimitMiddlewares(a.cfg, a.limiter, "password-reset", false)
+	if err != nil {
+		return err
+	}
+
+	// 登录接口，登录请求的限流策略在 `ratelimit.groups.login` 中配置
+	login := engine.Group("/login", loginLimit...)
+	{
+		login.POST("", a.userController.Login)
+		login.POST("/2fa", a.userController.LoginTwoFactor)
+		login.POST("/oidc", a.userController.StartOIDCLogin)
+		login.POST("/oidc/callback", a.userController.LoginOIDC)
+	}
+
+	// authn 返回认证中间件，scope 为空的接口只能使用登录签发的 JWT Token 调用，不接受 API Key
+	authn := func(scope string) gin.HandlerFunc {
+		return middleware.Authn(a.tokens, a.biz.Users(), scope)
+	}
 
 	// 创建 v1 路由分组
 	v1 := engine.Group("/v1")
 	{
-		// 创建 users 路由分组
-		usersV1 := v1.Group("/users")
+		// 创建 users 路由分组，按用户名限流的策略挂载在认证中间件之后，不需要认证的接口按 IP 限流
+		usersV1 := v1.Group("/users", usersLimit...)
+		{
+			public := usersV1.Group("", usersUserLimit...)
+			public.POST("", a.userController.Create)
+			public.POST(":name/verify-email/confirm", a.userController.VerifyEmail)
+
+			self := usersV1.Group("", append([]gin.HandlerFunc{authn("")}, usersUserLimit...)...)
+			self.PUT(":name/change-password", a.userController.ChangePassword)
+			self.POST(":name/2fa", a.userController.EnrollTwoFactor)
+			self.POST(":name/2fa/confirm", a.userController.ConfirmTwoFactor)
+			self.DELETE(":name/2fa", a.userController.DisableTwoFactor)
+			self.POST(":name/verify-email", a.userController.SendVerificationEmail)
+			self.POST(":name/api-keys", a.userController.CreateAPIKey)
+			self.GET(":name/api-keys", a.userController.ListAPIKeys)
+			self.DELETE(":name/api-keys/:prefix", a.userController.RevokeAPIKey)
+
+			admin := usersV1.Group("", append([]gin.HandlerFunc{authn(known.ScopeUsersAdmin)}, usersUserLimit...)...)
+			admin.DELETE(":name", a.userController.Delete)
+			admin.POST(":name/unlock", a.userController.Unlock)
+		}
+
+		// 邀请码管理接口，只有管理员可以调用
+		invitationsV1 := v1.Group("/invitations", authn(known.ScopeUsersAdmin))
+		{
+			invitationsV1.POST("", a.userController.CreateInvitation)
+			invitationsV1.GET("", a.userController.ListInvitations)
+			invitationsV1.DELETE(":code", a.userController.DeleteInvitation)
+		}
+
+		// 创建 posts 路由分组
+		postsV1 := v1.Group("/posts")
+		{
+			postsV1.POST("", authn(known.ScopePostsWrite), a.postController.Create)
+			postsV1.GET("", authn(known.ScopePostsRead), a.postController.List)
+			postsV1.GET(":postID", authn(known.ScopePostsRead), a.postController.Get)
+			postsV1.PUT(":postID", authn(known.ScopePostsWrite), a.postController.Update)
+			postsV1.DELETE(":postID", authn(known.ScopePostsWrite), a.postController.Delete)
+			postsV1.GET(":postID/revisions", authn(known.ScopePostsRead), a.postController.ListRevisions)
+			postsV1.GET(":postID/revisions/:rev", authn(known.ScopePostsRead), a.postController.GetRevision)
+			postsV1.POST(":postID/revisions/:rev/restore", authn(known.ScopePostsWrite), a.postController.RestoreRevision)
+			postsV1.GET(":postID/diff", authn(known.ScopePostsRead), a.postController.Diff)
+		}
+
+		// 回收站接口，用于查看和恢复已删除但尚未永久删除的用户和博客，只有管理员可以调用
+		trashV1 := v1.Group("/trash", authn(known.ScopeUsersAdmin))
+		{
+			trashV1.GET("/users", a.userController.ListDeleted)
+			trashV1.POST("/users/:name/restore", a.userController.Restore)
+			trashV1.GET("/posts", a.postController.ListDeleted)
+			trashV1.POST("/posts/:postID/restore", a.postController.Restore)
+		}
+
+		// 重置密码接口会发送邮件，限流策略在 `ratelimit.groups.password-reset` 中配置
+		passwordResetV1 := v1.Group("/password-reset", passwordResetLimit...)
 		{
-			usersV1.POST("", userController.Create)
+			passwordResetV1.POST("", a.userController.PasswordReset)
+			passwordResetV1.POST("/confirm", a.userController.ConfirmPasswordReset)
 		}
 	}
 	return nil
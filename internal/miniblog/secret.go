@@ -0,0 +1,87 @@
+package miniblog
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"miniblog/internal/pkg/log"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// fileSuffix 配置项加上该后缀后表示从文件中读取配置值，例如 db.password_file，可用于 Docker/Kubernetes secrets
+const fileSuffix = "_file"
+
+// envRefPattern 匹配显式引用环境变量的配置值，例如 `password: ${MINIBLOG_DB_PASSWORD}`
+var envRefPattern = regexp.MustCompile(`^\$\{([A-Za-z_][A-Za-z0-9_]*)\}$`)
+
+// defaultSecrets 是随代码发布的默认密码和密钥，release 模式下禁止使用
+var defaultSecrets = map[string][]string{
+	"db.password": {"syl666", "root", "password", "123456", "miniblog1234"},
+	"jwt-secret":  {"Rtg8BPKNEf2mB4mgvKONGPZZQSaJWNLijxR42qRgq0iBb5"},
+}
+
+// resolveSecrets 解析 cfg 中引用文件和环境变量的配置值，并将敏感配置注册到日志脱敏列表中：
+//
+//   - `<key>_file: /path/to/file`（或环境变量 MINIBLOG_<KEY>_FILE）：读取文件内容作为 <key> 的值，去掉末尾换行，优先级高于 <key>
+//   - `<key>: ${ENV_NAME}`：读取环境变量 ENV_NAME 作为 <key> 的值，环境变量不存在时报错
+func resolveSecrets(cfg *viper.Viper) error {
+	keys := map[string]struct{}{}
+	for _, key := range cfg.AllKeys() {
+		keys[strings.TrimSuffix(key, fileSuffix)] = struct{}{}
+	}
+
+	for key := range keys {
+		if path := cfg.GetString(key + fileSuffix); path != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read %s%s: %w", key, fileSuffix, err)
+			}
+			value := strings.TrimRight(string(data), "\r\n")
+			cfg.Set(key, value)
+			log.AddSecrets(value)
+			continue
+		}
+
+		value, ok := cfg.Get(key).(string)
+		if !ok {
+			continue
+		}
+		if m := envRefPattern.FindStringSubmatch(value); m != nil {
+			env, ok := os.LookupEnv(m[1])
+			if !ok {
+				return fmt.Errorf("%s references environment variable %s, which is not set", key, m[1])
+			}
+			cfg.Set(key, env)
+			value = env
+		}
+		if log.IsSensitiveKey(key) {
+			log.AddSecrets(value)
+		}
+	}
+
+	return nil
+}
+
+// checkSecrets 在 release 模式下拒绝使用空的或随代码发布的默认密码、密钥启动服务
+func checkSecrets(cfg *viper.Viper) error {
+	if cfg.GetString("runmode") != gin.ReleaseMode {
+		return nil
+	}
+
+	for key, defaults := range defaultSecrets {
+		value := cfg.GetString(key)
+		if key == "jwt-secret" && value == "" {
+			return fmt.Errorf("refusing to start in %s mode: %s is empty", gin.ReleaseMode, key)
+		}
+		for _, d := range defaults {
+			if value == d {
+				return fmt.Errorf("refusing to start in %s mode: %s is set to a known default value, "+
+					"set %s%s or reference an environment variable instead", gin.ReleaseMode, key, key, fileSuffix)
+			}
+		}
+	}
+
+	return nil
+}
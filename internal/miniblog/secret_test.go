@@ -0,0 +1,89 @@
+package miniblog
+
+import (
+	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestConfig(t *testing.T, yaml string) *viper.Viper {
+	t.Helper()
+
+	cfg := viper.New()
+	cfg.SetConfigType("yaml")
+	cfg.SetEnvPrefix("MINIBLOG")
+	cfg.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	cfg.AutomaticEnv()
+	if err := cfg.ReadConfig(strings.NewReader(yaml)); err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	return cfg
+}
+
+func TestResolveSecrets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db-password")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_JWT_SECRET", "from-env")
+	t.Setenv("MINIBLOG_DB_TOKEN_FILE", path)
+
+	cfg := newTestConfig(t, `
+jwt-secret: ${TEST_JWT_SECRET}
+db:
+  password: syl666
+  password_file: `+path+`
+  token: plain
+`)
+	if err := resolveSecrets(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for key, want := range map[string]string{
+		"jwt-secret":  "from-env",
+		"db.password": "from-file",
+		"db.token":    "from-file",
+	} {
+		if got := cfg.GetString(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestResolveSecretsErrors(t *testing.T) {
+	tests := map[string]string{
+		"missing env":  "jwt-secret: ${MINIBLOG_TEST_UNSET_VARIABLE}",
+		"missing file": "db:\n  password_file: " + filepath.Join(t.TempDir(), "missing"),
+	}
+	for name, yaml := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := resolveSecrets(newTestConfig(t, yaml)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestCheckSecrets(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{"debug mode allows defaults", "runmode: debug\njwt-secret: x\ndb:\n  password: syl666", false},
+		{"default db password", "runmode: release\njwt-secret: x\ndb:\n  password: syl666", true},
+		{"default jwt secret", "runmode: release\njwt-secret: Rtg8BPKNEf2mB4mgvKONGPZZQSaJWNLijxR42qRgq0iBb5\ndb:\n  password: s3cr3t", true},
+		{"empty jwt secret", "runmode: release\ndb:\n  password: s3cr3t", true},
+		{"custom secrets", "runmode: release\njwt-secret: x\ndb:\n  password: s3cr3t", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSecrets(newTestConfig(t, tt.yaml))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkSecrets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
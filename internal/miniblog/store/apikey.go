@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"miniblog/internal/pkg/model"
+	"time"
+)
+
+// APIKeyStore 定义了 api_key 表的数据库操作
+type APIKeyStore interface {
+	Create(ctx context.Context, key *model.APIKeyM) error
+	Get(ctx context.Context, prefix string) (*model.APIKeyM, error)
+	List(ctx context.Context, username string) ([]*model.APIKeyM, error)
+	Delete(ctx context.Context, username, prefix string) error
+	DeleteByUser(ctx context.Context, username string) error
+	Touch(ctx context.Context, prefix string, at time.Time) error
+}
+
+type apiKeys struct {
+	db      *gorm.DB
+	timeout time.Duration
+}
+
+// 确保 apiKeys 实现了 APIKeyStore 接口
+var _ APIKeyStore = (*apiKeys)(nil)
+
+func newAPIKeys(db *gorm.DB, timeout time.Duration) *apiKeys {
+	return &apiKeys{db: db, timeout: timeout}
+}
+
+// Create 插入一条 API Key 记录
+func (k *apiKeys) Create(ctx context.Context, key *model.APIKeyM) error {
+	ctx, cancel := withTimeout(ctx, k.timeout)
+	defer cancel()
+
+	return translateErr(ctx, k.db.WithContext(ctx).Create(key).Error)
+}
+
+// Get 根据前缀查询 API Key，不存在时返回 ErrRecordNotFound
+func (k *apiKeys) Get(ctx context.Context, prefix string) (*model.APIKeyM, error) {
+	ctx, cancel := withTimeout(ctx, k.timeout)
+	defer cancel()
+
+	var key model.APIKeyM
+	if err := k.db.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		return nil, translateErr(ctx, err)
+	}
+
+	return &key, nil
+}
+
+// List 按创建顺序返回 username 的所有 API Key
+func (k *apiKeys) List(ctx context.Context, username string) ([]*model.APIKeyM, error) {
+	ctx, cancel := withTimeout(ctx, k.timeout)
+	defer cancel()
+
+	var ret []*model.APIKeyM
+	err := k.db.WithContext(ctx).Where("username = ?", username).Order("id").Find(&ret).Error
+	return ret, translateErr(ctx, err)
+}
+
+// Delete 删除 username 的一个 API Key，不存在时返回 ErrRecordNotFound
+func (k *apiKeys) Delete(ctx context.Context, username, prefix string) error {
+	ctx, cancel := withTimeout(ctx, k.timeout)
+	defer cancel()
+
+	result := k.db.WithContext(ctx).Where("username = ? AND prefix = ?", username, prefix).Delete(&model.APIKeyM{})
+	if result.Error != nil {
+		return translateErr(ctx, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteByUser 删除 username 的所有 API Key
+func (k *apiKeys) DeleteByUser(ctx context.Context, username string) error {
+	ctx, cancel := withTimeout(ctx, k.timeout)
+	defer cancel()
+
+	return translateErr(ctx, k.db.WithContext(ctx).Where("username = ?", username).Delete(&model.APIKeyM{}).Error)
+}
+
+// Touch 将 API Key 最近一次使用的时间更新为 at，不更新 updatedAt
+func (k *apiKeys) Touch(ctx context.Context, prefix string, at time.Time) error {
+	ctx, cancel := withTimeout(ctx, k.timeout)
+	defer cancel()
+
+	err := k.db.WithContext(ctx).Model(&model.APIKeyM{}).Where("prefix = ?", prefix).UpdateColumn("lastUsedAt", at).Error
+	return translateErr(ctx, err)
+}
@@ -0,0 +1,47 @@
+package store
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"miniblog/internal/pkg/model"
+	"time"
+)
+
+// IdentityStore 定义了 identity 表的数据库操作
+type IdentityStore interface {
+	Create(ctx context.Context, identity *model.IdentityM) error
+	Get(ctx context.Context, issuer, subject string) (*model.IdentityM, error)
+}
+
+type identities struct {
+	db      *gorm.DB
+	timeout time.Duration
+}
+
+// 确保 identities 实现了 IdentityStore 接口
+var _ IdentityStore = (*identities)(nil)
+
+func newIdentities(db *gorm.DB, timeout time.Duration) *identities {
+	return &identities{db: db, timeout: timeout}
+}
+
+// Create 插入一条外部身份的绑定记录，同一个外部身份只能绑定一个用户
+func (i *identities) Create(ctx context.Context, identity *model.IdentityM) error {
+	ctx, cancel := withTimeout(ctx, i.timeout)
+	defer cancel()
+
+	return translateErr(ctx, i.db.WithContext(ctx).Create(identity).Error)
+}
+
+// Get 根据 issuer 和 subject 查询绑定记录，不存在时返回 ErrRecordNotFound
+func (i *identities) Get(ctx context.Context, issuer, subject string) (*model.IdentityM, error) {
+	ctx, cancel := withTimeout(ctx, i.timeout)
+	defer cancel()
+
+	var identity model.IdentityM
+	if err := i.db.WithContext(ctx).Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error; err != nil {
+		return nil, translateErr(ctx, err)
+	}
+
+	return &identity, nil
+}
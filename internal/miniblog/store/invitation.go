@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"miniblog/internal/pkg/model"
+	"time"
+)
+
+// InvitationStore 定义了 invitation 表的数据库操作
+type InvitationStore interface {
+	Create(ctx context.Context, invitation *model.InvitationM) error
+	List(ctx context.Context) ([]*model.InvitationM, error)
+	Delete(ctx context.Context, code string) error
+	Use(ctx context.Context, code string, now time.Time) (bool, error)
+}
+
+type invitations struct {
+	db      *gorm.DB
+	timeout time.Duration
+}
+
+// 确保 invitations 实现了 InvitationStore 接口
+var _ InvitationStore = (*invitations)(nil)
+
+func newInvitations(db *gorm.DB, timeout time.Duration) *invitations {
+	return &invitations{db: db, timeout: timeout}
+}
+
+// Create 插入一条邀请码记录
+func (i *invitations) Create(ctx context.Context, invitation *model.InvitationM) error {
+	ctx, cancel := withTimeout(ctx, i.timeout)
+	defer cancel()
+
+	return translateErr(ctx, i.db.WithContext(ctx).Create(invitation).Error)
+}
+
+// List 按创建顺序返回所有邀请码
+func (i *invitations) List(ctx context.Context) ([]*model.InvitationM, error) {
+	ctx, cancel := withTimeout(ctx, i.timeout)
+	defer cancel()
+
+	var ret []*model.InvitationM
+	err := i.db.WithContext(ctx).Order("id").Find(&ret).Error
+	return ret, translateErr(ctx, err)
+}
+
+// Delete 删除邀请码，不存在时返回 ErrRecordNotFound
+func (i *invitations) Delete(ctx context.Context, code string) error {
+	ctx, cancel := withTimeout(ctx, i.timeout)
+	defer cancel()
+
+	result := i.db.WithContext(ctx).Where("code = ?", code).Delete(&model.InvitationM{})
+	if result.Error != nil {
+		return translateErr(ctx, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// Use 在邀请码未过期且未用完时将使用次数加一，返回是否使用成功。
+// 判断和更新在同一条 SQL 中完成，并发注册时邀请码的使用次数也不会超过 maxUses
+func (i *invitations) Use(ctx context.Context, code string, now time.Time) (bool, error) {
+	ctx, cancel := withTimeout(ctx, i.timeout)
+	defer cancel()
+
+	// 驼峰列名需要由 gorm 加引号，否则在 PostgreSQL 中会被转换为小写
+	expiresAt := clause.Column{Name: "expiresAt"}
+	result := i.db.WithContext(ctx).Model(&model.InvitationM{}).
+		Where("code = ?", code).
+		Where(gorm.Expr("uses < ?", clause.Column{Name: "maxUses"})).
+		Where(clause.Or(clause.Eq{Column: expiresAt, Value: nil}, clause.Gt{Column: expiresAt, Value: now})).
+		UpdateColumn("uses", gorm.Expr("uses + ?", 1))
+	if result.Error != nil {
+		return false, translateErr(ctx, result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
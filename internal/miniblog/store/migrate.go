@@ -0,0 +1,23 @@
+package store
+
+import (
+	"gorm.io/gorm"
+	"miniblog/internal/pkg/model"
+)
+
+// models 列出了 miniblog 所有需要建表的 model，新增 model 时需要加入此列表
+var models = []any{
+	&model.UserM{},
+	&model.PostM{},
+	&model.PostRevisionM{},
+	&model.APIKeyM{},
+	&model.IdentityM{},
+	&model.InvitationM{},
+}
+
+// Migrate 根据 model 的定义创建或更新数据表。
+// 表结构由 gorm 根据当前连接的数据库方言生成，因此同样适用于 MySQL 和 PostgreSQL（以及测试使用的 SQLite）。
+// Migrate 只会新增表、列和索引，不会删除已有的列和数据
+func Migrate(db *gorm.DB) error {
+	return db.AutoMigrate(models...)
+}
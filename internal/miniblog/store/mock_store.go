@@ -0,0 +1,991 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: miniblog/internal/miniblog/store (interfaces: IStore,UserStore,PostStore,PostRevisionStore,APIKeyStore,IdentityStore,InvitationStore)
+
+// Package store is a generated GoMock package.
+package store
+
+import (
+	context "context"
+	model "miniblog/internal/pkg/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIStore is a mock of IStore interface.
+type MockIStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockIStoreMockRecorder
+}
+
+// MockIStoreMockRecorder is the mock recorder for MockIStore.
+type MockIStoreMockRecorder struct {
+	mock *MockIStore
+}
+
+// NewMockIStore creates a new mock instance.
+func NewMockIStore(ctrl *gomock.Controller) *MockIStore {
+	mock := &MockIStore{ctrl: ctrl}
+	mock.recorder = &MockIStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIStore) EXPECT() *MockIStoreMockRecorder {
+	return m.recorder
+}
+
+// APIKeys mocks base method.
+func (m *MockIStore) APIKeys() APIKeyStore {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIKeys")
+	ret0, _ := ret[0].(APIKeyStore)
+	return ret0
+}
+
+// APIKeys indicates an expected call of APIKeys.
+func (mr *MockIStoreMockRecorder) APIKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIKeys", reflect.TypeOf((*MockIStore)(nil).APIKeys))
+}
+
+// Identities mocks base method.
+func (m *MockIStore) Identities() IdentityStore {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Identities")
+	ret0, _ := ret[0].(IdentityStore)
+	return ret0
+}
+
+// Identities indicates an expected call of Identities.
+func (mr *MockIStoreMockRecorder) Identities() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Identities", reflect.TypeOf((*MockIStore)(nil).Identities))
+}
+
+// Invitations mocks base method.
+func (m *MockIStore) Invitations() InvitationStore {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Invitations")
+	ret0, _ := ret[0].(InvitationStore)
+	return ret0
+}
+
+// Invitations indicates an expected call of Invitations.
+func (mr *MockIStoreMockRecorder) Invitations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invitations", reflect.TypeOf((*MockIStore)(nil).Invitations))
+}
+
+// PostRevisions mocks base method.
+func (m *MockIStore) PostRevisions() PostRevisionStore {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostRevisions")
+	ret0, _ := ret[0].(PostRevisionStore)
+	return ret0
+}
+
+// PostRevisions indicates an expected call of PostRevisions.
+func (mr *MockIStoreMockRecorder) PostRevisions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostRevisions", reflect.TypeOf((*MockIStore)(nil).PostRevisions))
+}
+
+// Posts mocks base method.
+func (m *MockIStore) Posts() PostStore {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Posts")
+	ret0, _ := ret[0].(PostStore)
+	return ret0
+}
+
+// Posts indicates an expected call of Posts.
+func (mr *MockIStoreMockRecorder) Posts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Posts", reflect.TypeOf((*MockIStore)(nil).Posts))
+}
+
+// TX mocks base method.
+func (m *MockIStore) TX(arg0 context.Context, arg1 func(context.Context, IStore) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TX", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TX indicates an expected call of TX.
+func (mr *MockIStoreMockRecorder) TX(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TX", reflect.TypeOf((*MockIStore)(nil).TX), arg0, arg1)
+}
+
+// Users mocks base method.
+func (m *MockIStore) Users() UserStore {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Users")
+	ret0, _ := ret[0].(UserStore)
+	return ret0
+}
+
+// Users indicates an expected call of Users.
+func (mr *MockIStoreMockRecorder) Users() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Users", reflect.TypeOf((*MockIStore)(nil).Users))
+}
+
+// MockUserStore is a mock of UserStore interface.
+type MockUserStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserStoreMockRecorder
+}
+
+// MockUserStoreMockRecorder is the mock recorder for MockUserStore.
+type MockUserStoreMockRecorder struct {
+	mock *MockUserStore
+}
+
+// NewMockUserStore creates a new mock instance.
+func NewMockUserStore(ctrl *gomock.Controller) *MockUserStore {
+	mock := &MockUserStore{ctrl: ctrl}
+	mock.recorder = &MockUserStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserStore) EXPECT() *MockUserStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockUserStore) Create(arg0 context.Context, arg1 *model.UserM) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUserStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockUserStore) Delete(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUserStoreMockRecorder) Delete(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserStore)(nil).Delete), arg0, arg1, arg2)
+}
+
+// Get mocks base method.
+func (m *MockUserStore) Get(arg0 context.Context, arg1 string) (*model.UserM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*model.UserM)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockUserStoreMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUserStore)(nil).Get), arg0, arg1)
+}
+
+// GetDeleted mocks base method.
+func (m *MockUserStore) GetDeleted(arg0 context.Context, arg1 string) (*model.UserM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeleted", arg0, arg1)
+	ret0, _ := ret[0].(*model.UserM)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeleted indicates an expected call of GetDeleted.
+func (mr *MockUserStoreMockRecorder) GetDeleted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleted", reflect.TypeOf((*MockUserStore)(nil).GetDeleted), arg0, arg1)
+}
+
+// ListByEmail mocks base method.
+func (m *MockUserStore) ListByEmail(arg0 context.Context, arg1 string) ([]*model.UserM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByEmail", arg0, arg1)
+	ret0, _ := ret[0].([]*model.UserM)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByEmail indicates an expected call of ListByEmail.
+func (mr *MockUserStoreMockRecorder) ListByEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByEmail", reflect.TypeOf((*MockUserStore)(nil).ListByEmail), arg0, arg1)
+}
+
+// ListDeleted mocks base method.
+func (m *MockUserStore) ListDeleted(arg0 context.Context, arg1, arg2 int) (int64, []*model.UserM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeleted", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].([]*model.UserM)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeleted indicates an expected call of ListDeleted.
+func (mr *MockUserStoreMockRecorder) ListDeleted(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeleted", reflect.TypeOf((*MockUserStore)(nil).ListDeleted), arg0, arg1, arg2)
+}
+
+// Purge mocks base method.
+func (m *MockUserStore) Purge(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockUserStoreMockRecorder) Purge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockUserStore)(nil).Purge), arg0, arg1)
+}
+
+// ReleaseLoginAttempt mocks base method.
+func (m *MockUserStore) ReleaseLoginAttempt(arg0 context.Context, arg1 string, arg2 *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseLoginAttempt", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseLoginAttempt indicates an expected call of ReleaseLoginAttempt.
+func (mr *MockUserStoreMockRecorder) ReleaseLoginAttempt(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseLoginAttempt", reflect.TypeOf((*MockUserStore)(nil).ReleaseLoginAttempt), arg0, arg1, arg2)
+}
+
+// ReserveLoginAttempt mocks base method.
+func (m *MockUserStore) ReserveLoginAttempt(arg0 context.Context, arg1 string, arg2 time.Time, arg3 func(int) time.Time) (int, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveLoginAttempt", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReserveLoginAttempt indicates an expected call of ReserveLoginAttempt.
+func (mr *MockUserStoreMockRecorder) ReserveLoginAttempt(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveLoginAttempt", reflect.TypeOf((*MockUserStore)(nil).ReserveLoginAttempt), arg0, arg1, arg2, arg3)
+}
+
+// ResetFailedLogins mocks base method.
+func (m *MockUserStore) ResetFailedLogins(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetFailedLogins", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetFailedLogins indicates an expected call of ResetFailedLogins.
+func (mr *MockUserStoreMockRecorder) ResetFailedLogins(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetFailedLogins", reflect.TypeOf((*MockUserStore)(nil).ResetFailedLogins), arg0, arg1)
+}
+
+// ResetPassword mocks base method.
+func (m *MockUserStore) ResetPassword(arg0 context.Context, arg1, arg2, arg3 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserStoreMockRecorder) ResetPassword(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserStore)(nil).ResetPassword), arg0, arg1, arg2, arg3)
+}
+
+// Restore mocks base method.
+func (m *MockUserStore) Restore(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockUserStoreMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockUserStore)(nil).Restore), arg0, arg1)
+}
+
+// SetRole mocks base method.
+func (m *MockUserStore) SetRole(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRole", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRole indicates an expected call of SetRole.
+func (mr *MockUserStoreMockRecorder) SetRole(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRole", reflect.TypeOf((*MockUserStore)(nil).SetRole), arg0, arg1, arg2)
+}
+
+// Update mocks base method.
+func (m *MockUserStore) Update(arg0 context.Context, arg1 *model.UserM) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockUserStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserStore)(nil).Update), arg0, arg1)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockUserStore) UpdatePassword(arg0 context.Context, arg1, arg2, arg3 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserStoreMockRecorder) UpdatePassword(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserStore)(nil).UpdatePassword), arg0, arg1, arg2, arg3)
+}
+
+// UpdateRecoveryCodes mocks base method.
+func (m *MockUserStore) UpdateRecoveryCodes(arg0 context.Context, arg1, arg2, arg3 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecoveryCodes", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRecoveryCodes indicates an expected call of UpdateRecoveryCodes.
+func (mr *MockUserStoreMockRecorder) UpdateRecoveryCodes(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecoveryCodes", reflect.TypeOf((*MockUserStore)(nil).UpdateRecoveryCodes), arg0, arg1, arg2, arg3)
+}
+
+// UpdateTwoFactor mocks base method.
+func (m *MockUserStore) UpdateTwoFactor(arg0 context.Context, arg1 *model.UserM) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTwoFactor", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTwoFactor indicates an expected call of UpdateTwoFactor.
+func (mr *MockUserStoreMockRecorder) UpdateTwoFactor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTwoFactor", reflect.TypeOf((*MockUserStore)(nil).UpdateTwoFactor), arg0, arg1)
+}
+
+// UseTOTPCounter mocks base method.
+func (m *MockUserStore) UseTOTPCounter(arg0 context.Context, arg1 string, arg2 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTOTPCounter", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseTOTPCounter indicates an expected call of UseTOTPCounter.
+func (mr *MockUserStoreMockRecorder) UseTOTPCounter(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTOTPCounter", reflect.TypeOf((*MockUserStore)(nil).UseTOTPCounter), arg0, arg1, arg2)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockUserStore) VerifyEmail(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockUserStoreMockRecorder) VerifyEmail(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockUserStore)(nil).VerifyEmail), arg0, arg1, arg2)
+}
+
+// MockPostStore is a mock of PostStore interface.
+type MockPostStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockPostStoreMockRecorder
+}
+
+// MockPostStoreMockRecorder is the mock recorder for MockPostStore.
+type MockPostStoreMockRecorder struct {
+	mock *MockPostStore
+}
+
+// NewMockPostStore creates a new mock instance.
+func NewMockPostStore(ctrl *gomock.Controller) *MockPostStore {
+	mock := &MockPostStore{ctrl: ctrl}
+	mock.recorder = &MockPostStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPostStore) EXPECT() *MockPostStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPostStore) Create(arg0 context.Context, arg1 *model.PostM) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPostStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPostStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockPostStore) Delete(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPostStoreMockRecorder) Delete(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPostStore)(nil).Delete), arg0, arg1, arg2)
+}
+
+// DeleteByUser mocks base method.
+func (m *MockPostStore) DeleteByUser(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUser indicates an expected call of DeleteByUser.
+func (mr *MockPostStoreMockRecorder) DeleteByUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUser", reflect.TypeOf((*MockPostStore)(nil).DeleteByUser), arg0, arg1, arg2)
+}
+
+// Get mocks base method.
+func (m *MockPostStore) Get(arg0 context.Context, arg1 string) (*model.PostM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*model.PostM)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPostStoreMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPostStore)(nil).Get), arg0, arg1)
+}
+
+// GetDeleted mocks base method.
+func (m *MockPostStore) GetDeleted(arg0 context.Context, arg1 string) (*model.PostM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeleted", arg0, arg1)
+	ret0, _ := ret[0].(*model.PostM)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeleted indicates an expected call of GetDeleted.
+func (mr *MockPostStoreMockRecorder) GetDeleted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleted", reflect.TypeOf((*MockPostStore)(nil).GetDeleted), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockPostStore) List(arg0 context.Context, arg1 *PostFilter, arg2, arg3 int) (int64, []*model.PostM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].([]*model.PostM)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockPostStoreMockRecorder) List(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPostStore)(nil).List), arg0, arg1, arg2, arg3)
+}
+
+// ListDeleted mocks base method.
+func (m *MockPostStore) ListDeleted(arg0 context.Context, arg1 string, arg2, arg3 int) (int64, []*model.PostM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeleted", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].([]*model.PostM)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeleted indicates an expected call of ListDeleted.
+func (mr *MockPostStoreMockRecorder) ListDeleted(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeleted", reflect.TypeOf((*MockPostStore)(nil).ListDeleted), arg0, arg1, arg2, arg3)
+}
+
+// ListDue mocks base method.
+func (m *MockPostStore) ListDue(arg0 context.Context, arg1 time.Time, arg2 int) ([]*model.PostM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.PostM)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockPostStoreMockRecorder) ListDue(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockPostStore)(nil).ListDue), arg0, arg1, arg2)
+}
+
+// Publish mocks base method.
+func (m *MockPostStore) Publish(arg0 context.Context, arg1 string, arg2 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockPostStoreMockRecorder) Publish(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockPostStore)(nil).Publish), arg0, arg1, arg2)
+}
+
+// Purge mocks base method.
+func (m *MockPostStore) Purge(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockPostStoreMockRecorder) Purge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockPostStore)(nil).Purge), arg0, arg1)
+}
+
+// Restore mocks base method.
+func (m *MockPostStore) Restore(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockPostStoreMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockPostStore)(nil).Restore), arg0, arg1)
+}
+
+// RestoreByUser mocks base method.
+func (m *MockPostStore) RestoreByUser(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreByUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreByUser indicates an expected call of RestoreByUser.
+func (mr *MockPostStoreMockRecorder) RestoreByUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreByUser", reflect.TypeOf((*MockPostStore)(nil).RestoreByUser), arg0, arg1, arg2)
+}
+
+// Update mocks base method.
+func (m *MockPostStore) Update(arg0 context.Context, arg1 *model.PostM) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPostStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPostStore)(nil).Update), arg0, arg1)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockPostStore) UpdateStatus(arg0 context.Context, arg1, arg2, arg3 string, arg4 *time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockPostStoreMockRecorder) UpdateStatus(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockPostStore)(nil).UpdateStatus), arg0, arg1, arg2, arg3, arg4)
+}
+
+// MockPostRevisionStore is a mock of PostRevisionStore interface.
+type MockPostRevisionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockPostRevisionStoreMockRecorder
+}
+
+// MockPostRevisionStoreMockRecorder is the mock recorder for MockPostRevisionStore.
+type MockPostRevisionStoreMockRecorder struct {
+	mock *MockPostRevisionStore
+}
+
+// NewMockPostRevisionStore creates a new mock instance.
+func NewMockPostRevisionStore(ctrl *gomock.Controller) *MockPostRevisionStore {
+	mock := &MockPostRevisionStore{ctrl: ctrl}
+	mock.recorder = &MockPostRevisionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPostRevisionStore) EXPECT() *MockPostRevisionStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPostRevisionStore) Create(arg0 context.Context, arg1 *model.PostRevisionM) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPostRevisionStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPostRevisionStore)(nil).Create), arg0, arg1)
+}
+
+// Get mocks base method.
+func (m *MockPostRevisionStore) Get(arg0 context.Context, arg1 string, arg2 int) (*model.PostRevisionM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.PostRevisionM)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPostRevisionStoreMockRecorder) Get(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPostRevisionStore)(nil).Get), arg0, arg1, arg2)
+}
+
+// Latest mocks base method.
+func (m *MockPostRevisionStore) Latest(arg0 context.Context, arg1 string) (*model.PostRevisionM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Latest", arg0, arg1)
+	ret0, _ := ret[0].(*model.PostRevisionM)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Latest indicates an expected call of Latest.
+func (mr *MockPostRevisionStoreMockRecorder) Latest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Latest", reflect.TypeOf((*MockPostRevisionStore)(nil).Latest), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockPostRevisionStore) List(arg0 context.Context, arg1 string, arg2, arg3 int) (int64, []*model.PostRevisionM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].([]*model.PostRevisionM)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockPostRevisionStoreMockRecorder) List(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPostRevisionStore)(nil).List), arg0, arg1, arg2, arg3)
+}
+
+// MockAPIKeyStore is a mock of APIKeyStore interface.
+type MockAPIKeyStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyStoreMockRecorder
+}
+
+// MockAPIKeyStoreMockRecorder is the mock recorder for MockAPIKeyStore.
+type MockAPIKeyStoreMockRecorder struct {
+	mock *MockAPIKeyStore
+}
+
+// NewMockAPIKeyStore creates a new mock instance.
+func NewMockAPIKeyStore(ctrl *gomock.Controller) *MockAPIKeyStore {
+	mock := &MockAPIKeyStore{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyStore) EXPECT() *MockAPIKeyStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAPIKeyStore) Create(arg0 context.Context, arg1 *model.APIKeyM) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAPIKeyStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAPIKeyStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockAPIKeyStore) Delete(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAPIKeyStoreMockRecorder) Delete(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAPIKeyStore)(nil).Delete), arg0, arg1, arg2)
+}
+
+// DeleteByUser mocks base method.
+func (m *MockAPIKeyStore) DeleteByUser(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUser indicates an expected call of DeleteByUser.
+func (mr *MockAPIKeyStoreMockRecorder) DeleteByUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUser", reflect.TypeOf((*MockAPIKeyStore)(nil).DeleteByUser), arg0, arg1)
+}
+
+// Get mocks base method.
+func (m *MockAPIKeyStore) Get(arg0 context.Context, arg1 string) (*model.APIKeyM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*model.APIKeyM)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAPIKeyStoreMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAPIKeyStore)(nil).Get), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockAPIKeyStore) List(arg0 context.Context, arg1 string) ([]*model.APIKeyM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*model.APIKeyM)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAPIKeyStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAPIKeyStore)(nil).List), arg0, arg1)
+}
+
+// Touch mocks base method.
+func (m *MockAPIKeyStore) Touch(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockAPIKeyStoreMockRecorder) Touch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockAPIKeyStore)(nil).Touch), arg0, arg1, arg2)
+}
+
+// MockIdentityStore is a mock of IdentityStore interface.
+type MockIdentityStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityStoreMockRecorder
+}
+
+// MockIdentityStoreMockRecorder is the mock recorder for MockIdentityStore.
+type MockIdentityStoreMockRecorder struct {
+	mock *MockIdentityStore
+}
+
+// NewMockIdentityStore creates a new mock instance.
+func NewMockIdentityStore(ctrl *gomock.Controller) *MockIdentityStore {
+	mock := &MockIdentityStore{ctrl: ctrl}
+	mock.recorder = &MockIdentityStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityStore) EXPECT() *MockIdentityStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockIdentityStore) Create(arg0 context.Context, arg1 *model.IdentityM) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIdentityStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIdentityStore)(nil).Create), arg0, arg1)
+}
+
+// Get mocks base method.
+func (m *MockIdentityStore) Get(arg0 context.Context, arg1, arg2 string) (*model.IdentityM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.IdentityM)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockIdentityStoreMockRecorder) Get(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockIdentityStore)(nil).Get), arg0, arg1, arg2)
+}
+
+// MockInvitationStore is a mock of InvitationStore interface.
+type MockInvitationStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvitationStoreMockRecorder
+}
+
+// MockInvitationStoreMockRecorder is the mock recorder for MockInvitationStore.
+type MockInvitationStoreMockRecorder struct {
+	mock *MockInvitationStore
+}
+
+// NewMockInvitationStore creates a new mock instance.
+func NewMockInvitationStore(ctrl *gomock.Controller) *MockInvitationStore {
+	mock := &MockInvitationStore{ctrl: ctrl}
+	mock.recorder = &MockInvitationStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvitationStore) EXPECT() *MockInvitationStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockInvitationStore) Create(arg0 context.Context, arg1 *model.InvitationM) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockInvitationStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInvitationStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockInvitationStore) Delete(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockInvitationStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockInvitationStore)(nil).Delete), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockInvitationStore) List(arg0 context.Context) ([]*model.InvitationM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0)
+	ret0, _ := ret[0].([]*model.InvitationM)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockInvitationStoreMockRecorder) List(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockInvitationStore)(nil).List), arg0)
+}
+
+// Use mocks base method.
+func (m *MockInvitationStore) Use(arg0 context.Context, arg1 string, arg2 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Use", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Use indicates an expected call of Use.
+func (mr *MockInvitationStoreMockRecorder) Use(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Use", reflect.TypeOf((*MockInvitationStore)(nil).Use), arg0, arg1, arg2)
+}
@@ -0,0 +1,270 @@
+package store
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"miniblog/internal/pkg/known"
+	"miniblog/internal/pkg/model"
+	"time"
+)
+
+// PostStore 定义了 post 表的数据库操作。除 ListDeleted、Restore 和 Purge 外，所有操作都不包含已删除的博客
+type PostStore interface {
+	Create(ctx context.Context, post *model.PostM) error
+	Get(ctx context.Context, postID string) (*model.PostM, error)
+	Update(ctx context.Context, post *model.PostM) error
+	UpdateStatus(ctx context.Context, postID, from, to string, publishAt *time.Time) (bool, error)
+	List(ctx context.Context, filter *PostFilter, offset, limit int) (int64, []*model.PostM, error)
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*model.PostM, error)
+	Publish(ctx context.Context, postID string, now time.Time) (bool, error)
+	Delete(ctx context.Context, postID string, at time.Time) error
+	DeleteByUser(ctx context.Context, username string, at time.Time) error
+	GetDeleted(ctx context.Context, postID string) (*model.PostM, error)
+	ListDeleted(ctx context.Context, username string, offset, limit int) (int64, []*model.PostM, error)
+	Restore(ctx context.Context, postID string) error
+	RestoreByUser(ctx context.Context, username string, deletedAt time.Time) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// PostFilter 是查询博客列表的过滤条件
+type PostFilter struct {
+	Username string // 只查询该用户的博客，为空时查询所有用户的博客
+	Status   string // 只查询该状态的博客，为空时不按状态过滤
+	Viewer   string // 查询博客的用户，未发布的博客只有作者可以看到。为空时只查询已发布的博客
+}
+
+// 博客列表的排序方式：按创建顺序倒序、按删除时间倒序
+var (
+	byID        = clause.OrderBy{Columns: []clause.OrderByColumn{{Column: column("id"), Desc: true}}}
+	byDeletedAt = clause.OrderBy{Columns: []clause.OrderByColumn{{Column: column("deletedAt"), Desc: true}, {Column: column("id"), Desc: true}}}
+)
+
+type posts struct {
+	db      *gorm.DB
+	timeout time.Duration
+}
+
+// 确保 posts 实现了 PostStore 接口
+var _ PostStore = (*posts)(nil)
+
+func newPosts(db *gorm.DB, timeout time.Duration) *posts {
+	return &posts{db: db, timeout: timeout}
+}
+
+// Create 插入一条博客记录
+func (p *posts) Create(ctx context.Context, post *model.PostM) error {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	return translateErr(ctx, p.db.WithContext(ctx).Create(post).Error)
+}
+
+// Get 根据 postID 查询博客，不存在或已删除时返回 ErrRecordNotFound
+func (p *posts) Get(ctx context.Context, postID string) (*model.PostM, error) {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	var post model.PostM
+	if err := p.db.WithContext(ctx).Where(clause.Eq{Column: column("postID"), Value: postID}).First(&post).Error; err != nil {
+		return nil, translateErr(ctx, err)
+	}
+
+	return &post, nil
+}
+
+// Update 更新一条博客记录，不更新博客的状态和发布时间，状态需要通过 UpdateStatus 修改
+func (p *posts) Update(ctx context.Context, post *model.PostM) error {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	// 指定更新的列后，记录已被删除时 Save 不会重新插入该记录
+	return translateErr(ctx, p.db.WithContext(ctx).Select("*").Omit("deletedAt", "status", "publishAt").Save(post).Error)
+}
+
+// UpdateStatus 在博客的状态为 from 时将状态修改为 to，并将发布时间设置为 publishAt。
+// 博客不存在、已删除或者状态已被其他请求修改时返回 false
+func (p *posts) UpdateStatus(ctx context.Context, postID, from, to string, publishAt *time.Time) (bool, error) {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	result := p.db.WithContext(ctx).Model(&model.PostM{}).
+		Where(clause.Eq{Column: column("postID"), Value: postID}).Where("status = ?", from).
+		Updates(map[string]any{"status": to, "publishAt": publishAt})
+	return result.RowsAffected > 0, translateErr(ctx, result.Error)
+}
+
+// List 按创建时间倒序分页查询符合 filter 的博客，返回博客总数和当前页的博客
+func (p *posts) List(ctx context.Context, filter *PostFilter, offset, limit int) (int64, []*model.PostM, error) {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	db := p.db.WithContext(ctx)
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	visible := clause.Expression(clause.Eq{Column: column("status"), Value: known.PostStatusPublished})
+	if filter.Viewer != "" {
+		visible = clause.Or(visible, clause.Eq{Column: column("username"), Value: filter.Viewer})
+	}
+
+	return p.list(ctx, db.Where(visible), filter.Username, byID, offset, limit)
+}
+
+// ListDue 按发布时间顺序查询最多 limit 篇到达发布时间（不晚于 now）但尚未发布的定时博客
+func (p *posts) ListDue(ctx context.Context, now time.Time, limit int) ([]*model.PostM, error) {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	var ret []*model.PostM
+	err := p.db.WithContext(ctx).Where("status = ?", known.PostStatusScheduled).
+		Where(clause.Lte{Column: column("publishAt"), Value: now}).
+		Clauses(clause.OrderBy{Columns: []clause.OrderByColumn{{Column: column("publishAt")}, {Column: column("id")}}}).
+		Limit(limit).Find(&ret).Error
+	return ret, translateErr(ctx, err)
+}
+
+// Publish 发布到达发布时间的定时博客。状态的检查和修改在一条 UPDATE 语句中完成，
+// 多个实例同时发布同一篇博客时只有一个实例返回 true。博客已被发布、修改了发布时间或者不再是定时博客时返回 false
+func (p *posts) Publish(ctx context.Context, postID string, now time.Time) (bool, error) {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	result := p.db.WithContext(ctx).Model(&model.PostM{}).
+		Where(clause.Eq{Column: column("postID"), Value: postID}).Where("status = ?", known.PostStatusScheduled).
+		Where(clause.Lte{Column: column("publishAt"), Value: now}).
+		UpdateColumn("status", known.PostStatusPublished)
+	return result.RowsAffected > 0, translateErr(ctx, result.Error)
+}
+
+// Delete 将博客标记为在 at 删除，不存在或已删除时返回 ErrRecordNotFound
+func (p *posts) Delete(ctx context.Context, postID string, at time.Time) error {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	result := p.db.WithContext(ctx).Model(&model.PostM{}).Where(clause.Eq{Column: column("postID"), Value: postID}).UpdateColumn("deletedAt", at)
+	if result.Error != nil {
+		return translateErr(ctx, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteByUser 将 username 所有未删除的博客标记为在 at 删除
+func (p *posts) DeleteByUser(ctx context.Context, username string, at time.Time) error {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	err := p.db.WithContext(ctx).Model(&model.PostM{}).Where("username = ?", username).UpdateColumn("deletedAt", at).Error
+	return translateErr(ctx, err)
+}
+
+// GetDeleted 根据 postID 查询已删除的博客，不存在或未删除时返回 ErrRecordNotFound
+func (p *posts) GetDeleted(ctx context.Context, postID string) (*model.PostM, error) {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	var post model.PostM
+	err := p.db.WithContext(ctx).Unscoped().Where(clause.Eq{Column: column("postID"), Value: postID}).Where(deleted).First(&post).Error
+	if err != nil {
+		return nil, translateErr(ctx, err)
+	}
+
+	return &post, nil
+}
+
+// ListDeleted 按删除时间倒序分页查询已删除的博客，username 为空时查询所有用户的博客
+func (p *posts) ListDeleted(ctx context.Context, username string, offset, limit int) (int64, []*model.PostM, error) {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	return p.list(ctx, p.db.WithContext(ctx).Unscoped().Where(deleted), username, byDeletedAt, offset, limit)
+}
+
+// Restore 恢复已删除的博客，不存在或未删除时返回 ErrRecordNotFound
+func (p *posts) Restore(ctx context.Context, postID string) error {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	result := p.db.WithContext(ctx).Unscoped().Model(&model.PostM{}).
+		Where(clause.Eq{Column: column("postID"), Value: postID}).Where(deleted).UpdateColumn("deletedAt", nil)
+	if result.Error != nil {
+		return translateErr(ctx, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// RestoreByUser 恢复 username 在 deletedAt 删除的博客，即删除用户时一起删除的博客，此前单独删除的博客保持删除状态
+func (p *posts) RestoreByUser(ctx context.Context, username string, deletedAt time.Time) error {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	err := p.db.WithContext(ctx).Unscoped().Model(&model.PostM{}).
+		Where("username = ?", username).Where(clause.Eq{Column: column("deletedAt"), Value: deletedAt}).UpdateColumn("deletedAt", nil).Error
+	return translateErr(ctx, err)
+}
+
+// Purge 永久删除在 before 之前删除的博客及其所有版本，返回删除的博客数量
+func (p *posts) Purge(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	var purged int64
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var postIDs []string
+		if err := tx.Unscoped().Model(&model.PostM{}).Where(clause.Lt{Column: column("deletedAt"), Value: before}).
+			Pluck("postID", &postIDs).Error; err != nil {
+			return err
+		}
+		if len(postIDs) == 0 {
+			return nil
+		}
+
+		if err := deleteRevisions(tx, postIDs); err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where(clause.IN{Column: column("postID"), Values: toValues(postIDs)}).Delete(&model.PostM{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+
+	return purged, translateErr(ctx, err)
+}
+
+// list 在 db 的基础上按 username 过滤，并按照 order 排序分页查询博客
+func (p *posts) list(ctx context.Context, db *gorm.DB, username string, order clause.OrderBy, offset, limit int) (int64, []*model.PostM, error) {
+	if username != "" {
+		db = db.Where("username = ?", username)
+	}
+	// 查询总数和分页查询共用查询条件，需要使用新的 Session 避免互相影响
+	db = db.Session(&gorm.Session{})
+
+	var count int64
+	if err := db.Model(&model.PostM{}).Count(&count).Error; err != nil {
+		return 0, nil, translateErr(ctx, err)
+	}
+
+	var ret []*model.PostM
+	err := db.Clauses(order).Offset(offset).Limit(limit).Find(&ret).Error
+	return count, ret, translateErr(ctx, err)
+}
+
+// deleteRevisions 在事务 tx 中删除 postIDs 的所有版本
+func deleteRevisions(tx *gorm.DB, postIDs []string) error {
+	return tx.Where(clause.IN{Column: column("postID"), Values: toValues(postIDs)}).Delete(&model.PostRevisionM{}).Error
+}
+
+// toValues 将字符串切片转换为 clause.IN 所需的 []any
+func toValues(values []string) []any {
+	ret := make([]any, 0, len(values))
+	for _, v := range values {
+		ret = append(ret, v)
+	}
+	return ret
+}
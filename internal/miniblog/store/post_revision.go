@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"miniblog/internal/pkg/model"
+	"time"
+)
+
+// PostRevisionStore 定义了 post_revision 表的数据库操作
+type PostRevisionStore interface {
+	Create(ctx context.Context, revision *model.PostRevisionM) error
+	Get(ctx context.Context, postID string, revision int) (*model.PostRevisionM, error)
+	Latest(ctx context.Context, postID string) (*model.PostRevisionM, error)
+	List(ctx context.Context, postID string, offset, limit int) (int64, []*model.PostRevisionM, error)
+}
+
+// byRevision 按版本号倒序排列，最新的版本在前
+var byRevision = clause.OrderBy{Columns: []clause.OrderByColumn{{Column: column("revision"), Desc: true}}}
+
+type postRevisions struct {
+	db      *gorm.DB
+	timeout time.Duration
+}
+
+// 确保 postRevisions 实现了 PostRevisionStore 接口
+var _ PostRevisionStore = (*postRevisions)(nil)
+
+func newPostRevisions(db *gorm.DB, timeout time.Duration) *postRevisions {
+	return &postRevisions{db: db, timeout: timeout}
+}
+
+// Create 插入一个博客版本，同一篇博客的版本号已存在时返回 ErrDuplicatedKey
+func (r *postRevisions) Create(ctx context.Context, revision *model.PostRevisionM) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return translateErr(ctx, r.db.WithContext(ctx).Create(revision).Error)
+}
+
+// Get 查询博客的指定版本，不存在时返回 ErrRecordNotFound
+func (r *postRevisions) Get(ctx context.Context, postID string, revision int) (*model.PostRevisionM, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var ret model.PostRevisionM
+	err := r.db.WithContext(ctx).Where(clause.Eq{Column: column("postID"), Value: postID}).Where("revision = ?", revision).First(&ret).Error
+	if err != nil {
+		return nil, translateErr(ctx, err)
+	}
+
+	return &ret, nil
+}
+
+// Latest 查询博客的最新版本，博客没有任何版本时返回 ErrRecordNotFound
+func (r *postRevisions) Latest(ctx context.Context, postID string) (*model.PostRevisionM, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var ret model.PostRevisionM
+	err := r.db.WithContext(ctx).Where(clause.Eq{Column: column("postID"), Value: postID}).Clauses(byRevision).Take(&ret).Error
+	if err != nil {
+		return nil, translateErr(ctx, err)
+	}
+
+	return &ret, nil
+}
+
+// List 按版本号倒序分页查询博客的版本，返回版本总数和当前页的版本。返回的版本不包含内容，只包含内容的哈希值
+func (r *postRevisions) List(ctx context.Context, postID string, offset, limit int) (int64, []*model.PostRevisionM, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	db := r.db.WithContext(ctx).Where(clause.Eq{Column: column("postID"), Value: postID}).Session(&gorm.Session{})
+
+	var count int64
+	if err := db.Model(&model.PostRevisionM{}).Count(&count).Error; err != nil {
+		return 0, nil, translateErr(ctx, err)
+	}
+
+	var ret []*model.PostRevisionM
+	err := db.Omit("content").Clauses(byRevision).Offset(offset).Limit(limit).Find(&ret).Error
+	return count, ret, translateErr(ctx, err)
+}
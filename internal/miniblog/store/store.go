@@ -1,38 +1,113 @@
 package store
 
+//go:generate mockgen -destination mock_store.go -package store miniblog/internal/miniblog/store IStore,UserStore,PostStore,PostRevisionStore,APIKeyStore,IdentityStore,InvitationStore
+
 import (
+	"context"
+	"errors"
 	"gorm.io/gorm"
-	"sync"
+	"gorm.io/gorm/clause"
+	"miniblog/internal/pkg/errno"
+	"time"
 )
 
-var (
-	once      sync.Once
-	DataStore *Datastore // 全局变量，方便其它包直接调用已初始化好的 Datastore 实例
-)
+// ErrDuplicatedKey 表示写入的数据违反了唯一键约束，biz 层可以直接使用该错误判断，而无需依赖 gorm
+var ErrDuplicatedKey = gorm.ErrDuplicatedKey
+
+// ErrRecordNotFound 表示查询的记录不存在
+var ErrRecordNotFound = gorm.ErrRecordNotFound
 
 // IStore 定义了 store 层所需要实现的方法
 type IStore interface {
+	// TX 在一个数据库事务中执行 fn，fn 中需要使用传入的 tx 访问数据库。
+	// fn 返回 nil 时提交事务，返回错误或者 panic 时回滚事务。在 fn 中再次调用 tx.TX 会使用保存点（SAVEPOINT）实现嵌套事务，
+	// 内层事务回滚只会回滚到对应的保存点，不影响外层事务
+	TX(ctx context.Context, fn func(ctx context.Context, tx IStore) error) error
 	Users() UserStore
+	Posts() PostStore
+	PostRevisions() PostRevisionStore
+	APIKeys() APIKeyStore
+	Identities() IdentityStore
+	Invitations() InvitationStore
 }
 
 // Datastore 是 IStore 的一个具体实现
 type Datastore struct {
-	db *gorm.DB
+	db           *gorm.DB
+	queryTimeout time.Duration // 查询的默认超时时间，ctx 中的截止时间更早时以 ctx 为准
 }
 
 // 确保 Datastore 实现了 IStore 接口
 var _ IStore = (*Datastore)(nil)
 
-// NewStore 创建一个数据库实例
-func NewStore(db *gorm.DB) *Datastore {
-	// 确保 S 只被初始化一次
-	once.Do(func() {
-		DataStore = &Datastore{db: db}
+// NewStore 创建一个 Datastore 实例，queryTimeout 为每次查询的默认超时时间，<= 0 表示不限制。
+// 每次调用都会返回一个新的实例，由调用方（App）负责持有并向下传递
+func NewStore(db *gorm.DB, queryTimeout time.Duration) *Datastore {
+	return &Datastore{db: db, queryTimeout: queryTimeout}
+}
+
+// TX 实现了 IStore 接口中的 TX 方法
+func (ds *Datastore) TX(ctx context.Context, fn func(ctx context.Context, tx IStore) error) error {
+	// gorm 在已经开启的事务中调用 Transaction 时，会自动使用 SAVEPOINT 实现嵌套事务
+	err := ds.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(ctx, &Datastore{db: tx, queryTimeout: ds.queryTimeout})
 	})
 
-	return DataStore
+	return translateErr(ctx, err)
 }
 
 func (ds *Datastore) Users() UserStore {
-	return newUsers(ds.db)
+	return newUsers(ds.db, ds.queryTimeout)
+}
+
+func (ds *Datastore) Posts() PostStore {
+	return newPosts(ds.db, ds.queryTimeout)
+}
+
+func (ds *Datastore) PostRevisions() PostRevisionStore {
+	return newPostRevisions(ds.db, ds.queryTimeout)
+}
+
+func (ds *Datastore) APIKeys() APIKeyStore {
+	return newAPIKeys(ds.db, ds.queryTimeout)
+}
+
+func (ds *Datastore) Identities() IdentityStore {
+	return newIdentities(ds.db, ds.queryTimeout)
+}
+
+func (ds *Datastore) Invitations() InvitationStore {
+	return newInvitations(ds.db, ds.queryTimeout)
+}
+
+// deleted 是查询已删除记录的条件，需要与 Unscoped 一起使用
+var deleted = clause.Neq{Column: column("deletedAt"), Value: nil}
+
+// column 返回一个由 gorm 加引号的列。驼峰列名不加引号时在 PostgreSQL 中会被转换为小写，因此不能直接写在 SQL 字符串中
+func column(name string) clause.Column {
+	return clause.Column{Name: name}
+}
+
+// withTimeout 为 ctx 设置查询的默认超时时间。调用方需要在查询结束后调用返回的 cancel 函数
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// translateErr 将由于 ctx 被取消或超时导致的数据库错误转换为对应的 errno，其余错误原样返回
+func translateErr(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return errno.ErrQueryTimeout
+	case errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled):
+		return errno.ErrRequestCanceled
+	default:
+		return err
+	}
 }
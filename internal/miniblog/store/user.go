@@ -3,25 +3,367 @@ package store
 import (
 	"context"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"miniblog/internal/pkg/model"
+	"time"
 )
 
+// UserStore 定义了 user 表的数据库操作。除 GetDeleted、ListDeleted、Restore 和 Purge 外，所有操作都不包含已删除的用户
 type UserStore interface {
 	Create(ctx context.Context, user *model.UserM) error
+	Get(ctx context.Context, username string) (*model.UserM, error)
+	ListByEmail(ctx context.Context, email string) ([]*model.UserM, error)
+	Update(ctx context.Context, user *model.UserM) error
+	ReserveLoginAttempt(ctx context.Context, username string, now time.Time, lockUntil func(attempts int) time.Time) (int, bool, error)
+	ReleaseLoginAttempt(ctx context.Context, username string, lockedUntil *time.Time) error
+	SetRole(ctx context.Context, username, role string) error
+	ResetFailedLogins(ctx context.Context, username string) error
+	UpdateTwoFactor(ctx context.Context, user *model.UserM) error
+	UseTOTPCounter(ctx context.Context, username string, counter int64) (bool, error)
+	UpdateRecoveryCodes(ctx context.Context, username, old, codes string) (bool, error)
+	VerifyEmail(ctx context.Context, username, email string) (bool, error)
+	ResetPassword(ctx context.Context, username, old, password string) (bool, error)
+	UpdatePassword(ctx context.Context, username, old, password string) (bool, error)
+	Delete(ctx context.Context, username string, at time.Time) error
+	GetDeleted(ctx context.Context, username string) (*model.UserM, error)
+	ListDeleted(ctx context.Context, offset, limit int) (int64, []*model.UserM, error)
+	Restore(ctx context.Context, username string) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
 }
 
 type users struct {
-	db *gorm.DB
+	db      *gorm.DB
+	timeout time.Duration
 }
 
 // 确保 users 实现了 UserStore 接口
 var _ UserStore = (*users)(nil)
 
-func newUsers(db *gorm.DB) *users {
-	return &users{db: db}
+func newUsers(db *gorm.DB, timeout time.Duration) *users {
+	return &users{db: db, timeout: timeout}
 }
 
 // Create 插入一条 User 记录
 func (u *users) Create(ctx context.Context, user *model.UserM) error {
-	return u.db.Create(&user).Error
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	return translateErr(ctx, u.db.WithContext(ctx).Create(user).Error)
+}
+
+// Get 根据用户名查询指定 user 的数据库记录，不存在时返回 ErrRecordNotFound
+func (u *users) Get(ctx context.Context, username string) (*model.UserM, error) {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	var user model.UserM
+	if err := u.db.WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, translateErr(ctx, err)
+	}
+
+	return &user, nil
+}
+
+// ListByEmail 查询邮箱为 email（不区分大小写）的所有用户，多个用户可以使用同一个邮箱
+func (u *users) ListByEmail(ctx context.Context, email string) ([]*model.UserM, error) {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	var ret []*model.UserM
+	err := u.db.WithContext(ctx).Where("LOWER(email) = LOWER(?)", email).Order("id").Find(&ret).Error
+	return ret, translateErr(ctx, err)
+}
+
+// Update 更新一条 user 数据库记录
+func (u *users) Update(ctx context.Context, user *model.UserM) error {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	// 指定更新的列后，记录已被删除时 Save 不会重新插入该记录
+	return translateErr(ctx, u.db.WithContext(ctx).Select("*").Omit("deletedAt").Save(user).Error)
+}
+
+// ReserveLoginAttempt 在校验密码之前为 username 预留一次登录尝试：仅当 username 没有被锁定（lockedUntil 为空或不晚于 now）时，
+// 将连续登录失败的次数加 1，并按照 lockUntil 返回的时间锁定账户（返回零值时不锁定），返回加 1 后的次数和 true；
+// 账户被锁定时不做任何修改，返回 false。调用方在校验通过后需要调用 ResetFailedLogins 或 ReleaseLoginAttempt。
+//
+// 计数和锁定在同一个事务中完成，并发的请求在同一行上串行执行，后执行的请求会看到前面的请求设置的锁定时间，
+// 因此同时发起的多次尝试同样受到限制
+func (u *users) ReserveLoginAttempt(ctx context.Context, username string, now time.Time, lockUntil func(attempts int) time.Time) (int, bool, error) {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	var attempts int
+	var reserved bool
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		unlocked := clause.Or(clause.Eq{Column: column("lockedUntil"), Value: nil}, clause.Lte{Column: column("lockedUntil"), Value: now})
+		result := tx.Model(&model.UserM{}).Where("username = ?", username).Where(unlocked).
+			UpdateColumn("failedLogins", gorm.Expr("? + 1", column("failedLogins")))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			var count int64
+			if err := tx.Model(&model.UserM{}).Where("username = ?", username).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return ErrRecordNotFound
+			}
+			return nil
+		}
+
+		if err := tx.Model(&model.UserM{}).Where("username = ?", username).Select("failedLogins").Scan(&attempts).Error; err != nil {
+			return err
+		}
+		reserved = true
+
+		until := lockUntil(attempts)
+		if until.IsZero() {
+			return nil
+		}
+		return tx.Model(&model.UserM{}).Where("username = ?", username).UpdateColumn("lockedUntil", until).Error
+	})
+	if err != nil {
+		return 0, false, translateErr(ctx, err)
+	}
+
+	return attempts, reserved, nil
+}
+
+// ReleaseLoginAttempt 撤销 ReserveLoginAttempt 预留的一次尝试：将连续登录失败的次数减 1，并将锁定时间恢复为 lockedUntil。
+// 用于校验通过但还不能清零失败次数的情况，例如开启了两步验证的用户通过了密码校验
+func (u *users) ReleaseLoginAttempt(ctx context.Context, username string, lockedUntil *time.Time) error {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	result := u.db.WithContext(ctx).Model(&model.UserM{}).
+		Where("username = ?", username).Where(clause.Gt{Column: column("failedLogins"), Value: 0}).
+		UpdateColumns(map[string]any{"failedLogins": gorm.Expr("? - 1", column("failedLogins")), "lockedUntil": lockedUntil})
+	return translateErr(ctx, result.Error)
+}
+
+// SetRole 只更新 username 的角色，用户不存在时返回 ErrRecordNotFound
+func (u *users) SetRole(ctx context.Context, username, role string) error {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	return translateErr(ctx, u.updateColumns(ctx, username, map[string]any{"role": role}))
+}
+
+// ResetFailedLogins 清零 username 连续登录失败的次数并解除锁定
+func (u *users) ResetFailedLogins(ctx context.Context, username string) error {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	return translateErr(ctx, u.updateColumns(ctx, username, map[string]any{"failedLogins": 0, "lockedUntil": nil}))
+}
+
+// UpdateTwoFactor 只更新 user 的两步验证相关的列（TOTP 密钥、是否开启、计数器和恢复码），用户不存在时返回 ErrRecordNotFound。
+// 其它列保持不变，基于副本中读到的旧数据更新时也不会覆盖期间修改的密码或锁定状态
+func (u *users) UpdateTwoFactor(ctx context.Context, user *model.UserM) error {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	return translateErr(ctx, u.updateColumns(ctx, user.Username, map[string]any{
+		"totpSecret":      user.TOTPSecret,
+		"totpEnabled":     user.TOTPEnabled,
+		"totpLastCounter": user.TOTPLastCounter,
+		"recoveryCodes":   user.RecoveryCodes,
+	}))
+}
+
+// UseTOTPCounter 在 counter 大于已使用的计数器时，将其记录为 username 最近一次使用的 TOTP 计数器并返回 true，否则返回 false。
+// 同一个验证码被并发提交时，只有一个请求会成功
+func (u *users) UseTOTPCounter(ctx context.Context, username string, counter int64) (bool, error) {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	result := u.db.WithContext(ctx).Model(&model.UserM{}).
+		Where("username = ?", username).Where(clause.Lt{Column: clause.Column{Name: "totpLastCounter"}, Value: counter}).
+		UpdateColumn("totpLastCounter", counter)
+	if result.Error != nil {
+		return false, translateErr(ctx, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateRecoveryCodes 仅当 username 当前的恢复码等于 old 时将其更新为 codes 并返回 true，否则返回 false。
+// 同一个恢复码被并发提交时，只有一个请求会成功
+func (u *users) UpdateRecoveryCodes(ctx context.Context, username, old, codes string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	result := u.db.WithContext(ctx).Model(&model.UserM{}).
+		Where("username = ?", username).Where(clause.Eq{Column: clause.Column{Name: "recoveryCodes"}, Value: old}).
+		UpdateColumn("recoveryCodes", codes)
+	if result.Error != nil {
+		return false, translateErr(ctx, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// VerifyEmail 仅当 username 当前的邮箱等于 email 且尚未验证时，将邮箱标记为已验证并返回 true，否则返回 false。
+// 只更新 emailVerified 列，发送验证邮件后邮箱发生变化时不会将新邮箱标记为已验证
+func (u *users) VerifyEmail(ctx context.Context, username, email string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	result := u.db.WithContext(ctx).Model(&model.UserM{}).
+		Where("username = ? AND email = ?", username, email).Where(clause.Eq{Column: clause.Column{Name: "emailVerified"}, Value: false}).
+		UpdateColumn("emailVerified", true)
+	if result.Error != nil {
+		return false, translateErr(ctx, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ResetPassword 仅当 username 当前的密码密文等于 old 时，将其更新为 password 并返回 true，否则返回 false。
+// 同时清零连续登录失败的次数、解除锁定，并将邮箱标记为已验证（能收到重置密码的邮件说明用户拥有该邮箱）
+func (u *users) ResetPassword(ctx context.Context, username, old, password string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	result := u.db.WithContext(ctx).Model(&model.UserM{}).
+		Where("username = ?", username).Where(clause.Eq{Column: clause.Column{Name: "password"}, Value: old}).
+		Updates(map[string]any{"password": password, "failedLogins": 0, "lockedUntil": nil, "emailVerified": true})
+	if result.Error != nil {
+		return false, translateErr(ctx, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UpdatePassword 仅当 username 当前的密码密文等于 old 时，将其更新为 password 并返回 true，否则返回 false。
+// 只更新 password 列，用于登录时使用新参数重新加密密码，不会覆盖期间重置的密码或连续登录失败的次数
+func (u *users) UpdatePassword(ctx context.Context, username, old, password string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	result := u.db.WithContext(ctx).Model(&model.UserM{}).
+		Where("username = ?", username).Where(clause.Eq{Column: clause.Column{Name: "password"}, Value: old}).
+		UpdateColumn("password", password)
+	if result.Error != nil {
+		return false, translateErr(ctx, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Delete 将 username 标记为在 at 删除，不存在或已删除时返回 ErrRecordNotFound。
+// 已删除的用户在被永久删除之前仍然占用用户名，因此可以恢复
+func (u *users) Delete(ctx context.Context, username string, at time.Time) error {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	result := u.db.WithContext(ctx).Model(&model.UserM{}).Where("username = ?", username).UpdateColumn("deletedAt", at)
+	if result.Error != nil {
+		return translateErr(ctx, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetDeleted 根据用户名查询已删除的用户，不存在或未删除时返回 ErrRecordNotFound
+func (u *users) GetDeleted(ctx context.Context, username string) (*model.UserM, error) {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	var user model.UserM
+	if err := u.db.WithContext(ctx).Unscoped().Where("username = ?", username).Where(deleted).First(&user).Error; err != nil {
+		return nil, translateErr(ctx, err)
+	}
+
+	return &user, nil
+}
+
+// ListDeleted 按删除时间倒序分页查询已删除的用户，返回已删除用户的总数和当前页的用户
+func (u *users) ListDeleted(ctx context.Context, offset, limit int) (int64, []*model.UserM, error) {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	db := u.db.WithContext(ctx).Unscoped().Where(deleted).Session(&gorm.Session{})
+
+	var count int64
+	if err := db.Model(&model.UserM{}).Count(&count).Error; err != nil {
+		return 0, nil, translateErr(ctx, err)
+	}
+
+	var ret []*model.UserM
+	err := db.Clauses(byDeletedAt).Offset(offset).Limit(limit).Find(&ret).Error
+	return count, ret, translateErr(ctx, err)
+}
+
+// Restore 恢复已删除的用户，不存在或未删除时返回 ErrRecordNotFound
+func (u *users) Restore(ctx context.Context, username string) error {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	result := u.db.WithContext(ctx).Unscoped().Model(&model.UserM{}).
+		Where("username = ?", username).Where(deleted).UpdateColumn("deletedAt", nil)
+	if result.Error != nil {
+		return translateErr(ctx, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge 永久删除在 before 之前删除的用户，以及这些用户的博客（包括博客的所有版本）、API Key 和绑定的外部身份，返回删除的用户数量
+func (u *users) Purge(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := withTimeout(ctx, u.timeout)
+	defer cancel()
+
+	var purged int64
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var usernames []string
+		if err := tx.Unscoped().Model(&model.UserM{}).Where(clause.Lt{Column: column("deletedAt"), Value: before}).
+			Pluck("username", &usernames).Error; err != nil {
+			return err
+		}
+		if len(usernames) == 0 {
+			return nil
+		}
+
+		var postIDs []string
+		if err := tx.Unscoped().Model(&model.PostM{}).Where("username IN ?", usernames).Pluck("postID", &postIDs).Error; err != nil {
+			return err
+		}
+		if len(postIDs) > 0 {
+			if err := deleteRevisions(tx, postIDs); err != nil {
+				return err
+			}
+		}
+
+		for _, m := range []any{&model.PostM{}, &model.APIKeyM{}, &model.IdentityM{}} {
+			if err := tx.Unscoped().Where("username IN ?", usernames).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Unscoped().Where("username IN ?", usernames).Delete(&model.UserM{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+
+	return purged, translateErr(ctx, err)
+}
+
+// updateColumns 更新 username 的指定列，不更新 updatedAt，用户不存在时返回 ErrRecordNotFound
+func (u *users) updateColumns(ctx context.Context, username string, columns map[string]any) error {
+	result := u.db.WithContext(ctx).Model(&model.UserM{}).Where("username = ?", username).UpdateColumns(columns)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+
+	// MySQL 的 RowsAffected 不包含值没有变化的行，需要再确认用户是否存在
+	var count int64
+	if err := u.db.WithContext(ctx).Model(&model.UserM{}).Where("username = ?", username).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
 }
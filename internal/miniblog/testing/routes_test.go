@@ -0,0 +1,1183 @@
+package testing_test
+
+import (
+	"context"
+	"errors"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/spf13/viper"
+	"miniblog/internal/miniblog"
+	"miniblog/internal/miniblog/biz/post"
+	"miniblog/internal/miniblog/store"
+	"miniblog/internal/miniblog/testing"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/known"
+	"miniblog/internal/pkg/model"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	"miniblog/pkg/auth"
+	"miniblog/pkg/oidc/oidctest"
+	"miniblog/pkg/token"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	stdtesting "testing"
+	"time"
+)
+
+func TestHealth(t *stdtesting.T) {
+	s := testing.NewServer(t)
+
+	w := s.Do(http.MethodGet, "/health", nil)
+	testing.AssertOK(t, w)
+
+	var resp map[string]string
+	testing.DecodeJSON(t, w, &resp)
+	if resp["status"] != "OK" {
+		t.Fatalf("unexpected health status: %v", resp)
+	}
+	if w.Header().Get("X-Request-ID") == "" {
+		t.Fatal("X-Request-ID header is missing")
+	}
+}
+
+func TestNoRoute(t *stdtesting.T) {
+	s := testing.NewServer(t)
+
+	testing.AssertErrno(t, s.Do(http.MethodGet, "/v1/not-exist", nil), errno.ErrPageNotFound)
+}
+
+func TestCreateUser(t *stdtesting.T) {
+	invalid := testing.NewCreateUserRequest("bob")
+	invalid.Email = "not-an-email"
+
+	tests := []struct {
+		name    string
+		body    any
+		wantErr *errno.Errno
+	}{
+		{name: "ok", body: testing.NewCreateUserRequest("bob")},
+		{name: "malformed json", body: `{"username":`, wantErr: errno.ErrBind},
+		{name: "invalid email", body: invalid, wantErr: errno.ErrInvalidParam},
+		{name: "missing fields", body: map[string]string{"username": "bob"}, wantErr: errno.ErrInvalidParam},
+		{name: "duplicate username", body: testing.NewCreateUserRequest("alice"), wantErr: errno.ErrUserAlreadyExist},
+		{name: "body too large", body: `{"username":"` + strings.Repeat("a", 2<<20) + `"}`, wantErr: errno.ErrRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *stdtesting.T) {
+			s := testing.NewServer(t)
+			s.CreateUser("alice")
+
+			w := s.Do(http.MethodPost, "/v1/users", tt.body)
+			if tt.wantErr != nil {
+				testing.AssertErrno(t, w, tt.wantErr)
+				return
+			}
+			testing.AssertOK(t, w)
+		})
+	}
+}
+
+func TestCreateUserPasswordPolicy(t *stdtesting.T) {
+	tests := []struct {
+		name     string
+		password string
+		wantErr  *errno.Errno
+	}{
+		{name: "passphrase longer than 18 characters", password: "correct horse battery staple"},
+		{name: "too short", password: "abc123", wantErr: errno.ErrPasswordTooShort},
+		{name: "contains username", password: "bob-the-builder", wantErr: errno.ErrPasswordContainsUserInfo},
+		{name: "breached", password: "qwerty123", wantErr: errno.ErrPasswordBreached},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *stdtesting.T) {
+			s := testing.NewServer(t)
+
+			req := testing.NewCreateUserRequest("bob")
+			req.Password = tt.password
+			w := s.Do(http.MethodPost, "/v1/users", req)
+			if tt.wantErr != nil {
+				testing.AssertErrno(t, w, tt.wantErr)
+				return
+			}
+			testing.AssertOK(t, w)
+		})
+	}
+}
+
+func TestChangePassword(t *stdtesting.T) {
+	const newPassword = "correct horse battery staple"
+
+	tests := []struct {
+		name    string
+		path    string
+		body    any
+		opts    func(s *testing.Server) []testing.RequestOption
+		wantErr *errno.Errno
+	}{
+		{
+			name: "ok",
+			path: "/v1/users/alice/change-password",
+			body: v1.ChangePasswordRequest{OldPassword: testing.DefaultPassword, NewPassword: newPassword},
+		},
+		{
+			name:    "missing token",
+			path:    "/v1/users/alice/change-password",
+			body:    v1.ChangePasswordRequest{OldPassword: testing.DefaultPassword, NewPassword: newPassword},
+			opts:    func(s *testing.Server) []testing.RequestOption { return nil },
+			wantErr: errno.ErrTokenInvalid,
+		},
+		{
+			name:    "other user",
+			path:    "/v1/users/bob/change-password",
+			body:    v1.ChangePasswordRequest{OldPassword: testing.DefaultPassword, NewPassword: newPassword},
+			wantErr: errno.ErrPermissionDenied,
+		},
+		{
+			name:    "wrong old password",
+			path:    "/v1/users/alice/change-password",
+			body:    v1.ChangePasswordRequest{OldPassword: "wrong-password", NewPassword: newPassword},
+			wantErr: errno.ErrPasswordIncorrect,
+		},
+		{
+			name:    "weak new password",
+			path:    "/v1/users/alice/change-password",
+			body:    v1.ChangePasswordRequest{OldPassword: testing.DefaultPassword, NewPassword: "short"},
+			wantErr: errno.ErrPasswordTooShort,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *stdtesting.T) {
+			s := testing.NewServer(t)
+			s.CreateUser("alice")
+			s.CreateUser("bob")
+
+			opts := []testing.RequestOption{testing.WithToken(s.Login("alice"))}
+			if tt.opts != nil {
+				opts = tt.opts(s)
+			}
+
+			w := s.Do(http.MethodPut, tt.path, tt.body, opts...)
+			if tt.wantErr != nil {
+				testing.AssertErrno(t, w, tt.wantErr)
+				return
+			}
+			testing.AssertOK(t, w)
+
+			var user model.UserM
+			if err := s.DB.Where("username = ?", "alice").First(&user).Error; err != nil {
+				t.Fatalf("failed to query user: %v", err)
+			}
+			if _, err := auth.DefaultHasher().Compare(user.Password, newPassword); err != nil {
+				t.Fatalf("password was not changed: %v", err)
+			}
+		})
+	}
+}
+
+func TestLogin(t *stdtesting.T) {
+	tests := []struct {
+		name    string
+		body    any
+		wantErr *errno.Errno
+	}{
+		{name: "ok", body: v1.LoginRequest{Username: "alice", Password: testing.DefaultPassword}},
+		{name: "wrong password", body: v1.LoginRequest{Username: "alice", Password: "wrong-password"}, wantErr: errno.ErrInvalidCredentials},
+		{name: "unknown user", body: v1.LoginRequest{Username: "bob", Password: testing.DefaultPassword}, wantErr: errno.ErrInvalidCredentials},
+		{name: "missing password", body: map[string]string{"username": "alice"}, wantErr: errno.ErrInvalidParam},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *stdtesting.T) {
+			s := testing.NewServer(t)
+			s.CreateUser("alice")
+
+			w := s.Do(http.MethodPost, "/login", tt.body)
+			if tt.wantErr != nil {
+				testing.AssertErrno(t, w, tt.wantErr)
+				return
+			}
+			testing.AssertOK(t, w)
+
+			var resp v1.LoginResponse
+			testing.DecodeJSON(t, w, &resp)
+			if resp.Token == "" {
+				t.Fatal("token is empty")
+			}
+		})
+	}
+}
+
+func TestLoginRehashesOutdatedPassword(t *stdtesting.T) {
+	s := testing.NewServer(t, testing.WithConfig("auth.password-hash.bcrypt-cost", 11))
+	s.CreateUser("alice")
+
+	var user model.UserM
+	if err := s.DB.Where("username = ?", "alice").First(&user).Error; err != nil {
+		t.Fatalf("failed to query user: %v", err)
+	}
+	if !strings.HasPrefix(user.Password, "$2a$11$") {
+		t.Fatalf("password was not hashed with the configured cost: %s", user.Password)
+	}
+
+	// 提高加密参数后，使用旧参数加密的密码仍然可以登录，并在登录时使用新参数重新加密
+	outdated, err := auth.DefaultHasher().Encrypt(testing.DefaultPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DB.Model(&model.UserM{}).Where("username = ?", "alice").Update("password", outdated).Error; err != nil {
+		t.Fatalf("failed to update password: %v", err)
+	}
+
+	s.Login("alice")
+
+	if err := s.DB.Where("username = ?", "alice").First(&user).Error; err != nil {
+		t.Fatalf("failed to query user: %v", err)
+	}
+	if !strings.HasPrefix(user.Password, "$2a$11$") {
+		t.Fatalf("password was not rehashed with the new cost: %s", user.Password)
+	}
+	if _, err := auth.DefaultHasher().Compare(user.Password, testing.DefaultPassword); err != nil {
+		t.Fatalf("rehashed password does not match: %v", err)
+	}
+}
+
+func TestTokenConfig(t *stdtesting.T) {
+	s := testing.NewServer(t, testing.WithConfig("jwt-secret", "alice-secret"), testing.WithConfig("jwt-expire", "1h"))
+	s.CreateUser("alice")
+
+	// 签发的 token 使用配置的密钥和有效期
+	tokenString := s.Login("alice")
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(tokenString, claims, func(*jwt.Token) (any, error) { return []byte("alice-secret"), nil }); err != nil {
+		t.Fatalf("token is not signed with jwt-secret: %v", err)
+	}
+	exp, _ := claims["exp"].(float64)
+	iat, _ := claims["iat"].(float64)
+	if got := time.Duration(exp-iat) * time.Second; got != time.Hour {
+		t.Fatalf("unexpected token lifetime: want %v, got %v", time.Hour, got)
+	}
+	testing.AssertOK(t, s.Do(http.MethodGet, "/v1/users/alice/api-keys", nil, testing.WithToken(tokenString)))
+
+	// 使用其他密钥的 App 不接受该 token，同一进程中的多个 App 互不影响
+	other := testing.NewServer(t, testing.WithConfig("jwt-secret", "bob-secret"))
+	other.CreateUser("alice")
+	testing.AssertErrno(t, other.Do(http.MethodGet, "/v1/users/alice/api-keys", nil, testing.WithToken(tokenString)), errno.ErrTokenInvalid)
+	testing.AssertOK(t, other.Do(http.MethodGet, "/v1/users/alice/api-keys", nil, testing.WithToken(other.Login("alice"))))
+}
+
+func TestMissingTokenKey(t *stdtesting.T) {
+	// 没有配置 jwt-secret 时启动失败，不会使用内置的密钥签发 token
+	if _, err := miniblog.NewAppWithStore(viper.New(), store.NewStore(testing.NewDB(t), 0)); !errors.Is(err, token.ErrMissingKey) {
+		t.Fatalf("missing jwt-secret should be rejected, got %v", err)
+	}
+}
+
+func TestLoginLockout(t *stdtesting.T) {
+	s := testing.NewServer(t,
+		testing.WithConfig("auth.lockout.threshold", 3),
+		testing.WithConfig("auth.lockout.duration", "1h"),
+		testing.WithConfig("auth.lockout.delay", 0),
+	)
+	s.CreateUser("alice")
+	s.CreateUser("bob")
+	s.CreateUser("root")
+	s.SetRole("root", known.RoleAdmin)
+	bobToken, rootToken := s.Login("bob"), s.Login("root")
+	// 管理员必须开启两步验证才能执行管理操作
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/unlock", nil, testing.WithToken(rootToken)), errno.ErrTwoFactorRequired)
+	s.EnableTwoFactor(rootToken, "root")
+
+	wrong := v1.LoginRequest{Username: "alice", Password: "wrong-password"}
+	right := v1.LoginRequest{Username: "alice", Password: testing.DefaultPassword}
+
+	for i := 0; i < 3; i++ {
+		testing.AssertErrno(t, s.Do(http.MethodPost, "/login", wrong), errno.ErrInvalidCredentials)
+	}
+	// 达到阈值后，即使密码正确也无法登录，且响应与不存在的用户相同
+	locked := s.Do(http.MethodPost, "/login", right)
+	testing.AssertErrno(t, locked, errno.ErrInvalidCredentials)
+	unknown := s.Do(http.MethodPost, "/login", v1.LoginRequest{Username: "nobody", Password: testing.DefaultPassword})
+	if locked.Body.String() != unknown.Body.String() {
+		t.Fatalf("locked account is distinguishable from unknown user: %s != %s", locked.Body.String(), unknown.Body.String())
+	}
+
+	// 只有管理员可以解锁
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/unlock", nil), errno.ErrTokenInvalid)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/unlock", nil, testing.WithToken(bobToken)), errno.ErrPermissionDenied)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/nobody/unlock", nil, testing.WithToken(rootToken)), errno.ErrUserNotFound)
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/users/alice/unlock", nil, testing.WithToken(rootToken)))
+
+	testing.AssertOK(t, s.Do(http.MethodPost, "/login", right))
+}
+
+func TestLoginThrottle(t *stdtesting.T) {
+	s := testing.NewServer(t, testing.WithConfig("auth.lockout.delay", "1h"))
+	s.CreateUser("alice")
+
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/login", v1.LoginRequest{Username: "alice", Password: "wrong-password"}), errno.ErrInvalidCredentials)
+	// 登录失败后需要等待 delay 才能再次尝试，等待期间的响应与不存在的用户相同
+	throttled := s.Do(http.MethodPost, "/login", v1.LoginRequest{Username: "alice", Password: testing.DefaultPassword})
+	testing.AssertErrno(t, throttled, errno.ErrInvalidCredentials)
+	unknown := s.Do(http.MethodPost, "/login", v1.LoginRequest{Username: "nobody", Password: testing.DefaultPassword})
+	if throttled.Body.String() != unknown.Body.String() {
+		t.Fatalf("throttled account is distinguishable from unknown user: %s != %s", throttled.Body.String(), unknown.Body.String())
+	}
+}
+
+func TestTwoFactor(t *stdtesting.T) {
+	// 验证码错误同样会计入登录失败的次数，关闭失败后的等待时间
+	s := testing.NewServer(t, testing.WithConfig("auth.lockout.delay", 0))
+	s.CreateUser("alice")
+	s.CreateUser("bob")
+	aliceToken, bobToken := s.Login("alice"), s.Login("bob")
+
+	// 只能为自己开启两步验证，且需要先绑定密钥
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/2fa", nil, testing.WithToken(bobToken)), errno.ErrPermissionDenied)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/2fa/confirm", v1.TwoFactorCodeRequest{Code: "123456"}, testing.WithToken(aliceToken)), errno.ErrTwoFactorNotEnrolled)
+
+	secret, recoveryCodes := s.EnableTwoFactor(aliceToken, "alice")
+	if len(recoveryCodes) != 10 {
+		t.Fatalf("unexpected recovery codes: %v", recoveryCodes)
+	}
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/2fa", nil, testing.WithToken(aliceToken)), errno.ErrTwoFactorAlreadyEnabled)
+
+	login := func() string {
+		w := s.Do(http.MethodPost, "/login", v1.LoginRequest{Username: "alice", Password: testing.DefaultPassword})
+		testing.AssertOK(t, w)
+		var resp v1.LoginResponse
+		testing.DecodeJSON(t, w, &resp)
+		if resp.Token != "" || !resp.TwoFactorRequired || resp.Challenge == "" {
+			t.Fatalf("expected a two-factor challenge, got %+v", resp)
+		}
+		return resp.Challenge
+	}
+
+	// challenge 不能作为 token 使用
+	challenge := login()
+	testing.AssertErrno(t, s.Do(http.MethodPut, "/v1/users/alice/change-password", v1.ChangePasswordRequest{}, testing.WithToken(challenge)), errno.ErrTokenInvalid)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/login/2fa", v1.LoginTwoFactorRequest{Challenge: aliceToken, Code: "123456"}), errno.ErrChallengeInvalid)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/login/2fa", v1.LoginTwoFactorRequest{Challenge: challenge, Code: "000000"}), errno.ErrTwoFactorCodeInvalid)
+
+	// 确认时已经使用了当前周期的验证码，使用下一个周期的验证码登录；同一个验证码不能重复使用
+	code := testing.TOTPCode(t, secret, time.Now().Add(30*time.Second))
+	w := s.Do(http.MethodPost, "/login/2fa", v1.LoginTwoFactorRequest{Challenge: challenge, Code: code})
+	testing.AssertOK(t, w)
+	var resp v1.LoginResponse
+	testing.DecodeJSON(t, w, &resp)
+	if resp.Token == "" {
+		t.Fatalf("expected a token, got %+v", resp)
+	}
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/login/2fa", v1.LoginTwoFactorRequest{Challenge: login(), Code: code}), errno.ErrTwoFactorCodeInvalid)
+
+	// 恢复码只能使用一次，且不区分大小写
+	testing.AssertOK(t, s.Do(http.MethodPost, "/login/2fa", v1.LoginTwoFactorRequest{Challenge: login(), Code: strings.ToUpper(recoveryCodes[0])}))
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/login/2fa", v1.LoginTwoFactorRequest{Challenge: login(), Code: recoveryCodes[0]}), errno.ErrTwoFactorCodeInvalid)
+
+	// 关闭两步验证后恢复为只使用密码登录
+	testing.AssertOK(t, s.Do(http.MethodDelete, "/v1/users/alice/2fa", v1.TwoFactorCodeRequest{Code: recoveryCodes[1]}, testing.WithToken(aliceToken)))
+	if s.Login("alice") == "" {
+		t.Fatal("expected a token after disabling two-factor authentication")
+	}
+}
+
+func TestTwoFactorRequiredForAdmins(t *stdtesting.T) {
+	s := testing.NewServer(t)
+	s.CreateUser("root")
+	s.SetRole("root", known.RoleAdmin)
+	rootToken := s.Login("root")
+
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/root/unlock", nil, testing.WithToken(rootToken)), errno.ErrTwoFactorRequired)
+
+	_, recoveryCodes := s.EnableTwoFactor(rootToken, "root")
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/users/root/unlock", nil, testing.WithToken(rootToken)))
+	// 管理员不能关闭两步验证
+	testing.AssertErrno(t, s.Do(http.MethodDelete, "/v1/users/root/2fa", v1.TwoFactorCodeRequest{Code: recoveryCodes[0]}, testing.WithToken(rootToken)), errno.ErrTwoFactorRequired)
+
+	// 关闭 require-for-admins 后，管理员无需开启两步验证
+	s = testing.NewServer(t, testing.WithConfig("auth.two-factor.require-for-admins", false))
+	s.CreateUser("root")
+	s.SetRole("root", known.RoleAdmin)
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/users/root/unlock", nil, testing.WithToken(s.Login("root"))))
+}
+
+func TestVerifyEmail(t *stdtesting.T) {
+	s := testing.NewServer(t)
+	s.CreateUser("alice")
+	s.CreateUser("bob")
+	aliceToken := s.Login("alice")
+
+	// 创建用户时会发送验证邮件
+	mail := s.LastMail("alice@example.com")
+	if !strings.Contains(mail.Subject, "Verify") || !strings.Contains(mail.Body, "http://127.0.0.1:8080/verify-email?") {
+		t.Fatalf("unexpected verification mail: %+v", mail)
+	}
+	token := mail.Token(t)
+
+	// token 只能用于签发时的用户，且不能作为 JWT Token 使用
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/bob/verify-email/confirm", v1.VerifyEmailRequest{Token: token}), errno.ErrOneTimeTokenInvalid)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/verify-email", nil, testing.WithToken(token)), errno.ErrTokenInvalid)
+
+	// 重新发送验证邮件，之前的 token 仍然有效，验证成功后所有 token 失效
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/users/alice/verify-email", nil, testing.WithToken(aliceToken)))
+	if n := len(s.Mails("alice@example.com")); n != 2 {
+		t.Fatalf("want 2 mails, got %d", n)
+	}
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/users/alice/verify-email/confirm", v1.VerifyEmailRequest{Token: token}))
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/verify-email/confirm", v1.VerifyEmailRequest{Token: s.LastMail("alice@example.com").Token(t)}), errno.ErrOneTimeTokenInvalid)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/verify-email", nil, testing.WithToken(aliceToken)), errno.ErrEmailAlreadyVerified)
+
+	var user model.UserM
+	if err := s.DB.Where("username = ?", "alice").First(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !user.EmailVerified {
+		t.Fatal("email was not marked as verified")
+	}
+}
+
+func TestPasswordReset(t *stdtesting.T) {
+	s := testing.NewServer(t, testing.WithConfig("auth.lockout.delay", 0))
+	s.CreateUser("alice")
+
+	// 邮箱不存在时同样返回成功，但不会发送邮件
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/password-reset", v1.PasswordResetRequest{Email: "nobody@example.com"}))
+	if n := len(s.Mails("nobody@example.com")); n != 0 {
+		t.Fatalf("want no mails, got %d", n)
+	}
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/password-reset", v1.PasswordResetRequest{Email: "not-an-email"}), errno.ErrInvalidParam)
+
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/password-reset", v1.PasswordResetRequest{Email: "ALICE@example.com"}))
+	mail := s.LastMail("alice@example.com")
+	if !strings.Contains(mail.Subject, "Reset") || !strings.Contains(mail.Body, "http://127.0.0.1:8080/reset-password?") {
+		t.Fatalf("unexpected password reset mail: %+v", mail)
+	}
+	token := mail.Token(t)
+
+	// 新密码同样需要符合密码策略
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/password-reset/confirm", v1.ConfirmPasswordResetRequest{Token: token, NewPassword: "short"}), errno.ErrPasswordTooShort)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/password-reset/confirm", v1.ConfirmPasswordResetRequest{Token: "invalid", NewPassword: "new-password-1234"}), errno.ErrOneTimeTokenInvalid)
+
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/password-reset/confirm", v1.ConfirmPasswordResetRequest{Token: token, NewPassword: "new-password-1234"}))
+	// token 只能使用一次
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/password-reset/confirm", v1.ConfirmPasswordResetRequest{Token: token, NewPassword: "another-password-1234"}), errno.ErrOneTimeTokenInvalid)
+
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/login", v1.LoginRequest{Username: "alice", Password: testing.DefaultPassword}), errno.ErrInvalidCredentials)
+	testing.AssertOK(t, s.Do(http.MethodPost, "/login", v1.LoginRequest{Username: "alice", Password: "new-password-1234"}))
+}
+
+func TestAPIKeys(t *stdtesting.T) {
+	s := testing.NewServer(t)
+	s.CreateUser("root")
+	s.CreateUser("alice")
+	s.SetRole("root", known.RoleAdmin)
+	rootToken := s.Login("root")
+	aliceToken := s.Login("alice")
+	s.EnableTwoFactor(rootToken, "root")
+
+	createKey := func(token, username string, req v1.CreateAPIKeyRequest) *v1.CreateAPIKeyResponse {
+		t.Helper()
+		w := s.Do(http.MethodPost, "/v1/users/"+username+"/api-keys", req, testing.WithToken(token))
+		testing.AssertOK(t, w)
+		var resp v1.CreateAPIKeyResponse
+		testing.DecodeJSON(t, w, &resp)
+		return &resp
+	}
+	withKey := func(key string) testing.RequestOption {
+		return testing.WithHeader("Authorization", "ApiKey "+key)
+	}
+
+	// scope 必须有效，只有管理员可以授予 users:admin，同一个用户的名称不能重复
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/api-keys", v1.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"posts:delete"}}, testing.WithToken(aliceToken)), errno.ErrInvalidParam)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/api-keys", v1.CreateAPIKeyRequest{Name: "ci", Scopes: []string{known.ScopeUsersAdmin}}, testing.WithToken(aliceToken)), errno.ErrPermissionDenied)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/root/api-keys", v1.CreateAPIKeyRequest{Name: "ci", Scopes: []string{known.ScopePostsRead}}, testing.WithToken(aliceToken)), errno.ErrPermissionDenied)
+	past := time.Now().Add(-time.Minute)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/api-keys", v1.CreateAPIKeyRequest{Name: "ci", Scopes: []string{known.ScopePostsRead}, ExpiresAt: &past}, testing.WithToken(aliceToken)), errno.ErrInvalidParam)
+	createKey(aliceToken, "alice", v1.CreateAPIKeyRequest{Name: "ci", Scopes: []string{known.ScopePostsWrite}})
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/api-keys", v1.CreateAPIKeyRequest{Name: "ci", Scopes: []string{known.ScopePostsRead}}, testing.WithToken(aliceToken)), errno.ErrAPIKeyAlreadyExist)
+
+	// 带有 users:admin 的 API Key 可以调用管理接口，缺少 scope 或使用错误的 key 时拒绝
+	admin := createKey(rootToken, "root", v1.CreateAPIKeyRequest{Name: "ops", Scopes: []string{known.ScopeUsersAdmin}})
+	posts := createKey(rootToken, "root", v1.CreateAPIKeyRequest{Name: "posts", Scopes: []string{known.ScopePostsWrite}})
+	if admin.Prefix == "" || !strings.Contains(admin.Key, admin.Prefix) {
+		t.Fatalf("unexpected api key: %+v", admin)
+	}
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/users/alice/unlock", nil, withKey(admin.Key)))
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/unlock", nil, withKey(posts.Key)), errno.ErrInsufficientScope)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/unlock", nil, withKey(admin.Key+"x")), errno.ErrAPIKeyInvalid)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/unlock", nil, withKey("not-a-key")), errno.ErrAPIKeyInvalid)
+
+	// 账户安全相关的接口不接受 API Key，API Key 不能用来创建新的 API Key
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/root/api-keys", v1.CreateAPIKeyRequest{Name: "more", Scopes: []string{known.ScopePostsRead}}, withKey(admin.Key)), errno.ErrAPIKeyNotAllowed)
+	testing.AssertErrno(t, s.Do(http.MethodPut, "/v1/users/root/change-password", v1.ChangePasswordRequest{OldPassword: testing.DefaultPassword, NewPassword: "miniblog5678"}, withKey(admin.Key)), errno.ErrAPIKeyNotAllowed)
+
+	// 列表中不包含 API Key 本身，并记录了最近使用时间
+	w := s.Do(http.MethodGet, "/v1/users/root/api-keys", nil, testing.WithToken(rootToken))
+	testing.AssertOK(t, w)
+	if strings.Contains(w.Body.String(), admin.Key) {
+		t.Fatal("api key is returned by the list endpoint")
+	}
+	var list v1.ListAPIKeysResponse
+	testing.DecodeJSON(t, w, &list)
+	if len(list.APIKeys) != 2 || list.APIKeys[0].Prefix != admin.Prefix || list.APIKeys[0].LastUsedAt == nil || list.APIKeys[1].LastUsedAt != nil {
+		t.Fatalf("unexpected api keys: %+v", list.APIKeys)
+	}
+
+	// 删除后立即失效，只能删除自己的 API Key
+	testing.AssertErrno(t, s.Do(http.MethodDelete, "/v1/users/root/api-keys/"+admin.Prefix, nil, testing.WithToken(aliceToken)), errno.ErrPermissionDenied)
+	testing.AssertOK(t, s.Do(http.MethodDelete, "/v1/users/root/api-keys/"+admin.Prefix, nil, testing.WithToken(rootToken)))
+	testing.AssertErrno(t, s.Do(http.MethodDelete, "/v1/users/root/api-keys/"+admin.Prefix, nil, testing.WithToken(rootToken)), errno.ErrAPIKeyNotFound)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/unlock", nil, withKey(admin.Key)), errno.ErrAPIKeyInvalid)
+
+	// 过期的 API Key 不能再使用
+	expiring := createKey(rootToken, "root", v1.CreateAPIKeyRequest{Name: "expiring", Scopes: []string{known.ScopeUsersAdmin}})
+	if err := s.DB.Model(&model.APIKeyM{}).Where("prefix = ?", expiring.Prefix).Update("expiresAt", past).Error; err != nil {
+		t.Fatal(err)
+	}
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users/alice/unlock", nil, withKey(expiring.Key)), errno.ErrAPIKeyInvalid)
+}
+
+func TestOIDCLogin(t *stdtesting.T) {
+	testing.AssertErrno(t, testing.NewServer(t).Do(http.MethodPost, "/login/oidc", nil), errno.ErrOIDCDisabled)
+
+	idp := oidctest.NewServer(t)
+	s := testing.NewServer(t,
+		testing.WithConfig("auth.oidc.enabled", true),
+		testing.WithConfig("auth.oidc.issuer", idp.Issuer()),
+		testing.WithConfig("auth.oidc.client-id", oidctest.ClientID),
+		testing.WithConfig("auth.oidc.client-secret", oidctest.ClientSecret),
+		testing.WithConfig("auth.oidc.redirect-url", "http://127.0.0.1:8080/login/oidc/callback"),
+		testing.WithConfig("auth.oidc.admin-groups", []string{"blog-admins"}),
+		testing.WithConfig("auth.oidc.auto-provision", true),
+		testing.WithConfig("auth.oidc.link-by-email", true),
+	)
+	s.CreateUser("alice")
+
+	// start 模拟用户在 IdP 以 claims 的身份完成登录，返回授权码和回调请求
+	start := func(claims map[string]any) (string, v1.OIDCCallbackRequest) {
+		t.Helper()
+		w := s.Do(http.MethodPost, "/login/oidc", nil)
+		testing.AssertOK(t, w)
+		var resp v1.OIDCLoginResponse
+		testing.DecodeJSON(t, w, &resp)
+
+		code, state := idp.Authorize(t, resp.AuthURL, claims)
+		return code, v1.OIDCCallbackRequest{Code: code, State: state, Session: resp.Session}
+	}
+	login := func(claims map[string]any) *v1.LoginResponse {
+		t.Helper()
+		_, req := start(claims)
+		w := s.Do(http.MethodPost, "/login/oidc/callback", req)
+		testing.AssertOK(t, w)
+		var resp v1.LoginResponse
+		testing.DecodeJSON(t, w, &resp)
+		return &resp
+	}
+	role := func(username string) string {
+		t.Helper()
+		var user model.UserM
+		if err := s.DB.Where("username = ?", username).First(&user).Error; err != nil {
+			t.Fatal(err)
+		}
+		return user.Role
+	}
+
+	// 未验证的邮箱不能用于绑定，同名用户已存在或用户名无效时不能自动创建
+	_, req := start(map[string]any{"sub": "1001", "preferred_username": "alice", "email": "alice@example.com", "email_verified": false})
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/login/oidc/callback", req), errno.ErrUserAlreadyExist)
+	for _, username := range []any{nil, "not valid"} {
+		_, req = start(map[string]any{"sub": "1001", "preferred_username": username})
+		testing.AssertErrno(t, s.Do(http.MethodPost, "/login/oidc/callback", req), errno.ErrOIDCAccountNotLinked)
+	}
+
+	// state 不匹配或授权码重复使用时登录失败
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/login/oidc/callback", req), errno.ErrOIDCLoginFailed)
+	_, req = start(map[string]any{"sub": "1001"})
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/login/oidc/callback", v1.OIDCCallbackRequest{Code: req.Code, State: "other", Session: req.Session}), errno.ErrOIDCLoginFailed)
+
+	// 本地用户的邮箱未验证时不能绑定，避免攻击者先用他人的邮箱注册再接管其外部身份
+	_, req = start(map[string]any{"sub": "1001", "email": "alice@example.com", "email_verified": true})
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/login/oidc/callback", req), errno.ErrOIDCAccountNotLinked)
+
+	// 通过双方都已验证的邮箱绑定已有用户，之后按照 subject 找到该用户
+	if err := s.DB.Model(&model.UserM{}).Where("username = ?", "alice").Update("emailVerified", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	resp := login(map[string]any{"sub": "1001", "email": "alice@example.com", "email_verified": true})
+	testing.AssertOK(t, s.Do(http.MethodGet, "/v1/users/alice/api-keys", nil, testing.WithToken(resp.Token)))
+	resp = login(map[string]any{"sub": "1001", "email": "alice@example.org"})
+	testing.AssertOK(t, s.Do(http.MethodGet, "/v1/users/alice/api-keys", nil, testing.WithToken(resp.Token)))
+
+	// 自动创建用户，并按照用户组同步角色
+	resp = login(map[string]any{"sub": "2002", "preferred_username": "bob", "email": "bob@example.com", "email_verified": true, "groups": []string{"staff", "blog-admins"}})
+	testing.AssertOK(t, s.Do(http.MethodGet, "/v1/users/bob/api-keys", nil, testing.WithToken(resp.Token)))
+	if got := role("bob"); got != known.RoleAdmin {
+		t.Fatalf("want role %s, got %s", known.RoleAdmin, got)
+	}
+	login(map[string]any{"sub": "2002", "preferred_username": "bob", "groups": []string{"staff"}})
+	if got := role("bob"); got != known.RoleUser {
+		t.Fatalf("want role %s, got %s", known.RoleUser, got)
+	}
+
+	// 开启了两步验证的用户需要继续完成两步登录
+	s.EnableTwoFactor(s.Login("alice"), "alice")
+	if resp := login(map[string]any{"sub": "1001"}); resp.Token != "" || !resp.TwoFactorRequired || resp.Challenge == "" {
+		t.Fatalf("unexpected login response: %+v", resp)
+	}
+}
+
+func TestRegistrationModes(t *stdtesting.T) {
+	s := testing.NewServer(t, testing.WithConfig("registration.mode", known.RegistrationClosed))
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users", testing.NewCreateUserRequest("alice")), errno.ErrRegistrationClosed)
+
+	s = testing.NewServer(t, testing.WithConfig("registration.mode", known.RegistrationInviteOnly))
+	// invite-only 模式下通过数据库创建第一个管理员
+	hashed, err := auth.DefaultHasher().Encrypt(testing.DefaultPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Store.Users().Create(context.Background(), &model.UserM{Username: "root", Password: hashed, Nickname: "root", Email: "root@example.com", Role: known.RoleAdmin}); err != nil {
+		t.Fatal(err)
+	}
+	rootToken := s.Login("root")
+	s.EnableTwoFactor(rootToken, "root")
+
+	register := func(username, code string) *httptest.ResponseRecorder {
+		req := testing.NewCreateUserRequest(username)
+		req.InviteCode = code
+		return s.Do(http.MethodPost, "/v1/users", req)
+	}
+	testing.AssertErrno(t, register("alice", ""), errno.ErrInvitationInvalid)
+	testing.AssertErrno(t, register("alice", "UNKNOWN"), errno.ErrInvitationInvalid)
+
+	w := s.Do(http.MethodPost, "/v1/invitations", v1.CreateInvitationRequest{MaxUses: 2}, testing.WithToken(rootToken))
+	testing.AssertOK(t, w)
+	var invitation v1.Invitation
+	testing.DecodeJSON(t, w, &invitation)
+	if invitation.Code == "" || invitation.MaxUses != 2 || invitation.CreatedBy != "root" {
+		t.Fatalf("unexpected invitation: %+v", invitation)
+	}
+
+	// 创建用户失败时不消耗邀请码，用完后不能再使用
+	testing.AssertOK(t, register("alice", invitation.Code))
+	testing.AssertErrno(t, register("alice", invitation.Code), errno.ErrUserAlreadyExist)
+	testing.AssertOK(t, register("bob", invitation.Code))
+	testing.AssertErrno(t, register("carol", invitation.Code), errno.ErrInvitationInvalid)
+
+	// 只有管理员可以管理邀请码
+	aliceToken := s.Login("alice")
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/invitations", v1.CreateInvitationRequest{}, testing.WithToken(aliceToken)), errno.ErrPermissionDenied)
+	testing.AssertErrno(t, s.Do(http.MethodGet, "/v1/invitations", nil, testing.WithToken(aliceToken)), errno.ErrPermissionDenied)
+
+	past := time.Now().Add(-time.Minute)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/invitations", v1.CreateInvitationRequest{ExpiresAt: &past}, testing.WithToken(rootToken)), errno.ErrInvalidParam)
+	w = s.Do(http.MethodPost, "/v1/invitations", v1.CreateInvitationRequest{}, testing.WithToken(rootToken))
+	testing.AssertOK(t, w)
+	var single v1.Invitation
+	testing.DecodeJSON(t, w, &single)
+
+	w = s.Do(http.MethodGet, "/v1/invitations", nil, testing.WithToken(rootToken))
+	testing.AssertOK(t, w)
+	var list v1.ListInvitationsResponse
+	testing.DecodeJSON(t, w, &list)
+	if len(list.Invitations) != 2 || list.Invitations[0].Uses != 2 || list.Invitations[1].MaxUses != 1 {
+		t.Fatalf("unexpected invitations: %+v", list.Invitations)
+	}
+
+	// 删除后不能再使用
+	testing.AssertOK(t, s.Do(http.MethodDelete, "/v1/invitations/"+single.Code, nil, testing.WithToken(rootToken)))
+	testing.AssertErrno(t, s.Do(http.MethodDelete, "/v1/invitations/"+single.Code, nil, testing.WithToken(rootToken)), errno.ErrInvitationNotFound)
+	testing.AssertErrno(t, register("carol", single.Code), errno.ErrInvitationInvalid)
+}
+
+func TestPosts(t *stdtesting.T) {
+	s := testing.NewServer(t)
+	s.CreateUser("alice")
+	s.CreateUser("bob")
+	aliceToken := s.Login("alice")
+	bobToken := s.Login("bob")
+
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/posts", v1.CreatePostRequest{Title: "hello"}), errno.ErrTokenInvalid)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/posts", v1.CreatePostRequest{Title: "hello"}, testing.WithToken(aliceToken)), errno.ErrInvalidParam)
+	first := s.CreatePost(aliceToken, "first")
+	second := s.CreatePost(aliceToken, "second")
+	s.CreatePost(bobToken, "bob")
+
+	listPosts := func(query string) *v1.ListPostsResponse {
+		t.Helper()
+		w := s.Do(http.MethodGet, "/v1/posts"+query, nil, testing.WithToken(bobToken))
+		testing.AssertOK(t, w)
+		var resp v1.ListPostsResponse
+		testing.DecodeJSON(t, w, &resp)
+		return &resp
+	}
+	if resp := listPosts("?username=alice&limit=1"); resp.TotalCount != 2 || len(resp.Posts) != 1 || resp.Posts[0].PostID != second {
+		t.Fatalf("unexpected posts: %+v", resp)
+	}
+	if resp := listPosts(""); resp.TotalCount != 3 {
+		t.Fatalf("want 3 posts, got %d", resp.TotalCount)
+	}
+	testing.AssertErrno(t, s.Do(http.MethodGet, "/v1/posts?limit=1000", nil, testing.WithToken(bobToken)), errno.ErrInvalidParam)
+
+	// 只有作者可以修改和删除博客
+	title := "updated"
+	testing.AssertErrno(t, s.Do(http.MethodPut, "/v1/posts/"+first, v1.UpdatePostRequest{Title: &title}, testing.WithToken(bobToken)), errno.ErrPermissionDenied)
+	testing.AssertErrno(t, s.Do(http.MethodDelete, "/v1/posts/"+first, nil, testing.WithToken(bobToken)), errno.ErrPermissionDenied)
+	testing.AssertOK(t, s.Do(http.MethodPut, "/v1/posts/"+first, v1.UpdatePostRequest{Title: &title}, testing.WithToken(aliceToken)))
+
+	w := s.Do(http.MethodGet, "/v1/posts/"+first, nil, testing.WithToken(bobToken))
+	testing.AssertOK(t, w)
+	var post v1.Post
+	testing.DecodeJSON(t, w, &post)
+	if post.Title != "updated" || post.Content != "first content" || post.Username != "alice" || post.DeletedAt != nil {
+		t.Fatalf("unexpected post: %+v", post)
+	}
+
+	testing.AssertOK(t, s.Do(http.MethodDelete, "/v1/posts/"+first, nil, testing.WithToken(aliceToken)))
+	testing.AssertErrno(t, s.Do(http.MethodGet, "/v1/posts/"+first, nil, testing.WithToken(aliceToken)), errno.ErrPostNotFound)
+	testing.AssertErrno(t, s.Do(http.MethodDelete, "/v1/posts/"+first, nil, testing.WithToken(aliceToken)), errno.ErrPostNotFound)
+}
+
+func TestTrash(t *stdtesting.T) {
+	s := testing.NewServer(t)
+	s.CreateUser("root")
+	s.CreateUser("alice")
+	s.SetRole("root", known.RoleAdmin)
+	rootToken := s.Login("root")
+	aliceToken := s.Login("alice")
+	s.EnableTwoFactor(rootToken, "root")
+
+	deleted := s.CreatePost(aliceToken, "deleted")
+	kept := s.CreatePost(aliceToken, "kept")
+	testing.AssertOK(t, s.Do(http.MethodDelete, "/v1/posts/"+deleted, nil, testing.WithToken(aliceToken)))
+
+	// 只有管理员可以查看和恢复回收站
+	testing.AssertErrno(t, s.Do(http.MethodGet, "/v1/trash/posts", nil, testing.WithToken(aliceToken)), errno.ErrPermissionDenied)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/trash/posts/"+deleted+"/restore", nil, testing.WithToken(aliceToken)), errno.ErrPermissionDenied)
+
+	w := s.Do(http.MethodGet, "/v1/trash/posts", nil, testing.WithToken(rootToken))
+	testing.AssertOK(t, w)
+	var posts v1.ListPostsResponse
+	testing.DecodeJSON(t, w, &posts)
+	if posts.TotalCount != 1 || posts.Posts[0].PostID != deleted || posts.Posts[0].DeletedAt == nil {
+		t.Fatalf("unexpected deleted posts: %+v", posts)
+	}
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/trash/posts/"+deleted+"/restore", nil, testing.WithToken(rootToken)))
+	testing.AssertOK(t, s.Do(http.MethodGet, "/v1/posts/"+deleted, nil, testing.WithToken(aliceToken)))
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/trash/posts/"+deleted+"/restore", nil, testing.WithToken(rootToken)), errno.ErrPostNotFound)
+
+	// 删除用户时一起删除该用户的博客，已删除的用户不能登录，也不能使用删除前签发的 Token 发布博客
+	testing.AssertOK(t, s.Do(http.MethodDelete, "/v1/posts/"+deleted, nil, testing.WithToken(aliceToken)))
+	testing.AssertOK(t, s.Do(http.MethodDelete, "/v1/users/alice", nil, testing.WithToken(aliceToken)))
+	testing.AssertErrno(t, s.Do(http.MethodGet, "/v1/posts/"+kept, nil, testing.WithToken(rootToken)), errno.ErrPostNotFound)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/posts", v1.CreatePostRequest{Title: "t", Content: "c"}, testing.WithToken(aliceToken)), errno.ErrUserNotFound)
+	if w := s.Do(http.MethodPost, "/login", v1.LoginRequest{Username: "alice", Password: testing.DefaultPassword}); w.Code == http.StatusOK {
+		t.Fatal("deleted user logged in")
+	}
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/trash/posts/"+kept+"/restore", nil, testing.WithToken(rootToken)), errno.ErrPostAuthorDeleted)
+
+	w = s.Do(http.MethodGet, "/v1/trash/users", nil, testing.WithToken(rootToken))
+	testing.AssertOK(t, w)
+	var users v1.ListUsersResponse
+	testing.DecodeJSON(t, w, &users)
+	if users.TotalCount != 1 || users.Users[0].Username != "alice" {
+		t.Fatalf("unexpected deleted users: %+v", users)
+	}
+
+	// 恢复用户时只恢复与用户一起删除的博客，此前单独删除的博客仍在回收站中
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/trash/users/alice/restore", nil, testing.WithToken(rootToken)))
+	testing.AssertOK(t, s.Do(http.MethodGet, "/v1/posts/"+kept, nil, testing.WithToken(rootToken)))
+	testing.AssertErrno(t, s.Do(http.MethodGet, "/v1/posts/"+deleted, nil, testing.WithToken(rootToken)), errno.ErrPostNotFound)
+	s.Login("alice")
+
+	// 管理员可以删除其他用户，普通用户不能
+	testing.AssertErrno(t, s.Do(http.MethodDelete, "/v1/users/root", nil, testing.WithToken(aliceToken)), errno.ErrPermissionDenied)
+	testing.AssertOK(t, s.Do(http.MethodDelete, "/v1/users/alice", nil, testing.WithToken(rootToken)))
+	testing.AssertErrno(t, s.Do(http.MethodDelete, "/v1/users/alice", nil, testing.WithToken(rootToken)), errno.ErrUserNotFound)
+}
+
+func TestPostStatus(t *stdtesting.T) {
+	s := testing.NewServer(t)
+	s.CreateUser("alice")
+	s.CreateUser("bob")
+	aliceToken := s.Login("alice")
+	bobToken := s.Login("bob")
+
+	createPost := func(req v1.CreatePostRequest) string {
+		t.Helper()
+		w := s.Do(http.MethodPost, "/v1/posts", req, testing.WithToken(aliceToken))
+		testing.AssertOK(t, w)
+		var resp v1.CreatePostResponse
+		testing.DecodeJSON(t, w, &resp)
+		return resp.PostID
+	}
+	getPost := func(postID string) *v1.Post {
+		t.Helper()
+		w := s.Do(http.MethodGet, "/v1/posts/"+postID, nil, testing.WithToken(aliceToken))
+		testing.AssertOK(t, w)
+		var post v1.Post
+		testing.DecodeJSON(t, w, &post)
+		return &post
+	}
+	countPosts := func(token, query string) int64 {
+		t.Helper()
+		w := s.Do(http.MethodGet, "/v1/posts"+query, nil, testing.WithToken(token))
+		testing.AssertOK(t, w)
+		var resp v1.ListPostsResponse
+		testing.DecodeJSON(t, w, &resp)
+		return resp.TotalCount
+	}
+
+	published := s.CreatePost(aliceToken, "published")
+	if post := getPost(published); post.Status != known.PostStatusPublished || post.PublishAt == nil {
+		t.Fatalf("unexpected published post: %+v", post)
+	}
+	draft := createPost(v1.CreatePostRequest{Title: "draft", Content: "content", Status: known.PostStatusDraft})
+	publishAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	scheduled := createPost(v1.CreatePostRequest{Title: "scheduled", Content: "content", PublishAt: &publishAt})
+	if post := getPost(scheduled); post.Status != known.PostStatusScheduled || !post.PublishAt.Equal(publishAt) {
+		t.Fatalf("unexpected scheduled post: %+v", post)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/posts", v1.CreatePostRequest{Title: "t", Content: "c", PublishAt: &past}, testing.WithToken(aliceToken)), errno.ErrInvalidParam)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/posts", v1.CreatePostRequest{Title: "t", Content: "c", Status: known.PostStatusScheduled}, testing.WithToken(aliceToken)), errno.ErrInvalidParam)
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/posts", v1.CreatePostRequest{Title: "t", Content: "c", Status: "hidden"}, testing.WithToken(aliceToken)), errno.ErrInvalidParam)
+
+	// 未发布的博客只有作者可以看到
+	testing.AssertErrno(t, s.Do(http.MethodGet, "/v1/posts/"+draft, nil, testing.WithToken(bobToken)), errno.ErrPostNotFound)
+	testing.AssertErrno(t, s.Do(http.MethodGet, "/v1/posts/"+scheduled+"/revisions", nil, testing.WithToken(bobToken)), errno.ErrPostNotFound)
+	if count := countPosts(bobToken, "?username=alice"); count != 1 {
+		t.Fatalf("want 1 visible post for bob, got %d", count)
+	}
+	if count := countPosts(aliceToken, ""); count != 3 {
+		t.Fatalf("want 3 visible posts for alice, got %d", count)
+	}
+	if count := countPosts(aliceToken, "?status=draft"); count != 1 {
+		t.Fatalf("want 1 draft, got %d", count)
+	}
+
+	// 多个实例同时发布时，每篇博客只会被发布一次
+	var total int64
+	results := make(chan int64, 3)
+	for i := 0; i < cap(results); i++ {
+		go func() {
+			n, err := post.New(s.Store).PublishDue(context.Background(), publishAt.Add(time.Minute))
+			if err != nil {
+				t.Errorf("failed to publish due posts: %v", err)
+			}
+			results <- n
+		}()
+	}
+	for i := 0; i < cap(results); i++ {
+		total += <-results
+	}
+	if total != 1 {
+		t.Fatalf("want 1 post published, got %d", total)
+	}
+	if post := getPost(scheduled); post.Status != known.PostStatusPublished || !post.PublishAt.Equal(publishAt) {
+		t.Fatalf("unexpected post after publish: %+v", post)
+	}
+	testing.AssertOK(t, s.Do(http.MethodGet, "/v1/posts/"+scheduled, nil, testing.WithToken(bobToken)))
+
+	// 归档后其他用户看不到，草稿可以直接发布
+	archived := known.PostStatusArchived
+	testing.AssertOK(t, s.Do(http.MethodPut, "/v1/posts/"+published, v1.UpdatePostRequest{Status: &archived}, testing.WithToken(aliceToken)))
+	testing.AssertErrno(t, s.Do(http.MethodGet, "/v1/posts/"+published, nil, testing.WithToken(bobToken)), errno.ErrPostNotFound)
+	publish := known.PostStatusPublished
+	testing.AssertOK(t, s.Do(http.MethodPut, "/v1/posts/"+draft, v1.UpdatePostRequest{Status: &publish}, testing.WithToken(aliceToken)))
+	if post := getPost(draft); post.Status != known.PostStatusPublished || post.PublishAt == nil {
+		t.Fatalf("unexpected post after publish: %+v", post)
+	}
+	if count := countPosts(bobToken, ""); count != 2 {
+		t.Fatalf("want 2 visible posts for bob, got %d", count)
+	}
+}
+
+func TestPostFormat(t *stdtesting.T) {
+	s := testing.NewServer(t)
+	s.CreateUser("alice")
+	token := s.Login("alice")
+
+	getPost := func(postID string) *v1.Post {
+		t.Helper()
+		w := s.Do(http.MethodGet, "/v1/posts/"+postID, nil, testing.WithToken(token))
+		testing.AssertOK(t, w)
+		var post v1.Post
+		testing.DecodeJSON(t, w, &post)
+		return &post
+	}
+
+	// 默认使用 Markdown，返回原始内容和过滤后的 HTML
+	source := "# Hello\n\n| a | b |\n|---|---|\n| 1 | 2 |\n\n<script>alert(1)</script>\n\n[x](javascript:alert(1))\n"
+	w := s.Do(http.MethodPost, "/v1/posts", v1.CreatePostRequest{Title: "markdown", Content: source}, testing.WithToken(token))
+	testing.AssertOK(t, w)
+	var resp v1.CreatePostResponse
+	testing.DecodeJSON(t, w, &resp)
+	post := getPost(resp.PostID)
+	if post.Format != known.PostFormatMarkdown || post.Content != source {
+		t.Fatalf("unexpected post: %+v", post)
+	}
+	if !strings.Contains(post.ContentHTML, "<h1>Hello</h1>") || !strings.Contains(post.ContentHTML, "<td>1</td>") ||
+		strings.Contains(post.ContentHTML, "script") || strings.Contains(post.ContentHTML, "javascript") {
+		t.Fatalf("unexpected rendered html: %q", post.ContentHTML)
+	}
+
+	// 修改格式后重新渲染，并创建一个新版本
+	plain := known.PostFormatPlain
+	testing.AssertOK(t, s.Do(http.MethodPut, "/v1/posts/"+resp.PostID, v1.UpdatePostRequest{Format: &plain}, testing.WithToken(token)))
+	if post := getPost(resp.PostID); !strings.HasPrefix(post.ContentHTML, "<p># Hello</p>") {
+		t.Fatalf("content was not rendered as plain text: %q", post.ContentHTML)
+	}
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/posts/"+resp.PostID+"/revisions/1/restore", nil, testing.WithToken(token)))
+	if post := getPost(resp.PostID); post.Format != known.PostFormatMarkdown || !strings.Contains(post.ContentHTML, "<h1>Hello</h1>") {
+		t.Fatalf("format was not restored: %+v", post)
+	}
+
+	w = s.Do(http.MethodPost, "/v1/posts", v1.CreatePostRequest{Title: "html", Content: `<p onclick="x()">hi</p><img src="a.png" onerror="x()">`, Format: known.PostFormatHTML}, testing.WithToken(token))
+	testing.AssertOK(t, w)
+	testing.DecodeJSON(t, w, &resp)
+	if post := getPost(resp.PostID); post.ContentHTML != `<p>hi</p><img src="a.png">` {
+		t.Fatalf("html was not sanitized: %q", post.ContentHTML)
+	}
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/posts", v1.CreatePostRequest{Title: "t", Content: "c", Format: "rst"}, testing.WithToken(token)), errno.ErrInvalidParam)
+
+	// 支持内容格式之前发布的博客在读取时渲染
+	legacy := s.CreatePost(token, "legacy")
+	if err := s.DB.Model(&model.PostM{}).Where("title = ?", "legacy").Update("contentHTML", "").Error; err != nil {
+		t.Fatalf("failed to clear rendered html: %v", err)
+	}
+	if post := getPost(legacy); post.ContentHTML != "<p>legacy content</p>\n" {
+		t.Fatalf("unexpected html of legacy post: %q", post.ContentHTML)
+	}
+}
+
+func TestPostRevisions(t *stdtesting.T) {
+	s := testing.NewServer(t)
+	s.CreateUser("alice")
+	s.CreateUser("bob")
+	aliceToken := s.Login("alice")
+	bobToken := s.Login("bob")
+
+	postID := s.CreatePost(aliceToken, "first")
+	base := "/v1/posts/" + postID
+	for _, content := range []string{"one\ntwo\nthree\n", "one\n2\nthree\n"} {
+		content := content
+		testing.AssertOK(t, s.Do(http.MethodPut, base, v1.UpdatePostRequest{Content: &content}, testing.WithToken(aliceToken)))
+	}
+	// 内容没有变化时不创建新版本
+	title := "first"
+	testing.AssertOK(t, s.Do(http.MethodPut, base, v1.UpdatePostRequest{Title: &title}, testing.WithToken(aliceToken)))
+
+	listRevisions := func() *v1.ListPostRevisionsResponse {
+		t.Helper()
+		w := s.Do(http.MethodGet, base+"/revisions", nil, testing.WithToken(bobToken))
+		testing.AssertOK(t, w)
+		var resp v1.ListPostRevisionsResponse
+		testing.DecodeJSON(t, w, &resp)
+		return &resp
+	}
+	resp := listRevisions()
+	if resp.TotalCount != 3 || resp.Revisions[0].Revision != 3 || resp.Revisions[0].Username != "alice" || resp.Revisions[0].Content != "" {
+		t.Fatalf("unexpected revisions: %+v", resp)
+	}
+
+	w := s.Do(http.MethodGet, base+"/revisions/1", nil, testing.WithToken(bobToken))
+	testing.AssertOK(t, w)
+	var revision v1.PostRevision
+	testing.DecodeJSON(t, w, &revision)
+	if revision.Title != "first" || revision.Content != "first content" || len(revision.ContentHash) != 64 {
+		t.Fatalf("unexpected revision: %+v", revision)
+	}
+	testing.AssertErrno(t, s.Do(http.MethodGet, base+"/revisions/4", nil, testing.WithToken(bobToken)), errno.ErrPostRevisionNotFound)
+	testing.AssertErrno(t, s.Do(http.MethodGet, base+"/revisions/latest", nil, testing.WithToken(bobToken)), errno.ErrPostRevisionNotFound)
+
+	w = s.Do(http.MethodGet, base+"/diff?from=2", nil, testing.WithToken(bobToken))
+	testing.AssertOK(t, w)
+	var diff v1.DiffPostRevisionsResponse
+	testing.DecodeJSON(t, w, &diff)
+	if diff.From != 2 || diff.To != 3 || diff.Diff != "--- revision 2\n+++ revision 3\n@@ -1,3 +1,3 @@\n one\n-two\n+2\n three\n" {
+		t.Fatalf("unexpected diff: %+v", diff)
+	}
+	testing.AssertErrno(t, s.Do(http.MethodGet, base+"/diff", nil, testing.WithToken(bobToken)), errno.ErrInvalidParam)
+
+	// 只有作者可以回滚博客，回滚会创建一个新版本
+	testing.AssertErrno(t, s.Do(http.MethodPost, base+"/revisions/1/restore", nil, testing.WithToken(bobToken)), errno.ErrPermissionDenied)
+	testing.AssertOK(t, s.Do(http.MethodPost, base+"/revisions/1/restore", nil, testing.WithToken(aliceToken)))
+	w = s.Do(http.MethodGet, base, nil, testing.WithToken(bobToken))
+	testing.AssertOK(t, w)
+	var post v1.Post
+	testing.DecodeJSON(t, w, &post)
+	if post.Content != "first content" {
+		t.Fatalf("post was not restored: %+v", post)
+	}
+	if resp := listRevisions(); resp.TotalCount != 4 || resp.Revisions[0].ContentHash != revision.ContentHash {
+		t.Fatalf("unexpected revisions after restore: %+v", resp)
+	}
+}
+
+func TestCreateUserHashesPassword(t *stdtesting.T) {
+	s := testing.NewServer(t)
+	s.CreateUser("alice")
+
+	var user model.UserM
+	if err := s.DB.Where("username = ?", "alice").First(&user).Error; err != nil {
+		t.Fatalf("failed to query user: %v", err)
+	}
+	if user.Password == testing.DefaultPassword {
+		t.Fatal("password is stored in plaintext")
+	}
+	if _, err := auth.DefaultHasher().Compare(user.Password, testing.DefaultPassword); err != nil {
+		t.Fatalf("stored password does not match: %v", err)
+	}
+}
+
+func TestCreateUserRateLimit(t *stdtesting.T) {
+	s := testing.NewServer(t,
+		testing.WithConfig("ratelimit.enabled", true),
+		testing.WithConfig("ratelimit.groups.users", []map[string]any{
+			{"name": "users-per-ip", "key": "ip", "limit": 1, "period": "1h"},
+		}),
+	)
+
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/users", testing.NewCreateUserRequest("alice")))
+
+	w := s.Do(http.MethodPost, "/v1/users", testing.NewCreateUserRequest("bob"))
+	testing.AssertErrno(t, w, errno.ErrTooManyRequests)
+	if w.Header().Get("Retry-After") == "" {
+		t.Fatal("Retry-After header is missing")
+	}
+}
+
+func TestRateLimitTrustedProxies(t *stdtesting.T) {
+	policies := []map[string]any{{"name": "users-per-ip", "key": "ip", "limit": 1, "period": "1h"}}
+
+	// 默认不信任任何代理，伪造 X-Forwarded-For 无法绕过按 IP 的限流
+	s := testing.NewServer(t, testing.WithConfig("ratelimit.enabled", true), testing.WithConfig("ratelimit.groups.users", policies))
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/users", testing.NewCreateUserRequest("alice")))
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users", testing.NewCreateUserRequest("bob"), testing.WithHeader("X-Forwarded-For", "203.0.113.1")), errno.ErrTooManyRequests)
+
+	// httptest 请求的地址为 192.0.2.1，信任该代理后使用 X-Forwarded-For 中的客户端 IP
+	s = testing.NewServer(t,
+		testing.WithConfig("ratelimit.enabled", true),
+		testing.WithConfig("ratelimit.groups.users", policies),
+		testing.WithConfig("trusted-proxies", []string{"192.0.2.0/24"}),
+	)
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/users", testing.NewCreateUserRequest("alice"), testing.WithHeader("X-Forwarded-For", "203.0.113.1")))
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/users", testing.NewCreateUserRequest("bob"), testing.WithHeader("X-Forwarded-For", "203.0.113.2")))
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users", testing.NewCreateUserRequest("carol"), testing.WithHeader("X-Forwarded-For", "203.0.113.1")), errno.ErrTooManyRequests)
+}
+
+func TestRateLimitByUsername(t *stdtesting.T) {
+	s := testing.NewServer(t,
+		testing.WithConfig("ratelimit.enabled", true),
+		testing.WithConfig("ratelimit.groups.users", []map[string]any{
+			{"name": "users-per-user", "key": "username", "limit": 2, "period": "1h"},
+		}),
+	)
+	// 创建用户不需要认证，按 IP 限流
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/users", testing.NewCreateUserRequest("alice")))
+	testing.AssertOK(t, s.Do(http.MethodPost, "/v1/users", testing.NewCreateUserRequest("bob")))
+	testing.AssertErrno(t, s.Do(http.MethodPost, "/v1/users", testing.NewCreateUserRequest("carol")), errno.ErrTooManyRequests)
+
+	// 来自同一个 IP 的认证请求按用户名分别限流
+	aliceToken, bobToken := s.Login("alice"), s.Login("bob")
+	for i := 0; i < 2; i++ {
+		testing.AssertOK(t, s.Do(http.MethodGet, "/v1/users/alice/api-keys", nil, testing.WithToken(aliceToken)))
+	}
+	testing.AssertErrno(t, s.Do(http.MethodGet, "/v1/users/alice/api-keys", nil, testing.WithToken(aliceToken)), errno.ErrTooManyRequests)
+	testing.AssertOK(t, s.Do(http.MethodGet, "/v1/users/bob/api-keys", nil, testing.WithToken(bobToken)))
+}
+
+func TestRateLimitByUsernameRequiresAuthn(t *stdtesting.T) {
+	for _, group := range []string{"login", "password-reset"} {
+		cfg := viper.New()
+		cfg.Set("jwt-secret", "miniblog-test-secret")
+		cfg.Set("ratelimit.enabled", true)
+		cfg.Set("ratelimit.groups."+group, []map[string]any{{"name": "per-user", "key": "username", "limit": 1, "period": "1h"}})
+
+		app, err := miniblog.NewAppWithStore(cfg, store.NewStore(testing.NewDB(t), 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 这些分组没有需要认证的路由，按用户名限流的策略永远不会生效
+		if _, err := app.Engine(); err == nil || !strings.Contains(err.Error(), "ratelimit.groups."+group) {
+			t.Fatalf("%s: username keyed policy should be rejected, got %v", group, err)
+		}
+		_ = app.Close()
+	}
+}
+
+func TestRateLimitInvalidPolicy(t *stdtesting.T) {
+	for name, policies := range map[string]any{
+		"unparsable":  "per-ip",
+		"zero limit":  []map[string]any{{"name": "per-ip", "key": "ip", "limit": 0, "period": "1m"}},
+		"zero period": []map[string]any{{"name": "per-ip", "key": "ip", "limit": 5}},
+	} {
+		cfg := viper.New()
+		cfg.Set("jwt-secret", "miniblog-test-secret")
+		cfg.Set("ratelimit.enabled", true)
+		cfg.Set("ratelimit.groups.login", policies)
+
+		app, err := miniblog.NewAppWithStore(cfg, store.NewStore(testing.NewDB(t), 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 配置错误时启动失败，而不是让登录接口失去限流
+		if _, err := app.Engine(); err == nil || !strings.Contains(err.Error(), "ratelimit.groups.login") {
+			t.Fatalf("%s: invalid policy should be rejected, got %v", name, err)
+		}
+		_ = app.Close()
+	}
+}
+
+func TestCorsPreflight(t *stdtesting.T) {
+	s := testing.NewServer(t,
+		testing.WithConfig("cors.allow-origins", []string{"https://*.example.com"}),
+		testing.WithConfig("cors.allow-methods", []string{"GET", "POST"}),
+		testing.WithConfig("cors.allow-headers", []string{"Content-Type", "X-Request-ID"}),
+	)
+
+	w := s.Do(http.MethodOptions, "/v1/users", nil,
+		testing.WithHeader("Origin", "https://blog.example.com"),
+		testing.WithHeader("Access-Control-Request-Method", "POST"),
+		testing.WithHeader("Access-Control-Request-Headers", "content-type,x-request-id"),
+	)
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "https://blog.example.com" {
+		t.Fatalf("unexpected preflight response: %d %v", w.Code, w.Header())
+	}
+
+	w = s.Do(http.MethodOptions, "/v1/users", nil,
+		testing.WithHeader("Origin", "https://evil.com"),
+		testing.WithHeader("Access-Control-Request-Method", "POST"),
+	)
+	testing.AssertErrno(t, w, errno.ErrCorsRejected)
+}
+
+func TestCorsWildcardWithCredentials(t *stdtesting.T) {
+	cfg := viper.New()
+	cfg.Set("jwt-secret", "miniblog-test-secret")
+	cfg.Set("cors.allow-origins", []string{"*"})
+	cfg.Set("cors.allow-credentials", true)
+
+	app, err := miniblog.NewAppWithStore(cfg, store.NewStore(testing.NewDB(t), 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Close()
+
+	// 允许所有源携带凭证会把任意请求的 Origin 回显给浏览器，启动时直接拒绝
+	if _, err := app.Engine(); err == nil || !strings.Contains(err.Error(), "allow-credentials") {
+		t.Fatalf("wildcard origin with credentials should be rejected, got %v", err)
+	}
+}
+
+func TestServersAreIsolated(t *stdtesting.T) {
+	s1 := testing.NewServer(t)
+	s2 := testing.NewServer(t)
+
+	s1.CreateUser("alice")
+	// 两个 Server 使用各自的数据库，s2 中可以再次创建同名用户
+	s2.CreateUser("alice")
+}
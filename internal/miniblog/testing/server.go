@@ -0,0 +1,358 @@
+// Package testing 提供了基于 httptest 的 miniblog 集成测试工具。
+// NewServer 会使用 SQLite 内存数据库构建完整的 App，并通过 App.Engine 注册与线上一致的中间件和路由
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/pquerna/otp/totp"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"miniblog/internal/miniblog"
+	"miniblog/internal/miniblog/store"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/model"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	stdtesting "testing"
+	"time"
+)
+
+// DefaultPassword 是 CreateUser 创建的用户的默认密码
+const DefaultPassword = "miniblog1234"
+
+// Server 是一个用于集成测试的 miniblog 服务
+type Server struct {
+	t       stdtesting.TB
+	DB      *gorm.DB
+	Store   store.IStore
+	App     *miniblog.App
+	Engine  *gin.Engine
+	MailDir string // 发送的邮件保存在该目录下，见 LastMail
+}
+
+// Option 用来修改测试服务的配置
+type Option func(cfg *viper.Viper)
+
+// WithConfig 设置一个配置项，key 的格式与 miniblog.yaml 相同，例如 `ratelimit.enabled`
+func WithConfig(key string, value any) Option {
+	return func(cfg *viper.Viper) {
+		cfg.Set(key, value)
+	}
+}
+
+// NewServer 创建一个使用 SQLite 内存数据库的测试服务，测试结束时会自动释放数据库连接。
+// 每个 Server 使用独立的数据库，多个 Server 之间互不影响
+func NewServer(t stdtesting.TB, opts ...Option) *Server {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+
+	cfg := viper.New()
+	cfg.Set("jwt-secret", "miniblog-test-secret")
+	cfg.Set("request.max-body-size", "1mb")
+	cfg.Set("request.timeout", "10s")
+	cfg.Set("ratelimit.enabled", false)
+	mailDir := t.TempDir()
+	cfg.Set("mail.driver", "file")
+	cfg.Set("mail.dir", mailDir)
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	db := NewDB(t)
+	ds := store.NewStore(db, cfg.GetDuration("db.query-timeout"))
+
+	app, err := miniblog.NewAppWithStore(cfg, ds)
+	if err != nil {
+		t.Fatalf("failed to create app: %v", err)
+	}
+	t.Cleanup(func() { _ = app.Close() })
+	engine, err := app.Engine()
+	if err != nil {
+		t.Fatalf("failed to create gin engine: %v", err)
+	}
+
+	return &Server{t: t, DB: db, Store: ds, App: app, Engine: engine, MailDir: mailDir}
+}
+
+// NewDB 创建一个 SQLite 内存数据库，并使用 store.Migrate 创建数据表
+func NewDB(t stdtesting.TB) *gorm.DB {
+	t.Helper()
+
+	// 每个数据库使用唯一的名字，同一个数据库的多个连接共享数据
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", uuid.New().String())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	if err := store.Migrate(db); err != nil {
+		t.Fatalf("failed to migrate sqlite: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	return db
+}
+
+// RequestOption 用来修改测试请求
+type RequestOption func(req *http.Request)
+
+// WithToken 为请求添加 `Authorization: Bearer <token>` 请求头
+func WithToken(t string) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+t)
+	}
+}
+
+// WithHeader 为请求添加请求头
+func WithHeader(key, value string) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set(key, value)
+	}
+}
+
+// Do 发起一个 HTTP 请求。body 为 nil 时不发送请求体，为 string 或 []byte 时原样发送，其它类型会被序列化为 JSON
+func (s *Server) Do(method, path string, body any, opts ...RequestOption) *httptest.ResponseRecorder {
+	s.t.Helper()
+
+	var reader io.Reader
+	switch typed := body.(type) {
+	case nil:
+	case string:
+		reader = bytes.NewBufferString(typed)
+	case []byte:
+		reader = bytes.NewBuffer(typed)
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			s.t.Fatalf("failed to marshal request body: %v", err)
+		}
+		reader = bytes.NewBuffer(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	w := httptest.NewRecorder()
+	s.Engine.ServeHTTP(w, req)
+	return w
+}
+
+// NewCreateUserRequest 返回一个可以通过参数校验的创建用户请求
+func NewCreateUserRequest(username string) *v1.CreateUserRequest {
+	return &v1.CreateUserRequest{
+		Username: username,
+		Password: DefaultPassword,
+		Nickname: username,
+		Email:    username + "@example.com",
+		Phone:    "13800000000",
+	}
+}
+
+// CreateUser 通过 `POST /v1/users` 创建一个使用默认密码的用户，创建失败时测试失败
+func (s *Server) CreateUser(username string) {
+	s.t.Helper()
+
+	if w := s.Do(http.MethodPost, "/v1/users", NewCreateUserRequest(username)); w.Code != http.StatusOK {
+		s.t.Fatalf("failed to create user %q: %d %s", username, w.Code, w.Body.String())
+	}
+}
+
+// Login 使用默认密码调用 `POST /login` 登录，返回 username 对应的 JWT Token，登录失败时测试失败
+func (s *Server) Login(username string) string {
+	s.t.Helper()
+
+	w := s.Do(http.MethodPost, "/login", v1.LoginRequest{Username: username, Password: DefaultPassword})
+	if w.Code != http.StatusOK {
+		s.t.Fatalf("failed to login as %q: %d %s", username, w.Code, w.Body.String())
+	}
+
+	var resp v1.LoginResponse
+	DecodeJSON(s.t, w, &resp)
+	return resp.Token
+}
+
+// SetRole 直接在数据库中设置 username 的角色，例如将用户设置为管理员
+func (s *Server) SetRole(username, role string) {
+	s.t.Helper()
+
+	if err := s.DB.Model(&model.UserM{}).Where("username = ?", username).Update("role", role).Error; err != nil {
+		s.t.Fatalf("failed to set role of %q: %v", username, err)
+	}
+}
+
+// EnableTwoFactor 使用 username 的 token 调用 `POST /v1/users/:name/2fa` 和 `POST /v1/users/:name/2fa/confirm` 开启两步验证，
+// 返回 TOTP 密钥和恢复码，失败时测试失败。确认时使用了当前周期的验证码，之后登录需要使用下一个周期的验证码
+func (s *Server) EnableTwoFactor(token, username string) (secret string, recoveryCodes []string) {
+	s.t.Helper()
+
+	w := s.Do(http.MethodPost, "/v1/users/"+username+"/2fa", nil, WithToken(token))
+	AssertOK(s.t, w)
+	var enroll v1.EnrollTwoFactorResponse
+	DecodeJSON(s.t, w, &enroll)
+
+	w = s.Do(http.MethodPost, "/v1/users/"+username+"/2fa/confirm", v1.TwoFactorCodeRequest{Code: TOTPCode(s.t, enroll.Secret, time.Now())}, WithToken(token))
+	AssertOK(s.t, w)
+	var confirm v1.ConfirmTwoFactorResponse
+	DecodeJSON(s.t, w, &confirm)
+
+	return enroll.Secret, confirm.RecoveryCodes
+}
+
+// CreatePost 使用 token 调用 `POST /v1/posts` 发布一篇博客，返回博客 ID，发布失败时测试失败
+func (s *Server) CreatePost(token, title string) string {
+	s.t.Helper()
+
+	w := s.Do(http.MethodPost, "/v1/posts", v1.CreatePostRequest{Title: title, Content: title + " content"}, WithToken(token))
+	AssertOK(s.t, w)
+	var resp v1.CreatePostResponse
+	DecodeJSON(s.t, w, &resp)
+	return resp.PostID
+}
+
+// TOTPCode 返回 secret 在 at 时刻的 TOTP 验证码
+func TOTPCode(t stdtesting.TB, secret string, at time.Time) string {
+	t.Helper()
+
+	code, err := totp.GenerateCode(secret, at)
+	if err != nil {
+		t.Fatalf("failed to generate totp code: %v", err)
+	}
+	return code
+}
+
+// Mail 是一封已发送的邮件
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Token 返回邮件正文中链接的 token 参数，不存在时测试失败
+func (m *Mail) Token(t stdtesting.TB) string {
+	t.Helper()
+
+	match := mailTokenPattern.FindStringSubmatch(m.Body)
+	if match == nil {
+		t.Fatalf("no token found in mail body: %s", m.Body)
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatalf("failed to unescape token: %v", err)
+	}
+	return token
+}
+
+// mailTokenPattern 匹配邮件链接中的 token 参数
+var mailTokenPattern = regexp.MustCompile(`[?&]token=([^&\s]+)`)
+
+// Mails 返回发送给 to 的所有邮件，按发送顺序排列
+func (s *Server) Mails(to string) []*Mail {
+	s.t.Helper()
+
+	// 重置密码等邮件是异步发送的
+	s.App.FlushMail()
+
+	files, err := filepath.Glob(filepath.Join(s.MailDir, "*.eml"))
+	if err != nil {
+		s.t.Fatalf("failed to list mails: %v", err)
+	}
+	sort.Strings(files)
+
+	var mails []*Mail
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			s.t.Fatalf("failed to read mail: %v", err)
+		}
+		msg, err := mail.ReadMessage(bytes.NewReader(data))
+		if err != nil {
+			s.t.Fatalf("failed to parse mail %s: %v", file, err)
+		}
+		if msg.Header.Get("To") != to {
+			continue
+		}
+
+		body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+		if err != nil {
+			s.t.Fatalf("failed to decode mail body: %v", err)
+		}
+		subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+		if err != nil {
+			s.t.Fatalf("failed to decode mail subject: %v", err)
+		}
+		mails = append(mails, &Mail{To: to, Subject: subject, Body: string(body)})
+	}
+
+	return mails
+}
+
+// LastMail 返回最后一封发送给 to 的邮件，没有邮件时测试失败
+func (s *Server) LastMail(to string) *Mail {
+	s.t.Helper()
+
+	mails := s.Mails(to)
+	if len(mails) == 0 {
+		s.t.Fatalf("no mail was sent to %s", to)
+	}
+	return mails[len(mails)-1]
+}
+
+// DecodeJSON 将返回体解析到 v 中
+func DecodeJSON(t stdtesting.TB, w *httptest.ResponseRecorder, v any) {
+	t.Helper()
+
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("failed to decode response body %q: %v", w.Body.String(), err)
+	}
+}
+
+// AssertErrno 断言返回结果是 want 对应的错误：HTTP 状态码相同，且 core.ErrResponse 中的业务错误码相同
+func AssertErrno(t stdtesting.TB, w *httptest.ResponseRecorder, want *errno.Errno) {
+	t.Helper()
+
+	if w.Code != want.HTTP {
+		t.Fatalf("unexpected http status: want %d, got %d, body: %s", want.HTTP, w.Code, w.Body.String())
+	}
+
+	var resp core.ErrResponse
+	DecodeJSON(t, w, &resp)
+	if resp.Code != want.Code {
+		t.Fatalf("unexpected error code: want %q, got %q, message: %q", want.Code, resp.Code, resp.Message)
+	}
+}
+
+// AssertOK 断言请求成功
+func AssertOK(t stdtesting.TB, w *httptest.ResponseRecorder) {
+	t.Helper()
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected http status: want 200, got %d, body: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,163 @@
+package miniblog
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"miniblog/internal/miniblog/biz"
+	"miniblog/internal/pkg/log"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	"time"
+)
+
+// newTrashCommand 创建 `miniblog trash` 子命令，用于在服务器上直接查看、恢复和永久删除已删除的用户和博客
+func newTrashCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "Manage deleted users and posts",
+	}
+
+	var offset, limit int
+	listCmd := &cobra.Command{
+		Use:          "list <users|posts>",
+		Short:        "List deleted users or posts that have not been purged yet",
+		SilenceUsage: true,
+		Args:         cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs:    []string{"users", "posts"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWithBiz(func(ctx context.Context, b biz.IBiz) error {
+				out := cmd.OutOrStdout()
+				if args[0] == "users" {
+					resp, err := b.Users().ListDeleted(ctx, &v1.ListUsersRequest{Offset: offset, Limit: limit})
+					if err != nil {
+						return err
+					}
+					for _, user := range resp.Users {
+						fmt.Fprintf(out, "%s\t%s\t%s\n", user.Username, user.Email, user.DeletedAt.Format(time.RFC3339))
+					}
+					fmt.Fprintf(out, "Total: %d\n", resp.TotalCount)
+					return nil
+				}
+
+				resp, err := b.Posts().ListDeleted(ctx, &v1.ListPostsRequest{Offset: offset, Limit: limit})
+				if err != nil {
+					return err
+				}
+				for _, post := range resp.Posts {
+					fmt.Fprintf(out, "%s\t%s\t%s\t%s\n", post.PostID, post.Username, post.DeletedAt.Format(time.RFC3339), post.Title)
+				}
+				fmt.Fprintf(out, "Total: %d\n", resp.TotalCount)
+				return nil
+			})
+		},
+	}
+	listCmd.Flags().IntVar(&offset, "offset", 0, "Number of items to skip")
+	listCmd.Flags().IntVar(&limit, "limit", 20, "Maximum number of items to list")
+	cmd.AddCommand(listCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:          "restore <user|post> <username|postID>",
+		Short:        "Restore a deleted user together with the posts deleted with it, or a deleted post",
+		SilenceUsage: true,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+				return err
+			}
+			if args[0] != "user" && args[0] != "post" {
+				return fmt.Errorf("invalid argument %q, must be user or post", args[0])
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWithBiz(func(ctx context.Context, b biz.IBiz) error {
+				restore := b.Users().Restore
+				if args[0] == "post" {
+					restore = b.Posts().Restore
+				}
+				if err := restore(ctx, args[1]); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s %s restored\n", args[0], args[1])
+				return nil
+			})
+		},
+	})
+
+	var retention time.Duration
+	purgeCmd := &cobra.Command{
+		Use:          "purge",
+		Short:        "Permanently delete users and posts deleted longer than the retention period",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// 未配置保留期时需要显式指定 --retention，避免误删回收站中的所有数据
+			if !cmd.Flags().Changed("retention") {
+				if retention = viper.GetDuration("trash.retention"); retention <= 0 {
+					return fmt.Errorf("trash.retention is not set, please specify --retention")
+				}
+			}
+			return runWithBiz(func(ctx context.Context, b biz.IBiz) error {
+				users, posts, err := purge(ctx, b, time.Now().Add(-retention))
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Purged %d users and %d posts\n", users, posts)
+				return nil
+			})
+		},
+	}
+	purgeCmd.Flags().DurationVar(&retention, "retention", 0, "Purge items deleted longer than this ago, defaults to trash.retention. 0 purges everything in the trash")
+	cmd.AddCommand(purgeCmd)
+
+	return cmd
+}
+
+// startPurgeJob 在后台每隔 `trash.purge-interval` 永久删除超过 `trash.retention` 的用户和博客，ctx 被取消时停止。
+// 永久删除的条件只与删除时间有关，多个实例同时执行时结果相同，因此不需要选主或加锁
+func (a *App) startPurgeJob(ctx context.Context) {
+	retention := a.cfg.GetDuration("trash.retention")
+	if retention <= 0 {
+		log.Infow("Trash purge job disabled")
+		return
+	}
+	interval := a.cfg.GetDuration("trash.purge-interval")
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			users, posts, err := purge(ctx, a.biz, time.Now().Add(-retention))
+			if err != nil {
+				log.Errorw("Failed to purge trash", "err", err)
+			} else if users > 0 || posts > 0 {
+				log.Infow("Trash purged", "users", users, "posts", posts)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// purge 永久删除在 before 之前删除的用户和博客，返回永久删除的用户和博客数量（不包括随用户一起删除的博客）
+func purge(ctx context.Context, b biz.IBiz, before time.Time) (int64, int64, error) {
+	users, err := b.Users().Purge(ctx, before)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	posts, err := b.Posts().Purge(ctx, before)
+	if err != nil {
+		return users, 0, err
+	}
+
+	return users, posts, nil
+}
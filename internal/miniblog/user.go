@@ -0,0 +1,115 @@
+package miniblog
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"miniblog/internal/miniblog/biz"
+	"miniblog/internal/miniblog/store"
+	"miniblog/internal/pkg/log"
+	v1 "miniblog/pkg/api/miniblog/v1"
+	"miniblog/pkg/db"
+	"time"
+)
+
+// newUserCommand 创建 `miniblog user` 子命令，用于在服务器上直接管理用户，例如解锁账户、设置管理员、重置两步验证、生成邀请码
+func newUserCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage miniblog users",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:          "unlock <username>",
+		Short:        "Unlock an account locked by too many failed login attempts",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWithBiz(func(ctx context.Context, b biz.IBiz) error {
+				if err := b.Users().Unlock(ctx, args[0]); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "User %s unlocked\n", args[0])
+				return nil
+			})
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:          "set-role <username> <user|admin>",
+		Short:        "Set the role of a user",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWithBiz(func(ctx context.Context, b biz.IBiz) error {
+				if err := b.Users().SetRole(ctx, args[0], args[1]); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Role of user %s set to %s\n", args[0], args[1])
+				return nil
+			})
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:          "reset-2fa <username>",
+		Short:        "Disable two-factor authentication for a user who lost their authenticator and recovery codes",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWithBiz(func(ctx context.Context, b biz.IBiz) error {
+				if err := b.Users().ResetTwoFactor(ctx, args[0]); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Two-factor authentication of user %s reset\n", args[0])
+				return nil
+			})
+		},
+	})
+
+	var maxUses int
+	var expire time.Duration
+	inviteCmd := &cobra.Command{
+		Use:          "invite",
+		Short:        "Create an invitation code for registration in invite-only mode",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWithBiz(func(ctx context.Context, b biz.IBiz) error {
+				req := &v1.CreateInvitationRequest{MaxUses: maxUses}
+				if expire > 0 {
+					expiresAt := time.Now().Add(expire)
+					req.ExpiresAt = &expiresAt
+				}
+
+				invitation, err := b.Users().CreateInvitation(ctx, "cli", req)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), invitation.Code)
+				return nil
+			})
+		},
+	}
+	inviteCmd.Flags().IntVar(&maxUses, "max-uses", 1, "Maximum number of users that can register with the code")
+	inviteCmd.Flags().DurationVar(&expire, "expire", 7*24*time.Hour, "Validity period of the code, 0 means never expires")
+	cmd.AddCommand(inviteCmd)
+
+	return cmd
+}
+
+// runWithBiz 根据配置连接数据库并构建 biz 层，然后执行 fn，用于需要访问数据库的子命令
+func runWithBiz(fn func(ctx context.Context, b biz.IBiz) error) error {
+	log.Init(logOptions())
+	defer log.Sync()
+
+	instance, err := newDB(viper.GetViper())
+	if err != nil {
+		return err
+	}
+	defer db.Close(instance)
+
+	ds := store.NewStore(instance, viper.GetDuration("db.query-timeout"))
+	return fn(context.Background(), biz.NewBiz(ds, nil))
+}
@@ -35,4 +35,67 @@ var (
 		Code:    "InvalidParameter",
 		Message: "Parameter verification failed.",
 	}
+
+	// ErrRequestEntityTooLarge 表示请求体超过了允许的最大字节数
+	ErrRequestEntityTooLarge = &Errno{
+		HTTP:    413,
+		Code:    "InvalidParameter.RequestEntityTooLarge",
+		Message: "Request body is too large.",
+	}
+
+	// ErrRequestTimeout 表示请求处理超时
+	ErrRequestTimeout = &Errno{
+		HTTP:    504,
+		Code:    "InternalError.Timeout",
+		Message: "Request processing timed out.",
+	}
+
+	// ErrRequestCanceled 表示客户端取消了请求（例如断开连接），数据库查询随之被取消
+	ErrRequestCanceled = &Errno{
+		HTTP:    499,
+		Code:    "FailedOperation.RequestCanceled",
+		Message: "Request was canceled by the client.",
+	}
+
+	// ErrQueryTimeout 表示数据库查询超时
+	ErrQueryTimeout = &Errno{
+		HTTP:    504,
+		Code:    "InternalError.QueryTimeout",
+		Message: "Database query timed out.",
+	}
+
+	// ErrTokenInvalid 表示 JWT Token 格式错误或已失效
+	ErrTokenInvalid = &Errno{
+		HTTP:    401,
+		Code:    "AuthFailure.TokenInvalid",
+		Message: "Token was invalid.",
+	}
+
+	// ErrSignToken 表示签发 JWT Token 时出错
+	ErrSignToken = &Errno{
+		HTTP:    401,
+		Code:    "AuthFailure.SignTokenError",
+		Message: "Error occurred while signing the JSON web token.",
+	}
+
+	// ErrPermissionDenied 表示当前用户没有权限执行该操作
+	ErrPermissionDenied = &Errno{
+		HTTP:    403,
+		Code:    "AuthFailure.PermissionDenied",
+		Message: "Permission denied.",
+	}
+
+	// ErrTooManyRequests 表示请求过于频繁，触发了限流
+	ErrTooManyRequests = &Errno{
+		HTTP:    429,
+		Code:    "LimitExceeded.TooManyRequests",
+		Message: "Too many requests, please try again later.",
+	}
+
+	// ErrCorsRejected 表示跨域请求的源、方法或请求头不被允许
+	ErrCorsRejected = &Errno{
+		HTTP:    403,
+		Code:    "FailedOperation.CorsRejected",
+		Message: "Cross-origin request is not allowed.",
+	}
 )
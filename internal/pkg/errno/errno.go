@@ -20,6 +20,18 @@ func (e *Errno) SetMessage(format string, args ...any) *Errno {
 	return e
 }
 
+// WithMessage 返回一个使用新 message 的 Errno 副本，不会修改 e 本身。预定义的 Errno 是全局共享的，
+// message 中包含请求相关的内容时需要使用 WithMessage，避免并发请求之间相互覆盖
+func (e *Errno) WithMessage(format string, args ...any) *Errno {
+	return &Errno{HTTP: e.HTTP, Code: e.Code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Is 让 errors.Is 按照业务错误码比较 Errno，这样 WithMessage 返回的副本仍然可以和预定义的 Errno 匹配
+func (e *Errno) Is(target error) bool {
+	t, ok := target.(*Errno)
+	return ok && e.Code == t.Code
+}
+
 // Decode 尝试从 err 中解析中 HTTP 状态码、业务错误码和错误信息
 func Decode(err error) (int, string, string) {
 	if err == nil {
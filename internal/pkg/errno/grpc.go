@@ -0,0 +1,63 @@
+package errno
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+// domain 是 gRPC 错误详情 ErrorInfo 中的错误域
+const domain = "miniblog"
+
+// httpToGRPC 定义了 HTTP 状态码与 gRPC 状态码之间的映射关系
+var httpToGRPC = map[int]codes.Code{
+	http.StatusOK:                    codes.OK,
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusConflict:              codes.AlreadyExists,
+	http.StatusRequestEntityTooLarge: codes.InvalidArgument,
+	http.StatusLocked:                codes.FailedPrecondition,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+	499:                              codes.Canceled, // Client Closed Request
+	http.StatusInternalServerError:   codes.Internal,
+	http.StatusNotImplemented:        codes.Unimplemented,
+	http.StatusServiceUnavailable:    codes.Unavailable,
+	http.StatusGatewayTimeout:        codes.DeadlineExceeded,
+}
+
+// GRPCStatus 将 Errno 转换为 gRPC 状态，业务错误码放在 ErrorInfo 的 Reason 中。
+// 实现了该方法后，gRPC 框架（status.FromError）可以直接识别 Errno
+func (e *Errno) GRPCStatus() *status.Status {
+	code, ok := httpToGRPC[e.HTTP]
+	if !ok {
+		code = codes.Unknown
+	}
+
+	s := status.New(code, e.Message)
+	if e.Code == "" {
+		return s
+	}
+	if detailed, err := s.WithDetails(&errdetails.ErrorInfo{Reason: e.Code, Domain: domain}); err == nil {
+		return detailed
+	}
+	return s
+}
+
+// ToGRPCError 将任意错误转换为 gRPC 错误。非 Errno 类型的错误统一视为 InternalServerError，避免内部错误信息泄露给调用方
+func ToGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if typed, ok := err.(*Errno); ok {
+		return typed.GRPCStatus().Err()
+	}
+	// 已经是 gRPC 状态错误的直接返回
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return InternalServerError.GRPCStatus().Err()
+}
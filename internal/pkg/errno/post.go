@@ -0,0 +1,31 @@
+package errno
+
+var (
+	// ErrPostNotFound 表示未找到博客
+	ErrPostNotFound = &Errno{
+		HTTP:    404,
+		Code:    "ResourceNotFound.PostNotFound",
+		Message: "Post was not found.",
+	}
+
+	// ErrPostAuthorDeleted 表示博客的作者已被删除，需要先恢复作者才能恢复博客
+	ErrPostAuthorDeleted = &Errno{
+		HTTP:    400,
+		Code:    "FailedOperation.PostAuthorDeleted",
+		Message: "The author of the post was deleted, restore the user first.",
+	}
+
+	// ErrPostRevisionNotFound 表示未找到博客的指定版本
+	ErrPostRevisionNotFound = &Errno{
+		HTTP:    404,
+		Code:    "ResourceNotFound.PostRevisionNotFound",
+		Message: "Post revision was not found.",
+	}
+
+	// ErrPostConflict 表示博客在读取之后被其他请求修改了，需要重试
+	ErrPostConflict = &Errno{
+		HTTP:    409,
+		Code:    "FailedOperation.PostConflict",
+		Message: "The post was modified by another request, please try again.",
+	}
+)
@@ -7,4 +7,215 @@ var (
 		Code:    "FailedOperation.UserAlreadyExist",
 		Message: "User already exist.",
 	}
+
+	// ErrUserNotFound 表示未找到用户
+	ErrUserNotFound = &Errno{
+		HTTP:    404,
+		Code:    "ResourceNotFound.UserNotFound",
+		Message: "User was not found.",
+	}
+
+	// ErrPasswordIncorrect 表示密码不正确
+	ErrPasswordIncorrect = &Errno{
+		HTTP:    401,
+		Code:    "InvalidParameter.PasswordIncorrect",
+		Message: "Password was incorrect.",
+	}
+
+	// ErrPasswordTooShort 表示密码短于密码策略的最小长度
+	ErrPasswordTooShort = &Errno{
+		HTTP:    400,
+		Code:    "InvalidParameter.PasswordTooShort",
+		Message: "Password is too short.",
+	}
+
+	// ErrPasswordTooLong 表示密码超过密码策略的最大长度
+	ErrPasswordTooLong = &Errno{
+		HTTP:    400,
+		Code:    "InvalidParameter.PasswordTooLong",
+		Message: "Password is too long.",
+	}
+
+	// ErrPasswordTooWeak 表示密码缺少密码策略要求的字符类型（大写字母、小写字母、数字、特殊字符）
+	ErrPasswordTooWeak = &Errno{
+		HTTP:    400,
+		Code:    "InvalidParameter.PasswordTooWeak",
+		Message: "Password does not contain the required character classes.",
+	}
+
+	// ErrPasswordContainsUserInfo 表示密码中包含用户名或邮箱
+	ErrPasswordContainsUserInfo = &Errno{
+		HTTP:    400,
+		Code:    "InvalidParameter.PasswordContainsUserInfo",
+		Message: "Password must not contain the username or email.",
+	}
+
+	// ErrPasswordBreached 表示密码出现在常见泄露密码列表中
+	ErrPasswordBreached = &Errno{
+		HTTP:    400,
+		Code:    "InvalidParameter.PasswordBreached",
+		Message: "Password is too common.",
+	}
+
+	// ErrInvalidCredentials 表示登录时用户名或密码错误。用户不存在和密码错误返回相同的错误，避免枚举用户名
+	ErrInvalidCredentials = &Errno{
+		HTTP:    401,
+		Code:    "AuthFailure.InvalidCredentials",
+		Message: "Username or password is incorrect.",
+	}
+
+	// ErrAccountLocked 表示连续登录失败的次数过多，账户被临时锁定。只在两步登录时返回，
+	// 密码登录时返回 ErrInvalidCredentials，避免枚举用户名
+	ErrAccountLocked = &Errno{
+		HTTP:    423,
+		Code:    "FailedOperation.AccountLocked",
+		Message: "Account is temporarily locked due to too many failed login attempts.",
+	}
+
+	// ErrLoginThrottled 表示登录失败后需要等待一段时间才能再次尝试。和 ErrAccountLocked 一样只在两步登录时返回
+	ErrLoginThrottled = &Errno{
+		HTTP:    429,
+		Code:    "LimitExceeded.LoginThrottled",
+		Message: "Too many failed login attempts, please try again later.",
+	}
+
+	// ErrTwoFactorRequired 表示当前账户必须开启两步验证后才能执行该操作，例如管理员账户
+	ErrTwoFactorRequired = &Errno{
+		HTTP:    403,
+		Code:    "AuthFailure.TwoFactorRequired",
+		Message: "Two-factor authentication must be enabled for this account.",
+	}
+
+	// ErrTwoFactorAlreadyEnabled 表示用户已经开启了两步验证
+	ErrTwoFactorAlreadyEnabled = &Errno{
+		HTTP:    400,
+		Code:    "FailedOperation.TwoFactorAlreadyEnabled",
+		Message: "Two-factor authentication is already enabled.",
+	}
+
+	// ErrTwoFactorNotEnrolled 表示用户还没有绑定或开启两步验证
+	ErrTwoFactorNotEnrolled = &Errno{
+		HTTP:    400,
+		Code:    "FailedOperation.TwoFactorNotEnrolled",
+		Message: "Two-factor authentication is not enrolled.",
+	}
+
+	// ErrTwoFactorCodeInvalid 表示两步验证的验证码或恢复码错误，或者已经被使用过
+	ErrTwoFactorCodeInvalid = &Errno{
+		HTTP:    401,
+		Code:    "AuthFailure.TwoFactorCodeInvalid",
+		Message: "Two-factor code is invalid or has already been used.",
+	}
+
+	// ErrChallengeInvalid 表示两步登录的 challenge 无效或已过期，需要重新使用密码登录
+	ErrChallengeInvalid = &Errno{
+		HTTP:    401,
+		Code:    "AuthFailure.ChallengeInvalid",
+		Message: "Login challenge is invalid or has expired.",
+	}
+
+	// ErrEmailAlreadyVerified 表示用户的邮箱已经验证过
+	ErrEmailAlreadyVerified = &Errno{
+		HTTP:    400,
+		Code:    "FailedOperation.EmailAlreadyVerified",
+		Message: "Email has already been verified.",
+	}
+
+	// ErrOneTimeTokenInvalid 表示邮件中的验证或重置密码 token 无效、已过期或者已经被使用过
+	ErrOneTimeTokenInvalid = &Errno{
+		HTTP:    400,
+		Code:    "InvalidParameter.OneTimeTokenInvalid",
+		Message: "Token is invalid, expired or has already been used.",
+	}
+
+	// ErrSendMail 表示发送邮件失败
+	ErrSendMail = &Errno{
+		HTTP:    500,
+		Code:    "InternalError.SendMail",
+		Message: "Failed to send the email, please try again later.",
+	}
+
+	// ErrAPIKeyInvalid 表示 API Key 格式错误、不存在或已过期
+	ErrAPIKeyInvalid = &Errno{
+		HTTP:    401,
+		Code:    "AuthFailure.APIKeyInvalid",
+		Message: "API key is invalid or has expired.",
+	}
+
+	// ErrInsufficientScope 表示 API Key 的授权范围不允许调用该接口
+	ErrInsufficientScope = &Errno{
+		HTTP:    403,
+		Code:    "AuthFailure.InsufficientScope",
+		Message: "API key does not have the required scope.",
+	}
+
+	// ErrAPIKeyNotAllowed 表示该接口只接受登录签发的 JWT Token，不能使用 API Key 调用
+	ErrAPIKeyNotAllowed = &Errno{
+		HTTP:    403,
+		Code:    "AuthFailure.APIKeyNotAllowed",
+		Message: "API keys are not allowed for this operation, please log in.",
+	}
+
+	// ErrAPIKeyAlreadyExist 表示用户已经有同名的 API Key
+	ErrAPIKeyAlreadyExist = &Errno{
+		HTTP:    400,
+		Code:    "FailedOperation.APIKeyAlreadyExist",
+		Message: "API key with the same name already exists.",
+	}
+
+	// ErrAPIKeyNotFound 表示未找到 API Key
+	ErrAPIKeyNotFound = &Errno{
+		HTTP:    404,
+		Code:    "ResourceNotFound.APIKeyNotFound",
+		Message: "API key was not found.",
+	}
+
+	// ErrOIDCDisabled 表示没有配置外部身份提供方（OIDC IdP）
+	ErrOIDCDisabled = &Errno{
+		HTTP:    404,
+		Code:    "ResourceNotFound.OIDCDisabled",
+		Message: "OIDC login is not enabled.",
+	}
+
+	// ErrOIDCProvider 表示无法访问外部身份提供方
+	ErrOIDCProvider = &Errno{
+		HTTP:    502,
+		Code:    "InternalError.OIDCProvider",
+		Message: "Failed to communicate with the identity provider.",
+	}
+
+	// ErrOIDCLoginFailed 表示 OIDC 登录的回调参数无效，例如 state 不匹配、授权码无效或 ID Token 校验失败
+	ErrOIDCLoginFailed = &Errno{
+		HTTP:    401,
+		Code:    "AuthFailure.OIDCLoginFailed",
+		Message: "OIDC login failed, please start over.",
+	}
+
+	// ErrOIDCAccountNotLinked 表示外部身份没有绑定 miniblog 用户，且无法自动绑定或创建用户
+	ErrOIDCAccountNotLinked = &Errno{
+		HTTP:    403,
+		Code:    "AuthFailure.OIDCAccountNotLinked",
+		Message: "No miniblog account is linked to this identity.",
+	}
+
+	// ErrRegistrationClosed 表示当前禁止注册
+	ErrRegistrationClosed = &Errno{
+		HTTP:    403,
+		Code:    "FailedOperation.RegistrationClosed",
+		Message: "Registration is closed.",
+	}
+
+	// ErrInvitationInvalid 表示邀请码不存在、已过期或已用完
+	ErrInvitationInvalid = &Errno{
+		HTTP:    400,
+		Code:    "InvalidParameter.InvitationInvalid",
+		Message: "Invitation code is invalid, expired or used up.",
+	}
+
+	// ErrInvitationNotFound 表示未找到邀请码
+	ErrInvitationNotFound = &Errno{
+		HTTP:    404,
+		Code:    "ResourceNotFound.InvitationNotFound",
+		Message: "Invitation code was not found.",
+	}
 )
@@ -0,0 +1,64 @@
+package interceptor
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/known"
+	"miniblog/pkg/token"
+	"strings"
+)
+
+// apiKeyScheme 是使用 API Key 认证时 authorization 的前缀
+const apiKeyScheme = "ApiKey "
+
+// APIKeyAuthenticator 校验 API Key 是否有效且被授予了 scope，成功时返回 API Key 所属的用户名
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key, scope string) (string, error)
+}
+
+// Authn 是一个 gRPC 认证拦截器，从 metadata 的 `authorization` 中解析 JWT Token（使用 tokens）或 API Key，并将用户名注入 context。
+// publicMethods 中的方法（gRPC FullMethod）无需认证即可调用；
+// methodScopes 定义了可以使用 API Key 调用的方法及其要求的 scope，不在其中的方法只接受 JWT Token
+func Authn(tokens *token.Config, keys APIKeyAuthenticator, methodScopes map[string]string, publicMethods ...string) grpc.UnaryServerInterceptor {
+	public := make(map[string]struct{}, len(publicMethods))
+	for _, method := range publicMethods {
+		public[method] = struct{}{}
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := public[info.FullMethod]; ok {
+			return handler(ctx, req)
+		}
+
+		var header string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("authorization"); len(values) > 0 {
+				header = values[0]
+			}
+		}
+
+		var username string
+		if key, ok := strings.CutPrefix(header, apiKeyScheme); ok {
+			scope := methodScopes[info.FullMethod]
+			if scope == "" {
+				return nil, errno.ErrAPIKeyNotAllowed
+			}
+
+			var err error
+			if username, err = keys.AuthenticateAPIKey(ctx, strings.TrimSpace(key), scope); err != nil {
+				return nil, err
+			}
+		} else {
+			var err error
+			if username, err = tokens.ParseHeader(header); err != nil {
+				return nil, errno.ErrTokenInvalid
+			}
+		}
+
+		ctx = context.WithValue(ctx, known.XUsernameKey, username)
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,22 @@
+package interceptor
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/log"
+)
+
+// Errno 是一个 gRPC 拦截器，将 handler 返回的错误统一转换为带有业务错误码的 gRPC 状态
+func Errno() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			if _, ok := err.(*errno.Errno); !ok {
+				log.C(ctx).Errorw("gRPC handler returned an unknown error", "method", info.FullMethod, "err", err)
+			}
+			return resp, errno.ToGRPCError(err)
+		}
+		return resp, nil
+	}
+}
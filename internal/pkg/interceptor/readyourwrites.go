@@ -0,0 +1,14 @@
+package interceptor
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"miniblog/pkg/db"
+)
+
+// ReadYourWrites 是一个 gRPC 拦截器，为每个请求开启 read-your-writes：请求中执行过写操作后，后续的读操作都会访问主库
+func ReadYourWrites() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(db.WithReadYourWrites(ctx), req)
+	}
+}
@@ -0,0 +1,34 @@
+package interceptor
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"miniblog/internal/pkg/known"
+	"strings"
+)
+
+// RequestID 是一个 gRPC 拦截器，用来在每一个 gRPC 请求的 context、返回的 header 中注入 `X-Request-ID` 键值对
+func RequestID() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		// 检查请求的 metadata 中是否有 `X-Request-ID`，有则复用，没有则新建
+		var requestID string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(strings.ToLower(known.XRequestIdKey)); len(values) > 0 {
+				requestID = values[0]
+			}
+		}
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+
+		// 将 RequestID 保存在 context 中，log.C(ctx) 会从中取出并打印
+		ctx = context.WithValue(ctx, known.XRequestIdKey, requestID)
+
+		// 将 RequestID 保存在返回的 header 中
+		_ = grpc.SetHeader(ctx, metadata.Pairs(known.XRequestIdKey, requestID))
+
+		return handler(ctx, req)
+	}
+}
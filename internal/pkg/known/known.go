@@ -3,4 +3,71 @@ package known
 const (
 	// XRequestIdKey 用来定义 Gin 上下文中的键，代表请求的 uuid
 	XRequestIdKey = "X-Request-ID"
+
+	// XUsernameKey 用来定义上下文中的键，代表请求的所有者（通过认证的用户名）
+	XUsernameKey = "X-Username"
+)
+
+// 用户角色
+const (
+	// RoleUser 普通用户
+	RoleUser = "user"
+
+	// RoleAdmin 管理员，可以调用管理接口，例如解锁被锁定的账户
+	RoleAdmin = "admin"
+)
+
+// 注册模式，通过 `registration.mode` 配置
+const (
+	// RegistrationOpen 任何人都可以注册
+	RegistrationOpen = "open"
+
+	// RegistrationInviteOnly 需要使用管理员生成的邀请码注册
+	RegistrationInviteOnly = "invite-only"
+
+	// RegistrationClosed 禁止注册，开启了 auth.oidc.auto-provision 时仍然可以通过 OIDC 登录自动创建用户
+	RegistrationClosed = "closed"
+)
+
+// API Key 的授权范围（scope）。使用 JWT Token 访问时拥有全部权限，使用 API Key 访问时只能调用其 scope 允许的接口，
+// 修改密码、管理两步验证和 API Key 等账户安全相关的接口不允许使用 API Key 调用
+const (
+	// ScopePostsRead 读取博客
+	ScopePostsRead = "posts:read"
+
+	// ScopePostsWrite 创建、修改和删除博客，同时包含 posts:read
+	ScopePostsWrite = "posts:write"
+
+	// ScopeUsersAdmin 调用管理接口，只有管理员可以创建包含该 scope 的 API Key
+	ScopeUsersAdmin = "users:admin"
+)
+
+// Scopes 列出了所有可以授予 API Key 的 scope
+var Scopes = []string{ScopePostsRead, ScopePostsWrite, ScopeUsersAdmin}
+
+// 博客状态
+const (
+	// PostStatusDraft 草稿，只有作者可以看到
+	PostStatusDraft = "draft"
+
+	// PostStatusScheduled 定时发布，到达发布时间后由后台任务发布，发布前只有作者可以看到
+	PostStatusScheduled = "scheduled"
+
+	// PostStatusPublished 已发布，所有用户都可以看到
+	PostStatusPublished = "published"
+
+	// PostStatusArchived 已归档，只有作者可以看到
+	PostStatusArchived = "archived"
+)
+
+// 博客内容的格式
+const (
+	// PostFormatMarkdown CommonMark 格式的 Markdown，支持 GFM 表格
+	PostFormatMarkdown = "markdown"
+
+	// PostFormatHTML HTML，脚本和危险的属性会被过滤
+	PostFormatHTML = "html"
+
+	// PostFormatPlain 纯文本，空行分隔段落
+	PostFormatPlain = "plain"
 )
@@ -70,7 +70,10 @@ func NewLogger(opts *Options) *ZapLogger {
 	}
 
 	// TODO 2023/7/24 20:01 sun: 使用 cfg 创建 *zap.Logger 对象。参数含义含义❓因为是自定义封装的 zap 包，所以在调用栈中跳过的调用深度要加 1
-	z, err := cfg.Build(zap.AddStacktrace(zapcore.PanicLevel), zap.AddCallerSkip(1))
+	// 使用 redactCore 包装底层 Core，确保密码、密钥等敏感信息不会出现在任何一行日志中
+	z, err := cfg.Build(zap.AddStacktrace(zapcore.PanicLevel), zap.AddCallerSkip(1), zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &redactCore{Core: core}
+	}))
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -0,0 +1,117 @@
+package log
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"sync"
+)
+
+// Redacted 是日志中敏感信息被替换后的值
+const Redacted = "******"
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string // 需要在日志中脱敏的值，例如数据库密码、JWT 签发密钥
+)
+
+// sensitiveKeys 中的字符串出现在配置项或日志字段名中时，对应的值被视为敏感信息
+var sensitiveKeys = []string{"password", "secret", "token", "authorization", "apikey", "api-key", "api_key"}
+
+// AddSecrets 注册需要脱敏的值，之后所有日志的 message 和字段中出现这些值都会被替换为 Redacted
+func AddSecrets(values ...string) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+
+	for _, v := range values {
+		if v != "" {
+			secrets = append(secrets, v)
+		}
+	}
+}
+
+// IsSensitiveKey 判断配置项或日志字段名 key 对应的值是否为敏感信息，例如 db.password、jwt-secret
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact 将 s 中出现的已注册敏感值替换为 Redacted
+func Redact(s string) string {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+
+	for _, v := range secrets {
+		s = strings.ReplaceAll(s, v, Redacted)
+	}
+	return s
+}
+
+// containsSecret 判断 s 中是否包含已注册的敏感值
+func containsSecret(s string) bool {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+
+	for _, v := range secrets {
+		if strings.Contains(s, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactCore 包装 zapcore.Core，在日志写出前对 message 和字段做脱敏
+type redactCore struct {
+	zapcore.Core
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(redactFields(fields))}
+}
+
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = Redact(ent.Message)
+	return c.Core.Write(ent, redactFields(fields))
+}
+
+// redactFields 对字段脱敏：字段名为敏感字段时直接替换整个值，否则替换值中出现的已注册敏感值
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		redacted[i] = redactField(f)
+	}
+	return redacted
+}
+
+func redactField(f zapcore.Field) zapcore.Field {
+	if IsSensitiveKey(f.Key) {
+		return zap.String(f.Key, Redacted)
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		f.String = Redact(f.String)
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok && containsSecret(err.Error()) {
+			return zap.String(f.Key, Redact(err.Error()))
+		}
+	case zapcore.StringerType, zapcore.ReflectType:
+		if s := fmt.Sprintf("%+v", f.Interface); containsSecret(s) {
+			return zap.String(f.Key, Redact(s))
+		}
+	}
+	return f
+}
@@ -0,0 +1,39 @@
+package log
+
+import (
+	"errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"strings"
+	"testing"
+)
+
+func TestRedactCore(t *testing.T) {
+	AddSecrets("hunter2")
+
+	core, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(&redactCore{Core: core}).Sugar().With("dsn", "root:hunter2@tcp(127.0.0.1:3306)/miniblog")
+
+	logger.Infow("connecting with hunter2",
+		"password", "anything",
+		"err", errors.New("access denied for hunter2"),
+		"options", struct{ Password string }{"hunter2"},
+		"user", "alice",
+	)
+
+	entry := logs.All()[0]
+	if strings.Contains(entry.Message, "hunter2") {
+		t.Errorf("message is not redacted: %q", entry.Message)
+	}
+	for key, value := range entry.ContextMap() {
+		if strings.Contains(value.(string), "hunter2") {
+			t.Errorf("field %s is not redacted: %q", key, value)
+		}
+	}
+	if got := entry.ContextMap()["password"]; got != Redacted {
+		t.Errorf("password = %q, want %q", got, Redacted)
+	}
+	if got := entry.ContextMap()["user"]; got != "alice" {
+		t.Errorf("user = %q, want alice", got)
+	}
+}
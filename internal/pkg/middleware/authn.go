@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/known"
+	"miniblog/pkg/token"
+	"strings"
+)
+
+// apiKeyScheme 是使用 API Key 认证时 Authorization 请求头的前缀
+const apiKeyScheme = "ApiKey "
+
+// APIKeyAuthenticator 校验 API Key 是否有效且被授予了 scope，成功时返回 API Key 所属的用户名
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key, scope string) (string, error)
+}
+
+// Authn 是认证中间件，使用 tokens 从 `Authorization: Bearer <token>` 请求头中解析 JWT Token，并将用户名保存到 gin.Context 中。
+// scope 不为空时同时接受 `Authorization: ApiKey <key>`，API Key 必须被授予了 scope；
+// scope 为空的接口（例如修改密码、管理两步验证和 API Key）只能使用登录签发的 JWT Token 调用
+func Authn(tokens *token.Config, keys APIKeyAuthenticator, scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if key, ok := strings.CutPrefix(header, apiKeyScheme); ok {
+			if scope == "" {
+				core.WriteResponse(c, errno.ErrAPIKeyNotAllowed, nil)
+				c.Abort()
+				return
+			}
+
+			username, err := keys.AuthenticateAPIKey(c, strings.TrimSpace(key), scope)
+			if err != nil {
+				core.WriteResponse(c, err, nil)
+				c.Abort()
+				return
+			}
+
+			c.Set(known.XUsernameKey, username)
+			c.Next()
+			return
+		}
+
+		username, err := tokens.ParseHeader(header)
+		if err != nil {
+			core.WriteResponse(c, errno.ErrTokenInvalid, nil)
+			c.Abort()
+			return
+		}
+
+		c.Set(known.XUsernameKey, username)
+		c.Next()
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"io"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"net/http"
+)
+
+// BodyLimit 是一个 Gin 中间件，限制请求体的最大字节数，超出限制时在参数绑定之前返回 413。
+// 对于声明了 Content-Length 的请求直接根据长度判断；对于分块传输的请求，会先读取最多 maxBytes 字节到内存中再判断
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if maxBytes <= 0 || c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > maxBytes {
+			abortTooLarge(c, maxBytes)
+			return
+		}
+
+		if c.Request.ContentLength >= 0 {
+			// Content-Length 可能与实际的请求体大小不一致，仍然需要限制读取的字节数
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBytes+1))
+		if err != nil {
+			core.WriteResponse(c, errno.ErrBind, nil)
+			c.Abort()
+			return
+		}
+		if int64(len(body)) > maxBytes {
+			abortTooLarge(c, maxBytes)
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+// abortTooLarge 返回 413 错误，并结束请求
+func abortTooLarge(c *gin.Context, maxBytes int64) {
+	core.WriteResponse(c, errno.ErrRequestEntityTooLarge.WithMessage("Request body exceeds the limit of %d bytes.", maxBytes), nil)
+	c.Abort()
+}
@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CorsOptions 包含跨域资源共享（CORS）相关的配置项
+type CorsOptions struct {
+	// 允许跨域访问的源，支持精确匹配（https://blog.example.com）、子域名通配（https://*.example.com）以及 `*`
+	AllowOrigins []string
+	// 允许跨域请求使用的方法
+	AllowMethods []string
+	// 允许跨域请求携带的请求头
+	AllowHeaders []string
+	// 允许浏览器读取的返回头
+	ExposeHeaders []string
+	// 是否允许跨域请求携带 Cookie 等凭证。AllowOrigins 包含 `*` 时不会生效
+	AllowCredentials bool
+	// 预检请求结果的缓存时间
+	MaxAge time.Duration
+}
+
+// corsPolicy 是 CorsOptions 预处理后的结果，避免每个请求都重复处理配置
+type corsPolicy struct {
+	opts          *CorsOptions
+	allowAll      bool
+	exactOrigins  map[string]struct{}
+	wildOrigins   []wildcardOrigin
+	allowMethods  map[string]struct{}
+	allowHeaders  map[string]struct{}
+	methodsValue  string
+	headersValue  string
+	exposeValue   string
+	maxAgeSeconds string
+}
+
+// wildcardOrigin 表示形如 `https://*.example.com` 的通配源
+type wildcardOrigin struct {
+	prefix string // `https://`
+	suffix string // `.example.com`
+}
+
+// Cors 是一个 Gin 中间件，根据配置处理浏览器跨域请求。
+// 对于预检（OPTIONS）请求，会校验请求的源、方法和请求头，校验通过返回 204，否则返回 403，并结束请求
+func Cors(opts *CorsOptions) gin.HandlerFunc {
+	p := newCorsPolicy(opts)
+
+	return func(c *gin.Context) {
+		origin := c.Request.Header.Get("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.Request.Header.Get("Access-Control-Request-Method") != ""
+
+		// 非跨域请求，直接处理
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		// 返回结果与请求的 Origin 有关，需要告知缓存
+		c.Writer.Header().Add("Vary", "Origin")
+
+		if !p.originAllowed(origin) {
+			if preflight {
+				core.WriteResponse(c, errno.ErrCorsRejected.WithMessage("Origin %s is not allowed.", origin), nil)
+				c.Abort()
+				return
+			}
+			// 不返回跨域相关的 Header，由浏览器拦截响应
+			c.Next()
+			return
+		}
+
+		if !preflight {
+			p.writeAllowOrigin(c, origin)
+			if p.exposeValue != "" {
+				c.Header("Access-Control-Expose-Headers", p.exposeValue)
+			}
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+
+		method := strings.ToUpper(c.Request.Header.Get("Access-Control-Request-Method"))
+		if _, ok := p.allowMethods[method]; !ok {
+			core.WriteResponse(c, errno.ErrCorsRejected.WithMessage("Method %s is not allowed.", method), nil)
+			c.Abort()
+			return
+		}
+
+		for _, header := range strings.Split(c.Request.Header.Get("Access-Control-Request-Headers"), ",") {
+			header = strings.ToLower(strings.TrimSpace(header))
+			if header == "" {
+				continue
+			}
+			if _, ok := p.allowHeaders[header]; !ok {
+				core.WriteResponse(c, errno.ErrCorsRejected.WithMessage("Header %s is not allowed.", header), nil)
+				c.Abort()
+				return
+			}
+		}
+
+		p.writeAllowOrigin(c, origin)
+		c.Header("Access-Control-Allow-Methods", p.methodsValue)
+		if p.headersValue != "" {
+			c.Header("Access-Control-Allow-Headers", p.headersValue)
+		}
+		if p.maxAgeSeconds != "" {
+			c.Header("Access-Control-Max-Age", p.maxAgeSeconds)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// newCorsPolicy 预处理 CorsOptions
+func newCorsPolicy(opts *CorsOptions) *corsPolicy {
+	p := &corsPolicy{
+		opts:         opts,
+		exactOrigins: make(map[string]struct{}),
+		allowMethods: make(map[string]struct{}),
+		allowHeaders: make(map[string]struct{}),
+	}
+
+	for _, origin := range opts.AllowOrigins {
+		origin = strings.ToLower(strings.TrimSpace(origin))
+		switch {
+		case origin == "*":
+			p.allowAll = true
+		case strings.Contains(origin, "://*."):
+			i := strings.Index(origin, "*")
+			p.wildOrigins = append(p.wildOrigins, wildcardOrigin{prefix: origin[:i], suffix: origin[i+1:]})
+		case origin != "":
+			p.exactOrigins[origin] = struct{}{}
+		}
+	}
+
+	methods := make([]string, 0, len(opts.AllowMethods))
+	for _, method := range opts.AllowMethods {
+		method = strings.ToUpper(strings.TrimSpace(method))
+		p.allowMethods[method] = struct{}{}
+		methods = append(methods, method)
+	}
+	p.methodsValue = strings.Join(methods, ",")
+
+	for _, header := range opts.AllowHeaders {
+		p.allowHeaders[strings.ToLower(strings.TrimSpace(header))] = struct{}{}
+	}
+	p.headersValue = strings.Join(opts.AllowHeaders, ",")
+	p.exposeValue = strings.Join(opts.ExposeHeaders, ",")
+
+	if opts.MaxAge > 0 {
+		p.maxAgeSeconds = strconv.Itoa(int(opts.MaxAge.Seconds()))
+	}
+
+	return p
+}
+
+// originAllowed 判断请求的源是否允许跨域访问
+func (p *corsPolicy) originAllowed(origin string) bool {
+	if p.allowAll {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	if _, ok := p.exactOrigins[origin]; ok {
+		return true
+	}
+	for _, w := range p.wildOrigins {
+		// 通配符至少匹配一级子域名，且 `https://*.example.com` 不匹配 `https://example.com`
+		if len(origin) > len(w.prefix)+len(w.suffix) && strings.HasPrefix(origin, w.prefix) && strings.HasSuffix(origin, w.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeAllowOrigin 设置 `Access-Control-Allow-Origin` 等返回头。
+// 规范不允许 `*` 和凭证一起使用，允许所有源时不返回 `Access-Control-Allow-Credentials`，避免回显任意源导致凭证泄露
+func (p *corsPolicy) writeAllowOrigin(c *gin.Context, origin string) {
+	if p.allowAll {
+		c.Header("Access-Control-Allow-Origin", "*")
+		return
+	}
+
+	c.Header("Access-Control-Allow-Origin", origin)
+	if p.opts.AllowCredentials {
+		c.Header("Access-Control-Allow-Credentials", "true")
+	}
+}
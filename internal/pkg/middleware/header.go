@@ -14,24 +14,8 @@ func NoCache(c *gin.Context) {
 	c.Next()
 }
 
-// Cors 是一个 Gin 中间件，用来设置 Options 请求的返回头，然后退出中间件链，并结束请求（浏览器跨域设置）
-func Cors(c *gin.Context) {
-	// 若 HTTP 请求不是 Options 跨域请求，则继续处理 HTTP 请求；否则设置跨域 Header，并返回
-	if c.Request.Method != "OPTIONS" {
-		c.Next()
-	} else {
-		c.Header("Access-Control-Allow-Origin", "*")                                  // * 表示接受任何域名的请求
-		c.Header("Access-Control-Allow-Methods", "GET,POST,PUT,PATCH,DELETE,OPTIONS") // 表明服务器支持的所有跨域请求的方法
-		c.Header("Access-Control-Allow-Headers", "authorization, origin, content-type, accept")
-		c.Header("Allow", "HEAD,GET,POST,PUT,PATCH,DELETE,OPTIONS")
-		c.Header("Content-Type", "application/json")
-		c.AbortWithStatus(200)
-	}
-}
-
-// Secure 是一个 Gin 中间件，用来添加一些安全和资源访问相关的 HTTP 头
+// Secure 是一个 Gin 中间件，用来添加一些安全相关的 HTTP 头。跨域相关的 HTTP 头由 Cors 中间件负责
 func Secure(c *gin.Context) {
-	c.Header("Access-Control-Allow-Origin", "*")
 	c.Header("X-Frame-Options", "DENY")
 	c.Header("X-Content-Type-Options", "nosniff")
 	c.Header("X-XSS-Protection", "1; mode=block")
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"math"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"miniblog/internal/pkg/known"
+	"miniblog/internal/pkg/log"
+	"miniblog/pkg/ratelimit"
+	"strconv"
+	"time"
+)
+
+// RateLimit 是一个 Gin 中间件，使用令牌桶算法对请求限流。
+// 请求需要同时满足所有策略才会放行，被拒绝的请求不消耗任何策略的令牌，返回头中的 `X-RateLimit-*` 反映最严格的那条策略
+func RateLimit(limiter ratelimit.Limiter, policies ...ratelimit.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		buckets := make([]ratelimit.Bucket, len(policies))
+		for i, policy := range policies {
+			buckets[i] = ratelimit.Bucket{Key: bucketKey(c, policy), Policy: policy}
+		}
+
+		results, err := limiter.Take(c, buckets...)
+		if err != nil {
+			// 限流后端不可用时放行请求，避免限流组件故障导致整个服务不可用
+			log.C(c).Errorw("Failed to take token from rate limiter", "err", err)
+			c.Next()
+			return
+		}
+
+		var tightest *ratelimit.Result
+		for _, result := range results {
+			if tightest == nil || (tightest.Allowed && (!result.Allowed || result.Remaining < tightest.Remaining)) {
+				tightest = result
+			}
+		}
+
+		if tightest == nil {
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(tightest.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(tightest.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(tightest.ResetAfter)))
+
+		if !tightest.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(tightest.RetryAfter)))
+			core.WriteResponse(c, errno.ErrTooManyRequests, nil)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// bucketKey 根据策略的限流维度，计算请求对应的令牌桶 key
+func bucketKey(c *gin.Context, policy ratelimit.Policy) string {
+	var value string
+	switch policy.Key {
+	case ratelimit.KeyRoute:
+		value = c.FullPath()
+	case ratelimit.KeyUsername:
+		// 未认证的请求没有用户名，退化为按 IP 限流
+		if username := c.GetString(known.XUsernameKey); username != "" {
+			value = "user:" + username
+		} else {
+			value = "ip:" + c.ClientIP()
+		}
+	default:
+		value = c.ClientIP()
+	}
+
+	return policy.Name + ":" + string(policy.Key) + ":" + value
+}
+
+// ceilSeconds 将 time.Duration 向上取整为秒数
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,14 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"miniblog/pkg/db"
+)
+
+// ReadYourWrites 是一个 Gin 中间件，为每个请求开启 read-your-writes：请求中执行过写操作后，后续的读操作都会访问主库
+func ReadYourWrites() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(db.WithReadYourWrites(c.Request.Context()))
+		c.Next()
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"miniblog/internal/pkg/core"
+	"miniblog/internal/pkg/errno"
+	"time"
+)
+
+// RouteTimeout 定义了单个路由的超时时间，Route 的格式为 `<METHOD> <PATH>`，例如 `POST /v1/users`
+type RouteTimeout struct {
+	Route   string        `mapstructure:"route"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// Timeout 是一个 Gin 中间件，为每个请求的 context 设置截止时间。
+// 截止时间会通过 context.Context 传递到 biz 层和 store 层（gorm 的 WithContext），超时后数据库查询会被取消。
+// routes 中配置了的路由使用各自的超时时间，其余路由使用 defaultTimeout，超时时间 <= 0 表示不限制
+func Timeout(defaultTimeout time.Duration, routes ...RouteTimeout) gin.HandlerFunc {
+	timeouts := make(map[string]time.Duration, len(routes))
+	for _, r := range routes {
+		timeouts[r.Route] = r.Timeout
+	}
+
+	return func(c *gin.Context) {
+		timeout, ok := timeouts[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			timeout = defaultTimeout
+		}
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		// 注意：需要开启 gin.Engine.ContextWithFallback，gin.Context 的 Deadline、Done 和 Err 方法才会使用 Request.Context()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		// handler 没有返回任何数据，但请求已经超时，返回超时错误
+		if !c.Writer.Written() && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			core.WriteResponse(c, errno.ErrRequestTimeout, nil)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// APIKeyM 存储用户创建的 API Key，API Key 本身只在创建时返回一次，数据库中只保存哈希值
+type APIKeyM struct {
+	ID         int64      `gorm:"column:id;primary_key"`
+	Username   string     `gorm:"column:username;not null;uniqueIndex:idx_username_name"`
+	Name       string     `gorm:"column:name;not null;uniqueIndex:idx_username_name"` // 同一个用户的 API Key 名称不能重复
+	Prefix     string     `gorm:"column:prefix;not null;uniqueIndex:prefix"`          // API Key 中明文的前缀，用于查找和展示
+	KeyHash    string     `gorm:"column:keyHash;not null"`
+	Scopes     string     `gorm:"column:scopes;not null"` // 授权范围，以 `,` 分隔，例如 `posts:read,posts:write`
+	ExpiresAt  *time.Time `gorm:"column:expiresAt"`       // 为 nil 表示永不过期
+	LastUsedAt *time.Time `gorm:"column:lastUsedAt"`
+	CreatedAt  time.Time  `gorm:"column:createdAt"`
+	UpdatedAt  time.Time  `gorm:"column:updatedAt"`
+}
+
+// TableName 指定映射的表名
+func (k *APIKeyM) TableName() string {
+	return "api_key"
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// IdentityM 记录外部身份提供方（OIDC IdP）中的用户与 miniblog 用户的绑定关系
+type IdentityM struct {
+	ID        int64     `gorm:"column:id;primary_key"`
+	Username  string    `gorm:"column:username;not null;index:idx_identity_username"`
+	Issuer    string    `gorm:"column:issuer;not null;uniqueIndex:idx_issuer_subject"`  // IdP 的 issuer
+	Subject   string    `gorm:"column:subject;not null;uniqueIndex:idx_issuer_subject"` // 用户在 IdP 中的唯一标识，即 ID Token 中的 sub
+	CreatedAt time.Time `gorm:"column:createdAt"`
+	UpdatedAt time.Time `gorm:"column:updatedAt"`
+}
+
+// TableName 指定映射的表名
+func (i *IdentityM) TableName() string {
+	return "identity"
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// InvitationM 存储管理员生成的邀请码，registration.mode 为 invite-only 时需要使用邀请码注册
+type InvitationM struct {
+	ID        int64      `gorm:"column:id;primary_key"`
+	Code      string     `gorm:"column:code;not null;uniqueIndex:code"`
+	CreatedBy string     `gorm:"column:createdBy;not null"` // 生成邀请码的管理员
+	MaxUses   int        `gorm:"column:maxUses;not null"`   // 最多可以注册的用户数
+	Uses      int        `gorm:"column:uses;not null;default:0"`
+	ExpiresAt *time.Time `gorm:"column:expiresAt"` // 为 nil 表示永不过期
+	CreatedAt time.Time  `gorm:"column:createdAt"`
+	UpdatedAt time.Time  `gorm:"column:updatedAt"`
+}
+
+// TableName 指定映射的表名
+func (i *InvitationM) TableName() string {
+	return "invitation"
+}
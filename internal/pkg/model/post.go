@@ -1,19 +1,29 @@
 package model
 
-import "time"
+import (
+	"gorm.io/gorm"
+	"time"
+)
 
 // PostM 存储博客信息
 type PostM struct {
-	ID        int64     `gorm:"column:id;primary_key"`
-	Username  string    `gorm:"column:username;not null"`
-	PostID    string    `gorm:"column:postID;not null"`
-	Title     string    `gorm:"column:title;not null"`
-	Content   string    `gorm:"column:content"`
-	CreatedAt time.Time `gorm:"column:createdAt"`
-	UpdatedAt time.Time `gorm:"column:updatedAt"`
+	ID          int64     `gorm:"column:id;primary_key"`
+	Username    string    `gorm:"column:username;not null;index:idx_username"`
+	PostID      string    `gorm:"column:postID;not null;uniqueIndex:postID"`
+	Title       string    `gorm:"column:title;not null"`
+	Content     string    `gorm:"column:content"`
+	Format      string    `gorm:"column:format;not null;default:markdown"` // 内容格式，取值见 known.PostFormat*
+	ContentHTML string    `gorm:"column:contentHTML"`                      // 根据内容格式渲染并过滤后的 HTML，在写入时生成
+	CreatedAt   time.Time `gorm:"column:createdAt"`
+	UpdatedAt   time.Time `gorm:"column:updatedAt"`
+
+	Status    string     `gorm:"column:status;not null;default:published;index:idx_post_status,priority:1"` // 博客状态，取值见 known.PostStatus*
+	PublishAt *time.Time `gorm:"column:publishAt;index:idx_post_status,priority:2"`                         // 发布时间，定时发布的博客为计划发布的时间，草稿为 nil
+
+	DeletedAt gorm.DeletedAt `gorm:"column:deletedAt;index:idx_post_deletedAt"` // 删除时间，已删除的博客默认不会被查询到，在保留期内可以恢复
 }
 
-// TableName 指定映射的 MySQL 表名
+// TableName 指定映射的表名
 func (p *PostM) TableName() string {
 	return "post"
 }
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// PostRevisionM 存储博客的一个历史版本。发布博客时创建第 1 个版本，之后每次修改或回滚都会创建一个新的版本
+type PostRevisionM struct {
+	ID          int64     `gorm:"column:id;primary_key"`
+	PostID      string    `gorm:"column:postID;not null;uniqueIndex:idx_post_revision"`
+	Revision    int       `gorm:"column:revision;not null;uniqueIndex:idx_post_revision"` // 版本号，从 1 开始递增
+	Username    string    `gorm:"column:username;not null"`                               // 创建该版本的用户
+	Title       string    `gorm:"column:title;not null"`
+	Content     string    `gorm:"column:content"`
+	Format      string    `gorm:"column:format;not null;default:markdown"`
+	ContentHash string    `gorm:"column:contentHash;not null"` // 内容的 SHA-256 哈希值，十六进制编码
+	CreatedAt   time.Time `gorm:"column:createdAt"`
+}
+
+// TableName 指定映射的表名
+func (r *PostRevisionM) TableName() string {
+	return "post_revision"
+}
@@ -2,7 +2,6 @@ package model
 
 import (
 	"gorm.io/gorm"
-	"miniblog/pkg/auth"
 	"time"
 )
 
@@ -10,24 +9,29 @@ import (
 // 结构体命名规范：表名首字母大写➕M（Model）
 type UserM struct {
 	ID        int64     `gorm:"column:id;primary_key"`
-	Username  string    `gorm:"column:username;not null"`
+	Username  string    `gorm:"column:username;not null;uniqueIndex:username"`
 	Password  string    `gorm:"column:password;not null"`
 	Nickname  string    `gorm:"column:nickname"`
 	Email     string    `gorm:"column:email"`
 	Phone     string    `gorm:"column:phone"`
+	Role      string    `gorm:"column:role;not null;default:user"` // 用户角色，可选值：user,admin
 	CreatedAt time.Time `gorm:"column:createdAt"`
 	UpdatedAt time.Time `gorm:"column:updatedAt"`
+
+	FailedLogins int        `gorm:"column:failedLogins;not null;default:0"` // 连续登录失败的次数，登录成功或管理员解锁后清零
+	LockedUntil  *time.Time `gorm:"column:lockedUntil"`                     // 在该时间之前禁止登录，为 nil 表示未锁定
+
+	TOTPSecret      string `gorm:"column:totpSecret"`                         // base32 编码的 TOTP 密钥，绑定后但尚未确认时 TOTPEnabled 为 false
+	TOTPEnabled     bool   `gorm:"column:totpEnabled;not null;default:false"` // 是否已开启两步验证
+	TOTPLastCounter int64  `gorm:"column:totpLastCounter;not null;default:0"` // 最近一次使用的验证码对应的计数器，用于拒绝重复使用的验证码
+	RecoveryCodes   string `gorm:"column:recoveryCodes;type:text"`            // 未使用的恢复码的哈希值，以 `,` 分隔
+
+	EmailVerified bool `gorm:"column:emailVerified;not null;default:false"` // 邮箱是否已通过验证邮件中的链接验证
+
+	DeletedAt gorm.DeletedAt `gorm:"column:deletedAt;index:idx_user_deletedAt"` // 删除时间，已删除的用户默认不会被查询到，在保留期内可以恢复
 }
 
-// TableName 指定映射的 MySQL 表名
+// TableName 指定映射的表名。列名和表名在 SQL 中均由 gorm 加引号，因此 `user` 这类保留字和驼峰列名在 MySQL 和 PostgreSQL 中都可以使用
 func (u *UserM) TableName() string {
 	return "user"
 }
-
-// BeforeCreate 加密明文密钥
-func (u *UserM) BeforeCreate(db *gorm.DB) (err error) {
-	if u.Password, err = auth.Encrypt(u.Password); err != nil {
-		return err
-	}
-	return nil
-}
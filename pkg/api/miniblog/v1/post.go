@@ -0,0 +1,94 @@
+package v1
+
+import "time"
+
+// Post 是博客的详细信息
+type Post struct {
+	PostID      string     `json:"postID"`
+	Username    string     `json:"username"`
+	Title       string     `json:"title"`
+	Content     string     `json:"content"`             // 博客的原始内容
+	Format      string     `json:"format"`              // 内容格式：markdown、html 或 plain
+	ContentHTML string     `json:"contentHTML"`         // 根据内容格式渲染的 HTML，已过滤脚本和危险的属性，可以直接嵌入页面
+	Status      string     `json:"status"`              // 博客状态：draft、scheduled、published 或 archived
+	PublishAt   *time.Time `json:"publishAt,omitempty"` // 发布时间，定时发布的博客为计划发布的时间
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"` // 只有已删除的博客才有删除时间
+}
+
+// CreatePostRequest 定义了 `POST /v1/posts` 接口的请求参数。Status 为空时，指定了 PublishAt 则定时发布，否则立即发布
+type CreatePostRequest struct {
+	Title     string     `json:"title" valid:"required,stringlength(1|256)"`
+	Content   string     `json:"content" valid:"required"`
+	Format    string     `json:"format" valid:"in(markdown|html|plain)"` // 为空时使用 markdown
+	Status    string     `json:"status" valid:"in(draft|scheduled|published)"`
+	PublishAt *time.Time `json:"publishAt"` // 定时发布的时间，只能用于 scheduled 状态，需要晚于当前时间
+}
+
+// CreatePostResponse 定义了 `POST /v1/posts` 接口的返回参数
+type CreatePostResponse struct {
+	PostID string `json:"postID"`
+}
+
+// UpdatePostRequest 定义了 `PUT /v1/posts/:postID` 接口的请求参数，为 nil 的字段不更新。只指定 PublishAt 时修改为定时发布
+type UpdatePostRequest struct {
+	Title     *string    `json:"title" valid:"stringlength(1|256)"`
+	Content   *string    `json:"content"`
+	Format    *string    `json:"format" valid:"in(markdown|html|plain)"`
+	Status    *string    `json:"status" valid:"in(draft|scheduled|published|archived)"`
+	PublishAt *time.Time `json:"publishAt"`
+}
+
+// ListPostsRequest 定义了 `GET /v1/posts` 和 `GET /v1/trash/posts` 接口的请求参数，Username 为空时查询所有用户的博客。
+// `GET /v1/posts` 只返回已发布的博客和当前登录用户自己的博客
+type ListPostsRequest struct {
+	Username string `form:"username"`
+	Status   string `form:"status" valid:"in(draft|scheduled|published|archived)"` // 只查询该状态的博客，不适用于 `GET /v1/trash/posts`
+	Offset   int    `form:"offset" valid:"range(0|2147483647)"`
+	Limit    int    `form:"limit" valid:"range(0|100)"` // 为 0 时使用默认值 20
+}
+
+// ListPostsResponse 定义了 `GET /v1/posts` 和 `GET /v1/trash/posts` 接口的返回参数
+type ListPostsResponse struct {
+	TotalCount int64   `json:"totalCount"`
+	Posts      []*Post `json:"posts"`
+}
+
+// PostRevision 是博客的一个历史版本
+type PostRevision struct {
+	Revision    int       `json:"revision"`
+	Username    string    `json:"username"` // 创建该版本的用户
+	Title       string    `json:"title"`
+	Content     string    `json:"content,omitempty"` // 列表接口不返回内容
+	Format      string    `json:"format"`
+	ContentHash string    `json:"contentHash"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// ListPostRevisionsRequest 定义了 `GET /v1/posts/:postID/revisions` 接口的请求参数
+type ListPostRevisionsRequest struct {
+	Offset int `form:"offset" valid:"range(0|2147483647)"`
+	Limit  int `form:"limit" valid:"range(0|100)"` // 为 0 时使用默认值 20
+}
+
+// ListPostRevisionsResponse 定义了 `GET /v1/posts/:postID/revisions` 接口的返回参数，版本按版本号倒序排列
+type ListPostRevisionsResponse struct {
+	TotalCount int64           `json:"totalCount"`
+	Revisions  []*PostRevision `json:"revisions"`
+}
+
+// DiffPostRevisionsRequest 定义了 `GET /v1/posts/:postID/diff` 接口的请求参数
+type DiffPostRevisionsRequest struct {
+	From int `form:"from" valid:"required,range(1|2147483647)"`
+	To   int `form:"to" valid:"range(0|2147483647)"` // 为 0 时与最新版本比较
+}
+
+// DiffPostRevisionsResponse 定义了 `GET /v1/posts/:postID/diff` 接口的返回参数
+type DiffPostRevisionsResponse struct {
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	FromTitle string `json:"fromTitle"`
+	ToTitle   string `json:"toTitle"`
+	Diff      string `json:"diff"` // 内容按行比较的 unified 格式差异，内容相同时为空
+}
@@ -1,10 +1,158 @@
 package v1
 
+import "time"
+
 // CreateUserRequest 定义了 `POST /v1/users` 接口的请求参数
 type CreateUserRequest struct {
-	Username string `json:"username" valid:"alphanum,required,stringlength(1|255)"` // valid tag 为自定义的参数校验
-	Password string `json:"password" valid:"required,stringlength(6|18)"`
-	Nickname string `json:"nickname" valid:"required,stringlength(1|255)"`
-	Email    string `json:"email" valid:"required,email"`
-	Phone    string `json:"phone" valid:"required,stringlength(11|11)"`
+	Username   string `json:"username" valid:"alphanum,required,stringlength(1|255)"` // valid tag 为自定义的参数校验
+	Password   string `json:"password" valid:"required"`                              // 密码的长度、复杂度等由 biz 层按照密码策略校验
+	Nickname   string `json:"nickname" valid:"required,stringlength(1|255)"`
+	Email      string `json:"email" valid:"required,email"`
+	Phone      string `json:"phone" valid:"required,stringlength(11|11)"`
+	InviteCode string `json:"inviteCode,omitempty"` // 管理员生成的邀请码，registration.mode 为 invite-only 时必填
+}
+
+// LoginRequest 定义了 `POST /login` 接口的请求参数
+type LoginRequest struct {
+	Username string `json:"username" valid:"alphanum,required,stringlength(1|255)"`
+	Password string `json:"password" valid:"required"`
+}
+
+// LoginResponse 定义了 `POST /login` 和 `POST /login/2fa` 接口的返回参数。
+// 开启了两步验证的用户通过密码校验后，TwoFactorRequired 为 true，Token 为空，需要使用 Challenge 调用 `POST /login/2fa` 完成登录
+type LoginResponse struct {
+	Token             string `json:"token,omitempty"`
+	TwoFactorRequired bool   `json:"twoFactorRequired,omitempty"`
+	Challenge         string `json:"challenge,omitempty"`
+}
+
+// OIDCLoginResponse 定义了 `POST /login/oidc` 接口的返回参数。
+// 客户端需要跳转到 AuthURL，IdP 回调时将 code、state 和 Session 一起提交到 `POST /login/oidc/callback`
+type OIDCLoginResponse struct {
+	AuthURL string `json:"authURL"`
+	Session string `json:"session"`
+}
+
+// OIDCCallbackRequest 定义了 `POST /login/oidc/callback` 接口的请求参数
+type OIDCCallbackRequest struct {
+	Code    string `json:"code" valid:"required"`
+	State   string `json:"state" valid:"required"`
+	Session string `json:"session" valid:"required"`
+}
+
+// LoginTwoFactorRequest 定义了 `POST /login/2fa` 接口的请求参数，Code 为验证器应用中的 6 位验证码或一次性恢复码
+type LoginTwoFactorRequest struct {
+	Challenge string `json:"challenge" valid:"required"`
+	Code      string `json:"code" valid:"required,stringlength(1|32)"`
+}
+
+// ChangePasswordRequest 定义了 `PUT /v1/users/:name/change-password` 接口的请求参数
+type ChangePasswordRequest struct {
+	OldPassword string `json:"oldPassword" valid:"required"`
+	NewPassword string `json:"newPassword" valid:"required"`
+}
+
+// EnrollTwoFactorResponse 定义了 `POST /v1/users/:name/2fa` 接口的返回参数
+type EnrollTwoFactorResponse struct {
+	Secret string `json:"secret"` // base32 编码的 TOTP 密钥，用于手动输入
+	URI    string `json:"uri"`    // `otpauth://` URI，可以生成二维码供验证器应用扫描
+}
+
+// TwoFactorCodeRequest 定义了 `POST /v1/users/:name/2fa/confirm` 和 `DELETE /v1/users/:name/2fa` 接口的请求参数
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" valid:"required,stringlength(1|32)"`
+}
+
+// ConfirmTwoFactorResponse 定义了 `POST /v1/users/:name/2fa/confirm` 接口的返回参数，恢复码只会返回这一次
+type ConfirmTwoFactorResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// VerifyEmailRequest 定义了 `POST /v1/users/:name/verify-email/confirm` 接口的请求参数，Token 来自验证邮件中的链接
+type VerifyEmailRequest struct {
+	Token string `json:"token" valid:"required"`
+}
+
+// PasswordResetRequest 定义了 `POST /v1/password-reset` 接口的请求参数
+type PasswordResetRequest struct {
+	Email string `json:"email" valid:"required,email"`
+}
+
+// ConfirmPasswordResetRequest 定义了 `POST /v1/password-reset/confirm` 接口的请求参数，Token 来自重置密码邮件中的链接
+type ConfirmPasswordResetRequest struct {
+	Token       string `json:"token" valid:"required"`
+	NewPassword string `json:"newPassword" valid:"required"`
+}
+
+// CreateAPIKeyRequest 定义了 `POST /v1/users/:name/api-keys` 接口的请求参数
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" valid:"required,stringlength(1|255)"`
+	Scopes    []string   `json:"scopes" valid:"required"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"` // 为空表示永不过期
+}
+
+// APIKey 是 API Key 的信息，不包含 API Key 本身
+type APIKey struct {
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // 用于在 `DELETE /v1/users/:name/api-keys/:prefix` 中指定 API Key
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// CreateAPIKeyResponse 定义了 `POST /v1/users/:name/api-keys` 接口的返回参数，Key 只会返回这一次
+type CreateAPIKeyResponse struct {
+	Key string `json:"key"`
+	APIKey
+}
+
+// ListAPIKeysResponse 定义了 `GET /v1/users/:name/api-keys` 接口的返回参数
+type ListAPIKeysResponse struct {
+	APIKeys []*APIKey `json:"apiKeys"`
+}
+
+// CreateInvitationRequest 定义了 `POST /v1/invitations` 接口的请求参数
+type CreateInvitationRequest struct {
+	MaxUses   int        `json:"maxUses,omitempty" valid:"range(0|10000)"` // 为 0 时使用默认值 1
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`                      // 为空表示永不过期
+}
+
+// Invitation 是邀请码的信息
+type Invitation struct {
+	Code      string     `json:"code"`
+	CreatedBy string     `json:"createdBy"`
+	MaxUses   int        `json:"maxUses"`
+	Uses      int        `json:"uses"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// ListInvitationsResponse 定义了 `GET /v1/invitations` 接口的返回参数
+type ListInvitationsResponse struct {
+	Invitations []*Invitation `json:"invitations"`
+}
+
+// User 是用户的基本信息，不包含密码等敏感信息
+type User struct {
+	Username  string     `json:"username"`
+	Nickname  string     `json:"nickname"`
+	Email     string     `json:"email"`
+	Phone     string     `json:"phone"`
+	Role      string     `json:"role"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"` // 只有已删除的用户才有删除时间
+}
+
+// ListUsersRequest 定义了 `GET /v1/trash/users` 接口的请求参数
+type ListUsersRequest struct {
+	Offset int `form:"offset" valid:"range(0|2147483647)"`
+	Limit  int `form:"limit" valid:"range(0|100)"` // 为 0 时使用默认值 20
+}
+
+// ListUsersResponse 定义了 `GET /v1/trash/users` 接口的返回参数
+type ListUsersResponse struct {
+	TotalCount int64   `json:"totalCount"`
+	Users      []*User `json:"users"`
 }
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// API Key 的格式为 `mb_<prefix>_<secret>`：prefix 以明文保存，用于查找 API Key；secret 只在创建时返回一次，数据库中只保存整个 key 的哈希值
+const (
+	apiKeyScheme      = "mb"
+	apiKeyPrefixBytes = 6
+	apiKeySecretBytes = 32
+)
+
+// GenerateAPIKey 生成一个新的 API Key，返回展示给用户的 key、用于查找的 prefix 和用于保存的哈希值
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	buf := make([]byte, apiKeyPrefixBytes+apiKeySecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", err
+	}
+
+	prefix = hex.EncodeToString(buf[:apiKeyPrefixBytes])
+	key = apiKeyScheme + "_" + prefix + "_" + base64.RawURLEncoding.EncodeToString(buf[apiKeyPrefixBytes:])
+	return key, prefix, HashAPIKey(key), nil
+}
+
+// ParseAPIKey 从 key 中解析出 prefix，key 的格式不正确时 ok 为 false
+func ParseAPIKey(key string) (prefix string, ok bool) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyScheme || len(parts[1]) != 2*apiKeyPrefixBytes || parts[2] == "" {
+		return "", false
+	}
+	if _, err := hex.DecodeString(parts[1]); err != nil {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// HashAPIKey 返回 API Key 的哈希值。API Key 是足够长的随机字符串，使用 SHA-256 即可，无需 bcrypt 等慢哈希
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// CompareAPIKey 使用常量时间比较 key 的哈希值是否等于 hash
+func CompareAPIKey(hash, key string) bool {
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(HashAPIKey(key))) == 1
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, hash, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(key, "mb_"+prefix+"_") {
+		t.Errorf("key %q does not contain prefix %q", key, prefix)
+	}
+	if got, ok := ParseAPIKey(key); !ok || got != prefix {
+		t.Errorf("ParseAPIKey(%q) = %q, %v, want %q", key, got, ok, prefix)
+	}
+	if !CompareAPIKey(hash, key) {
+		t.Error("CompareAPIKey returned false for the generated key")
+	}
+	if CompareAPIKey(hash, key+"x") {
+		t.Error("CompareAPIKey returned true for a different key")
+	}
+
+	other, otherPrefix, _, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == key || otherPrefix == prefix {
+		t.Error("GenerateAPIKey returned the same key twice")
+	}
+}
+
+func TestParseAPIKey(t *testing.T) {
+	for _, key := range []string{
+		"",
+		"mb_0123456789ab",
+		"mb_0123456789ab_",
+		"xx_0123456789ab_secret",
+		"mb_0123456789_secret",
+		"mb_0123456789xz_secret",
+	} {
+		if _, ok := ParseAPIKey(key); ok {
+			t.Errorf("ParseAPIKey(%q) should fail", key)
+		}
+	}
+
+	// secret 使用 base64url 编码，其中可能包含 `_`
+	for _, key := range []string{"mb_0123456789ab_secret", "mb_0123456789ab_se_cr_et"} {
+		if prefix, ok := ParseAPIKey(key); !ok || prefix != "0123456789ab" {
+			t.Errorf("ParseAPIKey(%q) = %q, %v", key, prefix, ok)
+		}
+	}
+}
@@ -1,14 +1,78 @@
 package auth
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"errors"
+	"sync"
+)
 
-// Encrypt 使用 bcrype 加密纯文本
-func Encrypt(source string) (string, error) {
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(source), bcrypt.DefaultCost)
-	return string(hashedBytes), err
+// ErrPasswordMismatch 表示密码与密文不匹配
+var ErrPasswordMismatch = errors.New("hashed password does not match the given password")
+
+// ErrUnknownHashFormat 表示无法识别密文的格式
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+// Hasher 使用固定的算法和参数加密密码，并校验所有支持格式的密文。使用 NewHasher 创建，可以被多个 goroutine 并发使用
+type Hasher struct {
+	h passwordHasher
+
+	once  sync.Once
+	dummy string // 用于 CompareDummy 的密文，使用与 h 相同的参数，第一次使用时生成
+}
+
+// defaultHasher 是使用默认参数的 Hasher，见 DefaultHasher
+var defaultHasher = &Hasher{h: mustNewHasher(DefaultHashOptions())}
+
+// NewHasher 根据 opts 创建 Hasher，参数非法时报错
+func NewHasher(opts *HashOptions) (*Hasher, error) {
+	h, err := newHasher(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Hasher{h: h}, nil
+}
+
+// DefaultHasher 返回使用 DefaultHashOptions 的 Hasher，多次调用返回同一个实例
+func DefaultHasher() *Hasher {
+	return defaultHasher
+}
+
+// Encrypt 使用 Hasher 的算法和参数加密纯文本，返回自描述格式的密文（包含算法和参数）
+func (h *Hasher) Encrypt(source string) (string, error) {
+	return h.h.hash(source)
+}
+
+// Compare 比较密文和明文是否相同，不匹配时返回 ErrPasswordMismatch。
+// 匹配时，如果密文使用的算法或参数与 Hasher 不同，rehash 为 true，调用方应使用 Encrypt 重新加密并保存
+func (h *Hasher) Compare(hashedPassword, password string) (rehash bool, err error) {
+	for _, v := range verifiers {
+		if !v.match(hashedPassword) {
+			continue
+		}
+		if err := v.verify(hashedPassword, password); err != nil {
+			return false, err
+		}
+		return h.h.needsRehash(hashedPassword), nil
+	}
+
+	return false, ErrUnknownHashFormat
+}
+
+// CompareDummy 使用与 Compare 相同的算法和参数做一次不会成功的比较，返回 ErrPasswordMismatch。
+// 用户不存在时调用该函数，使响应时间与密码错误时一致，避免通过响应时间枚举用户名
+func (h *Hasher) CompareDummy(password string) error {
+	h.once.Do(func() {
+		h.dummy, _ = h.h.hash("miniblog-dummy-password")
+	})
+	_, _ = h.Compare(h.dummy, password)
+
+	return ErrPasswordMismatch
 }
 
-// Compare 比较密文和明文是否相同
-func Compare(hashedPassword, password string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+// maxPasswordBytes 返回 Hasher 的算法支持的最大密码字节数，0 表示不限制，h 为 nil 时同样返回 0
+func (h *Hasher) maxPasswordBytes() int {
+	if h == nil {
+		return 0
+	}
+	return h.h.maxPasswordBytes()
 }
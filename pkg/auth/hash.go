@@ -0,0 +1,205 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+)
+
+// 支持的密码加密算法
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+// HashOptions 包含密码加密的配置项，可以通过 viper 从 `auth.password-hash` 中读取
+type HashOptions struct {
+	Algorithm  string          `mapstructure:"algorithm"`   // 加密算法，可选值：bcrypt,argon2id
+	BcryptCost int             `mapstructure:"bcrypt-cost"` // bcrypt 的 cost，取值范围 4~31
+	Argon2id   Argon2idOptions `mapstructure:"argon2id"`
+}
+
+// Argon2idOptions 包含 argon2id 算法的参数
+type Argon2idOptions struct {
+	Memory      uint32 `mapstructure:"memory"`      // 内存开销，单位 KiB
+	Iterations  uint32 `mapstructure:"iterations"`  // 迭代次数
+	Parallelism uint8  `mapstructure:"parallelism"` // 并行度
+	SaltLength  uint32 `mapstructure:"salt-length"` // 盐的字节数
+	KeyLength   uint32 `mapstructure:"key-length"`  // 生成的密钥字节数
+}
+
+// DefaultHashOptions 返回默认的加密参数：bcrypt，cost 为 bcrypt.DefaultCost；argon2id 参数为 RFC 9106 推荐的第二组参数
+func DefaultHashOptions() *HashOptions {
+	return &HashOptions{
+		Algorithm:  AlgorithmBcrypt,
+		BcryptCost: bcrypt.DefaultCost,
+		Argon2id: Argon2idOptions{
+			Memory:      64 * 1024,
+			Iterations:  3,
+			Parallelism: 4,
+			SaltLength:  16,
+			KeyLength:   32,
+		},
+	}
+}
+
+// passwordHasher 使用固定的算法和参数加密密码
+type passwordHasher interface {
+	// hash 加密 password，返回自描述格式的密文
+	hash(password string) (string, error)
+	// needsRehash 判断密文使用的算法或参数是否与当前 hasher 不同
+	needsRehash(hashed string) bool
+	// maxPasswordBytes 返回算法支持的最大密码字节数，0 表示不限制
+	maxPasswordBytes() int
+}
+
+// verifier 校验某种格式的密文，与当前配置的参数无关，使用旧参数加密的密文同样可以校验
+type verifier interface {
+	match(hashed string) bool
+	verify(hashed, password string) error
+}
+
+// verifiers 包含所有支持校验的密文格式
+var verifiers = []verifier{bcryptHasher{}, argon2idHasher{}}
+
+// newHasher 根据 opts 创建 passwordHasher
+func newHasher(opts *HashOptions) (passwordHasher, error) {
+	switch opts.Algorithm {
+	case AlgorithmBcrypt, "":
+		if opts.BcryptCost < bcrypt.MinCost || opts.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("invalid bcrypt cost %d, must be between %d and %d", opts.BcryptCost, bcrypt.MinCost, bcrypt.MaxCost)
+		}
+		return bcryptHasher{cost: opts.BcryptCost}, nil
+	case AlgorithmArgon2id:
+		p := opts.Argon2id
+		if p.Memory < 8*uint32(p.Parallelism) || p.Iterations < 1 || p.Parallelism < 1 || p.SaltLength < 8 || p.KeyLength < 16 {
+			return nil, fmt.Errorf("invalid argon2id parameters %+v", p)
+		}
+		return argon2idHasher{params: p}, nil
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm %q", opts.Algorithm)
+	}
+}
+
+// mustNewHasher 与 newHasher 相同，参数非法时 panic，用于初始化默认值
+func mustNewHasher(opts *HashOptions) passwordHasher {
+	h, err := newHasher(opts)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+// bcryptHasher 使用 bcrypt 加密，密文格式为 `$2a$<cost>$<salt+hash>`
+type bcryptHasher struct {
+	cost int
+}
+
+func (h bcryptHasher) hash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	return string(hashed), err
+}
+
+func (h bcryptHasher) needsRehash(hashed string) bool {
+	cost, err := bcrypt.Cost([]byte(hashed))
+	return err != nil || cost != h.cost
+}
+
+func (h bcryptHasher) maxPasswordBytes() int {
+	return 72
+}
+
+func (bcryptHasher) match(hashed string) bool {
+	return strings.HasPrefix(hashed, "$2a$") || strings.HasPrefix(hashed, "$2b$") || strings.HasPrefix(hashed, "$2y$")
+}
+
+func (bcryptHasher) verify(hashed, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hashed), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrPasswordMismatch
+	}
+	return err
+}
+
+// argon2idPrefix 是 argon2id 密文的前缀
+const argon2idPrefix = "$argon2id$"
+
+// argon2idHasher 使用 argon2id 加密，密文为 PHC 字符串格式：`$argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>`，
+// salt 和 hash 使用不带填充的标准 base64 编码
+type argon2idHasher struct {
+	params Argon2idOptions
+}
+
+func (h argon2idHasher) hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version,
+		h.params.Memory, h.params.Iterations, h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h argon2idHasher) needsRehash(hashed string) bool {
+	params, salt, key, err := decodeArgon2id(hashed)
+	if err != nil {
+		return true
+	}
+	return params.Memory != h.params.Memory || params.Iterations != h.params.Iterations ||
+		params.Parallelism != h.params.Parallelism || uint32(len(salt)) != h.params.SaltLength ||
+		uint32(len(key)) != h.params.KeyLength
+}
+
+func (h argon2idHasher) maxPasswordBytes() int {
+	return 0
+}
+
+func (argon2idHasher) match(hashed string) bool {
+	return strings.HasPrefix(hashed, argon2idPrefix)
+}
+
+func (argon2idHasher) verify(hashed, password string) error {
+	params, salt, key, err := decodeArgon2id(hashed)
+	if err != nil {
+		return err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+// decodeArgon2id 解析 argon2id 密文中的参数、盐和哈希值
+func decodeArgon2id(hashed string) (params Argon2idOptions, salt, key []byte, err error) {
+	parts := strings.Split(hashed, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version %q", parts[2])
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	params.SaltLength, params.KeyLength = uint32(len(salt)), uint32(len(key))
+	return params, salt, key, nil
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fastArgon2id 是测试使用的 argon2id 参数，减少内存和耗时
+var fastArgon2id = Argon2idOptions{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+// mustHasher 使用 opts 创建 Hasher，参数非法时测试失败
+func mustHasher(t *testing.T, opts *HashOptions) *Hasher {
+	t.Helper()
+
+	h, err := NewHasher(opts)
+	if err != nil {
+		t.Fatalf("failed to create hasher: %v", err)
+	}
+	return h
+}
+
+func TestEncryptAndCompare(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   *HashOptions
+		prefix string
+	}{
+		{"bcrypt", &HashOptions{Algorithm: AlgorithmBcrypt, BcryptCost: 4}, "$2a$04$"},
+		{"argon2id", &HashOptions{Algorithm: AlgorithmArgon2id, Argon2id: fastArgon2id}, "$argon2id$v=19$m=64,t=1,p=1$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := mustHasher(t, tt.opts)
+
+			hashed, err := h.Encrypt("correct horse battery staple")
+			if err != nil {
+				t.Fatalf("failed to encrypt: %v", err)
+			}
+			if !strings.HasPrefix(hashed, tt.prefix) {
+				t.Fatalf("hash %q is not self-describing, want prefix %q", hashed, tt.prefix)
+			}
+
+			rehash, err := h.Compare(hashed, "correct horse battery staple")
+			if err != nil || rehash {
+				t.Fatalf("Compare() = %v, %v, want false, nil", rehash, err)
+			}
+			if _, err := h.Compare(hashed, "wrong password"); !errors.Is(err, ErrPasswordMismatch) {
+				t.Fatalf("unexpected error for wrong password: %v", err)
+			}
+		})
+	}
+}
+
+func TestCompareReportsOutdatedHashes(t *testing.T) {
+	bcrypt4, _ := mustHasher(t, &HashOptions{Algorithm: AlgorithmBcrypt, BcryptCost: 4}).Encrypt("miniblog1234")
+	argon2, _ := mustHasher(t, &HashOptions{Algorithm: AlgorithmArgon2id, Argon2id: fastArgon2id}).Encrypt("miniblog1234")
+
+	tests := []struct {
+		name   string
+		opts   *HashOptions
+		hashed string
+		want   bool
+	}{
+		{"same bcrypt cost", &HashOptions{Algorithm: AlgorithmBcrypt, BcryptCost: 4}, bcrypt4, false},
+		{"raised bcrypt cost", &HashOptions{Algorithm: AlgorithmBcrypt, BcryptCost: 5}, bcrypt4, true},
+		{"bcrypt to argon2id", &HashOptions{Algorithm: AlgorithmArgon2id, Argon2id: fastArgon2id}, bcrypt4, true},
+		{"same argon2id parameters", &HashOptions{Algorithm: AlgorithmArgon2id, Argon2id: fastArgon2id}, argon2, false},
+		{"raised argon2id memory", &HashOptions{Algorithm: AlgorithmArgon2id, Argon2id: Argon2idOptions{Memory: 128, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}}, argon2, true},
+		{"argon2id to bcrypt", &HashOptions{Algorithm: AlgorithmBcrypt, BcryptCost: 4}, argon2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rehash, err := mustHasher(t, tt.opts).Compare(tt.hashed, "miniblog1234")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rehash != tt.want {
+				t.Fatalf("rehash = %v, want %v", rehash, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareUnknownFormat(t *testing.T) {
+	if _, err := DefaultHasher().Compare("plaintext", "plaintext"); !errors.Is(err, ErrUnknownHashFormat) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNewHasherRejectsInvalidOptions(t *testing.T) {
+	for name, opts := range map[string]*HashOptions{
+		"unknown algorithm": {Algorithm: "md5"},
+		"bcrypt cost":       {Algorithm: AlgorithmBcrypt, BcryptCost: 99},
+		"argon2id key":      {Algorithm: AlgorithmArgon2id, Argon2id: Argon2idOptions{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewHasher(opts); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
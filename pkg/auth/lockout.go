@@ -0,0 +1,49 @@
+package auth
+
+import "time"
+
+// LockoutPolicy 定义了登录失败后的限制策略，可以通过 viper 从 `auth.lockout` 中读取：
+// 第 n 次连续失败后，需要等待 Delay*2^(n-1)（最多 MaxDelay）才能再次尝试；连续失败 Threshold 次后锁定 Duration
+type LockoutPolicy struct {
+	Enabled   bool          `mapstructure:"enabled"`
+	Threshold int           `mapstructure:"threshold"` // 连续失败多少次后锁定账户
+	Duration  time.Duration `mapstructure:"duration"`  // 账户锁定时长
+	Delay     time.Duration `mapstructure:"delay"`     // 第一次失败后的等待时间，之后每次失败翻倍，0 表示不限制
+	MaxDelay  time.Duration `mapstructure:"max-delay"` // 两次尝试之间的最长等待时间
+}
+
+// DefaultLockoutPolicy 返回默认的登录限制策略：连续失败 5 次后锁定 15 分钟，失败后等待 1s、2s、4s、8s
+func DefaultLockoutPolicy() *LockoutPolicy {
+	return &LockoutPolicy{
+		Enabled:   true,
+		Threshold: 5,
+		Duration:  15 * time.Minute,
+		Delay:     time.Second,
+		MaxDelay:  30 * time.Second,
+	}
+}
+
+// LockedUntil 返回连续失败 attempts 次后，账户在什么时间之前禁止登录，locked 表示是否达到了锁定阈值。
+// 未启用或不需要等待时返回零值
+func (p *LockoutPolicy) LockedUntil(attempts int, now time.Time) (until time.Time, locked bool) {
+	if !p.Enabled || attempts <= 0 {
+		return time.Time{}, false
+	}
+
+	if p.Threshold > 0 && attempts >= p.Threshold {
+		return now.Add(p.Duration), true
+	}
+
+	if p.Delay <= 0 {
+		return time.Time{}, false
+	}
+	delay := p.Delay
+	for i := 1; i < attempts && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	return now.Add(delay), false
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockoutPolicy_LockedUntil(t *testing.T) {
+	now := time.Now()
+	policy := &LockoutPolicy{Enabled: true, Threshold: 5, Duration: 15 * time.Minute, Delay: time.Second, MaxDelay: 5 * time.Second}
+
+	tests := []struct {
+		attempts   int
+		wantWait   time.Duration
+		wantLocked bool
+	}{
+		{0, 0, false},
+		{1, time.Second, false},
+		{2, 2 * time.Second, false},
+		{3, 4 * time.Second, false},
+		{4, 5 * time.Second, false},
+		{5, 15 * time.Minute, true},
+		{8, 15 * time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		until, locked := policy.LockedUntil(tt.attempts, now)
+		var wait time.Duration
+		if !until.IsZero() {
+			wait = until.Sub(now)
+		}
+		if wait != tt.wantWait || locked != tt.wantLocked {
+			t.Errorf("LockedUntil(%d) = %v, %v, want %v, %v", tt.attempts, wait, locked, tt.wantWait, tt.wantLocked)
+		}
+	}
+
+	disabled := *policy
+	disabled.Enabled = false
+	if until, locked := disabled.LockedUntil(10, now); !until.IsZero() || locked {
+		t.Errorf("disabled policy should never lock, got %v, %v", until, locked)
+	}
+}
+
+func TestCompareDummy(t *testing.T) {
+	h := mustHasher(t, &HashOptions{Algorithm: AlgorithmBcrypt, BcryptCost: 4})
+
+	if err := h.CompareDummy("miniblog-dummy-password"); err != ErrPasswordMismatch {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
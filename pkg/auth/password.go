@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 密码策略的校验规则，PolicyError.Rule 的取值
+const (
+	RuleMinLength = "min-length"
+	RuleMaxLength = "max-length"
+	RuleUpper     = "upper"
+	RuleLower     = "lower"
+	RuleDigit     = "digit"
+	RuleSymbol    = "symbol"
+	RuleUserInfo  = "user-info"
+	RuleBreached  = "breached"
+)
+
+// minUserInfoLength 用户名、邮箱等信息短于该长度时不检查是否出现在密码中，避免误判
+const minUserInfoLength = 3
+
+// breachedList 是离线的常见泄露密码列表，每行一个，均为小写
+//
+//go:embed breached.txt
+var breachedList []byte
+
+// breached 是 breachedList 解析后的集合
+var breached = func() map[string]struct{} {
+	set := make(map[string]struct{})
+	scanner := bufio.NewScanner(bytes.NewReader(breachedList))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			set[line] = struct{}{}
+		}
+	}
+	return set
+}()
+
+// PasswordPolicy 定义了密码策略，可以通过 viper 从 `auth.password-policy` 中读取
+type PasswordPolicy struct {
+	MinLength        int  `mapstructure:"min-length"`         // 最小长度（字符数）
+	MaxLength        int  `mapstructure:"max-length"`         // 最大长度（字符数），使用 bcrypt 时同时不能超过 72 个字节
+	RequireUpper     bool `mapstructure:"require-upper"`      // 是否必须包含大写字母
+	RequireLower     bool `mapstructure:"require-lower"`      // 是否必须包含小写字母
+	RequireDigit     bool `mapstructure:"require-digit"`      // 是否必须包含数字
+	RequireSymbol    bool `mapstructure:"require-symbol"`     // 是否必须包含特殊字符
+	DisallowUserInfo bool `mapstructure:"disallow-user-info"` // 是否禁止密码中包含用户名、邮箱
+	CheckBreached    bool `mapstructure:"check-breached"`     // 是否禁止使用常见的泄露密码
+}
+
+// DefaultPasswordPolicy 返回默认的密码策略：8 到 64 个字符，不强制字符类型，禁止包含用户信息和使用泄露密码
+func DefaultPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength:        8,
+		MaxLength:        64,
+		DisallowUserInfo: true,
+		CheckBreached:    true,
+	}
+}
+
+// PolicyError 表示密码不满足密码策略，Rule 为未通过的规则
+type PolicyError struct {
+	Rule    string
+	Message string
+}
+
+// Error 实现了 error 接口
+func (e *PolicyError) Error() string {
+	return e.Message
+}
+
+// Validate 校验 password 是否满足密码策略，userInfo 为不允许出现在密码中的用户名、邮箱等信息（不区分大小写）。
+// h 为加密密码使用的 Hasher，用于检查密码是否超出算法支持的字节数，为 nil 时不检查。不满足时返回 *PolicyError
+func (p *PasswordPolicy) Validate(h *Hasher, password string, userInfo ...string) error {
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		return &PolicyError{Rule: RuleMinLength, Message: fmt.Sprintf("must be at least %d characters long", p.MinLength)}
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		return &PolicyError{Rule: RuleMaxLength, Message: fmt.Sprintf("must be at most %d characters long", p.MaxLength)}
+	}
+	// bcrypt 最多支持 72 个字节的密码，多字节字符组成的密码可能在字符数达到 MaxLength 之前就超出限制
+	if limit := h.maxPasswordBytes(); limit > 0 && len(password) > limit {
+		return &PolicyError{Rule: RuleMaxLength, Message: fmt.Sprintf("must be at most %d bytes long", limit)}
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	for _, class := range []struct {
+		required, present bool
+		rule, name        string
+	}{
+		{p.RequireUpper, upper, RuleUpper, "an uppercase letter"},
+		{p.RequireLower, lower, RuleLower, "a lowercase letter"},
+		{p.RequireDigit, digit, RuleDigit, "a digit"},
+		{p.RequireSymbol, symbol, RuleSymbol, "a symbol"},
+	} {
+		if class.required && !class.present {
+			return &PolicyError{Rule: class.rule, Message: "must contain at least " + class.name}
+		}
+	}
+
+	lowered := strings.ToLower(password)
+	if p.DisallowUserInfo {
+		for _, info := range expandUserInfo(userInfo) {
+			if strings.Contains(lowered, info) {
+				return &PolicyError{Rule: RuleUserInfo, Message: "must not contain your username or email"}
+			}
+		}
+	}
+
+	if p.CheckBreached {
+		if _, ok := breached[lowered]; ok {
+			return &PolicyError{Rule: RuleBreached, Message: "is too common and has appeared in data breaches"}
+		}
+	}
+
+	return nil
+}
+
+// expandUserInfo 将用户信息转为小写，邮箱额外拆分出 `@` 前的部分，并去掉过短的信息
+func expandUserInfo(userInfo []string) []string {
+	var infos []string
+	for _, info := range userInfo {
+		info = strings.ToLower(info)
+		if local, _, ok := strings.Cut(info, "@"); ok {
+			infos = append(infos, local)
+		}
+		infos = append(infos, info)
+	}
+
+	result := infos[:0]
+	for _, info := range infos {
+		if utf8.RuneCountInString(info) >= minUserInfoLength {
+			result = append(result, info)
+		}
+	}
+	return result
+}
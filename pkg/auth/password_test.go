@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	strict := &PasswordPolicy{
+		MinLength:        8,
+		MaxLength:        20,
+		RequireUpper:     true,
+		RequireLower:     true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+		DisallowUserInfo: true,
+		CheckBreached:    true,
+	}
+
+	tests := []struct {
+		name     string
+		policy   *PasswordPolicy
+		password string
+		wantRule string
+	}{
+		{"default ok", DefaultPasswordPolicy(), "miniblog1234", ""},
+		{"passphrase", DefaultPasswordPolicy(), "correct horse battery staple", ""},
+		{"multibyte characters count as one", DefaultPasswordPolicy(), "密码密码密码密码", ""},
+		{"too short", DefaultPasswordPolicy(), "short", RuleMinLength},
+		{"too long", DefaultPasswordPolicy(), strings.Repeat("x", 65), RuleMaxLength},
+		{"exceeds bcrypt bytes", DefaultPasswordPolicy(), strings.Repeat("密", 25), RuleMaxLength},
+		{"breached", DefaultPasswordPolicy(), "Password1", RuleBreached},
+		{"username", DefaultPasswordPolicy(), "xxALICExx", RuleUserInfo},
+		{"email local part", DefaultPasswordPolicy(), "wonderland1", RuleUserInfo},
+		{"missing upper", strict, "abcdef1!", RuleUpper},
+		{"missing lower", strict, "ABCDEF1!", RuleLower},
+		{"missing digit", strict, "Abcdefg!", RuleDigit},
+		{"missing symbol", strict, "Abcdefg1", RuleSymbol},
+		{"strict ok", strict, "Abcdefg1!", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(DefaultHasher(), tt.password, "alice", "wonderland@example.com")
+			if tt.wantRule == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var policyErr *PolicyError
+			if !errors.As(err, &policyErr) || policyErr.Rule != tt.wantRule {
+				t.Fatalf("want rule %q, got %v", tt.wantRule, err)
+			}
+		})
+	}
+}
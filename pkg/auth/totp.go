@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/hotp"
+	"github.com/pquerna/otp/totp"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// TOTP 使用的参数，与 Google Authenticator 等主流应用的默认值一致
+const (
+	totpPeriod = 30 // 验证码有效期，单位秒
+	totpSkew   = 1  // 允许前后各偏差 1 个周期，容忍客户端时钟误差
+	totpDigits = otp.DigitsSix
+)
+
+// 恢复码由 recoveryCodeLength 个 recoveryCodeAlphabet 中的字符组成，展示时每 5 个字符用 `-` 分隔
+const (
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+	recoveryCodeLength   = 10
+)
+
+// TwoFactorPolicy 定义了两步验证的配置，可以通过 viper 从 `auth.two-factor` 中读取
+type TwoFactorPolicy struct {
+	Issuer           string        `mapstructure:"issuer"`             // 验证器应用中显示的发行方名称
+	ChallengeExpire  time.Duration `mapstructure:"challenge-expire"`   // 密码校验通过后，提交验证码的有效期
+	RequireForAdmins bool          `mapstructure:"require-for-admins"` // 管理员是否必须开启两步验证才能执行管理操作
+	RecoveryCodes    int           `mapstructure:"recovery-codes"`     // 开启两步验证时生成的恢复码个数
+}
+
+// DefaultTwoFactorPolicy 返回默认的两步验证配置：challenge 有效期 5 分钟，管理员必须开启，生成 10 个恢复码
+func DefaultTwoFactorPolicy() *TwoFactorPolicy {
+	return &TwoFactorPolicy{
+		Issuer:           "miniblog",
+		ChallengeExpire:  5 * time.Minute,
+		RequireForAdmins: true,
+		RecoveryCodes:    10,
+	}
+}
+
+// IsTOTPCode 判断 code 是否为 TOTP 验证码的格式（6 位数字），用于区分验证码和恢复码
+func IsTOTPCode(code string) bool {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits.Length() {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// GenerateTOTP 为 account 生成一个新的 TOTP 密钥，返回 base32 编码的密钥和可以导入验证器应用（或生成二维码）的 `otpauth://` URI
+func GenerateTOTP(issuer, account string) (secret, uri string, err error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      issuer,
+		AccountName: account,
+		Period:      totpPeriod,
+		Digits:      totpDigits,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return key.Secret(), key.URL(), nil
+}
+
+// ValidateTOTP 校验 code 是否为 secret 在 now 时刻（前后各允许 1 个周期的偏差）的验证码。
+// 校验成功时返回验证码对应的计数器，调用方需要保存该计数器，并拒绝计数器不大于 lastCounter 的验证码，避免同一个验证码被重复使用
+func ValidateTOTP(code, secret string, now time.Time, lastCounter int64) (counter int64, ok bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits.Length() {
+		return 0, false
+	}
+
+	current := now.Unix() / totpPeriod
+	for c := current - totpSkew; c <= current+totpSkew; c++ {
+		if c <= lastCounter {
+			continue
+		}
+
+		expected, err := hotp.GenerateCodeCustom(secret, uint64(c), hotp.ValidateOpts{Digits: totpDigits, Algorithm: otp.AlgorithmSHA1})
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return c, true
+		}
+	}
+
+	return 0, false
+}
+
+// GenerateRecoveryCodes 生成 n 个一次性恢复码，返回展示给用户的恢复码和用于保存的哈希值
+func GenerateRecoveryCodes(n int) (codes, hashes []string, err error) {
+	for i := 0; i < n; i++ {
+		buf := make([]byte, recoveryCodeLength)
+		for j := range buf {
+			k, err := rand.Int(rand.Reader, big.NewInt(int64(len(recoveryCodeAlphabet))))
+			if err != nil {
+				return nil, nil, err
+			}
+			buf[j] = recoveryCodeAlphabet[k.Int64()]
+		}
+
+		code := string(buf[:5]) + "-" + string(buf[5:])
+		codes = append(codes, code)
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode 返回恢复码的哈希值，忽略大小写、空格和 `-`。
+// 恢复码是足够长的随机字符串，使用 SHA-256 即可，无需 bcrypt 等慢哈希
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
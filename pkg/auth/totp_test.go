@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/hotp"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateTOTP(t *testing.T) {
+	secret, uri, err := GenerateTOTP("miniblog", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Query().Get("secret") != secret || u.Query().Get("issuer") != "miniblog" {
+		t.Errorf("unexpected uri: %s", uri)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret, _, err := GenerateTOTP("miniblog", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	current := now.Unix() / totpPeriod
+	code := func(counter int64) string {
+		c, err := hotp.GenerateCodeCustom(secret, uint64(counter), hotp.ValidateOpts{Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name        string
+		code        string
+		lastCounter int64
+		wantCounter int64
+		wantOK      bool
+	}{
+		{name: "current", code: code(current), wantCounter: current, wantOK: true},
+		{name: "previous period", code: code(current - 1), wantCounter: current - 1, wantOK: true},
+		{name: "next period", code: code(current + 1), wantCounter: current + 1, wantOK: true},
+		{name: "too old", code: code(current - 2)},
+		{name: "already used", code: code(current), lastCounter: current},
+		{name: "newer than used", code: code(current + 1), lastCounter: current, wantCounter: current + 1, wantOK: true},
+		{name: "wrong length", code: "12345"},
+	}
+
+	for _, tt := range tests {
+		counter, ok := ValidateTOTP(tt.code, secret, now, tt.lastCounter)
+		if ok != tt.wantOK || counter != tt.wantCounter {
+			t.Errorf("%s: ValidateTOTP() = %d, %v, want %d, %v", tt.name, counter, ok, tt.wantCounter, tt.wantOK)
+		}
+	}
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, hashes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != 10 || len(hashes) != 10 {
+		t.Fatalf("unexpected number of codes: %d, %d", len(codes), len(hashes))
+	}
+
+	seen := map[string]bool{}
+	for i, code := range codes {
+		if len(code) != recoveryCodeLength+1 || code[5] != '-' {
+			t.Errorf("unexpected recovery code format: %q", code)
+		}
+		if seen[code] {
+			t.Errorf("duplicated recovery code: %q", code)
+		}
+		seen[code] = true
+
+		if IsTOTPCode(code) {
+			t.Errorf("recovery code %q should not be recognized as a totp code", code)
+		}
+		// 哈希值忽略大小写、空格和 `-`
+		if HashRecoveryCode(strings.ToUpper(strings.ReplaceAll(code, "-", " "))) != hashes[i] {
+			t.Errorf("hash of %q does not match", code)
+		}
+	}
+}
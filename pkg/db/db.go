@@ -1,6 +1,9 @@
 package db
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -8,6 +11,12 @@ import (
 	"time"
 )
 
+// 支持的数据库类型
+const (
+	TypeMySQL    = "mysql"
+	TypePostgres = "postgres"
+)
+
 type MySqlOptions struct {
 	Host                  string
 	Username              string
@@ -17,39 +26,165 @@ type MySqlOptions struct {
 	MaxOpenConnections    int           // 数据库的最大打开连接数
 	MaxConnectionLifeTime time.Duration // 连接可重用的最长时间
 	LogLevel              int
+
+	Replicas             []string      // 只读副本的地址，为空时所有读写都访问主库。副本使用与主库相同的用户名、密码和数据库名
+	MaxReplicaLag        time.Duration // 副本允许的最大复制延迟，超过后不再将读请求路由到该副本，0 表示不检查复制延迟
+	ReplicaCheckInterval time.Duration // 副本健康检查的间隔，默认 5s
 }
 
 // DSN (Data Source Name) 返回 DSN
 func (o *MySqlOptions) DSN() string {
+	return o.dsn(o.Host)
+}
+
+// String 实现 fmt.Stringer 接口，输出时隐藏密码，避免通过日志或错误信息泄露
+func (o MySqlOptions) String() string {
+	type plain MySqlOptions
+	o.Password = redact(o.Password)
+	return fmt.Sprintf("%+v", plain(o))
+}
+
+// GoString 实现 fmt.GoStringer 接口，使 `%#v` 同样隐藏密码
+func (o MySqlOptions) GoString() string {
+	return "db.MySqlOptions" + o.String()
+}
+
+// dsn 返回连接 host 使用的 DSN
+func (o *MySqlOptions) dsn(host string) string {
 	return fmt.Sprintf(`%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=%t&loc=%s`,
 		o.Username,
 		o.Password,
-		o.Host,
+		host,
 		o.Database,
 		true,
 		"Local")
 }
 
-func NewMySql(opts *MySqlOptions) (*gorm.DB, error) {
+// NewMySql 根据 opts 创建 MySQL 的 *gorm.DB 实例，ctx 用来控制建立连接的超时时间
+func NewMySql(ctx context.Context, opts *MySqlOptions) (*gorm.DB, error) {
+	dialector := func(conn *sql.DB) gorm.Dialector {
+		return mysql.New(mysql.Config{DSN: opts.DSN(), Conn: conn})
+	}
+	return open(ctx, "mysql", opts.DSN(), dialector, opts.LogLevel, poolOptions{
+		maxIdle:     opts.MaxIdleConnections,
+		maxOpen:     opts.MaxOpenConnections,
+		maxLifeTime: opts.MaxConnectionLifeTime,
+	}, &replicaOptions{
+		hosts:    opts.Replicas,
+		dsn:      opts.dsn,
+		lag:      mysqlLag,
+		maxLag:   opts.MaxReplicaLag,
+		interval: opts.ReplicaCheckInterval,
+	})
+}
+
+// redactedPassword 是 Options 输出时替换密码的值
+const redactedPassword = "******"
+
+// redact 隐藏非空的密码
+func redact(password string) string {
+	if password == "" {
+		return ""
+	}
+	return redactedPassword
+}
+
+// poolOptions 包含连接池相关的配置项
+type poolOptions struct {
+	maxIdle     int
+	maxOpen     int
+	maxLifeTime time.Duration
+}
+
+// apply 设置 sqlDb 的连接池参数
+func (p poolOptions) apply(sqlDb *sql.DB) {
+	sqlDb.SetMaxOpenConns(p.maxOpen)
+	sqlDb.SetConnMaxLifetime(p.maxLifeTime)
+	sqlDb.SetMaxIdleConns(p.maxIdle)
+}
+
+// open 使用 database/sql 中注册的驱动 driver 连接主库，设置连接池，并在配置了只读副本时注册读写分离插件。
+// gorm.Open 内部的 Ping 和查询数据库版本都不受 ctx 控制，所以先用 ctx 建立好连接，再交给 dialector 使用
+func open(ctx context.Context, driver, dsn string, dialector func(conn *sql.DB) gorm.Dialector, level int, pool poolOptions, replicas *replicaOptions) (*gorm.DB, error) {
 	// GORM log level, 1: silent, 2:error, 3:warn, 4:info
 	logLevel := logger.Silent
-	if opts.LogLevel != 0 {
-		logLevel = logger.LogLevel(opts.LogLevel)
+	if level != 0 {
+		logLevel = logger.LogLevel(level)
 	}
 	// 🍑根据自定义的日志等级初始化 database session 的过程还挺曲折的～
-	db, err := gorm.Open(mysql.Open(opts.DSN()), &gorm.Config{Logger: logger.Default.LogMode(logLevel)})
+	// TranslateError 会将数据库相关的错误转换为 gorm 定义的通用错误，例如唯一键冲突会被转换为 gorm.ErrDuplicatedKey
+	sqlDb, err := sql.Open(driver, dsn)
 	if err != nil {
 		return nil, err
 	}
+	if err := sqlDb.PingContext(ctx); err != nil {
+		_ = sqlDb.Close()
+		return nil, err
+	}
 
-	sqlDb, err := db.DB()
+	db, err := gorm.Open(dialector(sqlDb), &gorm.Config{Logger: logger.Default.LogMode(logLevel), TranslateError: true, DisableAutomaticPing: true})
 	if err != nil {
+		_ = sqlDb.Close()
 		return nil, err
 	}
 
-	sqlDb.SetMaxOpenConns(opts.MaxOpenConnections)
-	sqlDb.SetConnMaxLifetime(opts.MaxConnectionLifeTime)
-	sqlDb.SetMaxIdleConns(opts.MaxIdleConnections)
+	// 连接池参数在 gorm.Open 之后设置，保证 gorm.Open 复用上面建立的连接（MaxIdleConnections 可能为 0）
+	pool.apply(sqlDb)
+
+	if len(replicas.hosts) > 0 {
+		if err := useReplicas(db, driver, pool, replicas); err != nil {
+			_ = sqlDb.Close()
+			return nil, err
+		}
+	}
 
 	return db, nil
 }
+
+// replicaOptions 包含只读副本相关的配置项
+type replicaOptions struct {
+	hosts    []string                 // 副本地址
+	dsn      func(host string) string // 根据副本地址生成 DSN
+	lag      LagFunc
+	maxLag   time.Duration
+	interval time.Duration
+}
+
+// useReplicas 连接所有只读副本，并注册读写分离插件
+func useReplicas(db *gorm.DB, driver string, pool poolOptions, opts *replicaOptions) error {
+	r := &resolver{
+		maxLag:   opts.maxLag,
+		interval: opts.interval,
+		lag:      opts.lag,
+		stop:     make(chan struct{}),
+	}
+	if r.interval <= 0 {
+		r.interval = 5 * time.Second
+	}
+
+	for _, host := range opts.hosts {
+		// sql.Open 只校验参数，不会建立连接，副本是否可用由健康检查决定
+		replicaDb, err := sql.Open(driver, opts.dsn(host))
+		if err != nil {
+			return err
+		}
+		pool.apply(replicaDb)
+		r.replicas = append(r.replicas, &replica{host: host, db: replicaDb})
+	}
+
+	return db.Use(r)
+}
+
+// Close 关闭数据库连接。如果配置了只读副本，会同时停止副本的健康检查并关闭副本的连接
+func Close(db *gorm.DB) error {
+	var errs []error
+	if plugin, ok := db.Config.Plugins[resolverName]; ok {
+		errs = append(errs, plugin.(*resolver).close())
+	}
+
+	sqlDb, err := db.DB()
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	return errors.Join(append(errs, sqlDb.Close())...)
+}
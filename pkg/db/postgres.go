@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"net/url"
+	"time"
+)
+
+// PostgresOptions 包含连接 PostgreSQL 的配置项，各字段的含义与 MySqlOptions 相同
+type PostgresOptions struct {
+	Host                  string // 机器 IP 和端口，例如 127.0.0.1:5432
+	Username              string
+	Password              string
+	Database              string
+	SSLMode               string // 可选值：disable,require,verify-ca,verify-full，默认 disable
+	MaxIdleConnections    int
+	MaxOpenConnections    int
+	MaxConnectionLifeTime time.Duration
+	LogLevel              int
+
+	Replicas             []string
+	MaxReplicaLag        time.Duration
+	ReplicaCheckInterval time.Duration
+}
+
+// DSN (Data Source Name) 返回 URL 格式的 DSN，用户名和密码中的特殊字符会被转义
+func (o *PostgresOptions) DSN() string {
+	return o.dsn(o.Host)
+}
+
+// String 实现 fmt.Stringer 接口，输出时隐藏密码
+func (o PostgresOptions) String() string {
+	type plain PostgresOptions
+	o.Password = redact(o.Password)
+	return fmt.Sprintf("%+v", plain(o))
+}
+
+// GoString 实现 fmt.GoStringer 接口，使 `%#v` 同样隐藏密码
+func (o PostgresOptions) GoString() string {
+	return "db.PostgresOptions" + o.String()
+}
+
+// dsn 返回连接 host 使用的 DSN
+func (o *PostgresOptions) dsn(host string) string {
+	sslMode := o.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(o.Username, o.Password),
+		Host:     host,
+		Path:     "/" + o.Database,
+		RawQuery: url.Values{"sslmode": []string{sslMode}}.Encode(),
+	}
+	return u.String()
+}
+
+// NewPostgres 根据 opts 创建 PostgreSQL 的 *gorm.DB 实例，ctx 用来控制建立连接的超时时间
+func NewPostgres(ctx context.Context, opts *PostgresOptions) (*gorm.DB, error) {
+	dialector := func(conn *sql.DB) gorm.Dialector {
+		return postgres.New(postgres.Config{DSN: opts.DSN(), Conn: conn})
+	}
+	return open(ctx, "pgx", opts.DSN(), dialector, opts.LogLevel, poolOptions{
+		maxIdle:     opts.MaxIdleConnections,
+		maxOpen:     opts.MaxOpenConnections,
+		maxLifeTime: opts.MaxConnectionLifeTime,
+	}, &replicaOptions{
+		hosts:    opts.Replicas,
+		dsn:      opts.dsn,
+		lag:      postgresLag,
+		maxLag:   opts.MaxReplicaLag,
+		interval: opts.ReplicaCheckInterval,
+	})
+}
+
+// postgresLag 根据最后一次回放事务的时间计算复制延迟，非备库（pg_is_in_recovery() 为 false）视为没有延迟
+func postgresLag(ctx context.Context, db *sql.DB) (time.Duration, bool, error) {
+	var seconds sql.NullFloat64
+	err := db.QueryRowContext(ctx, `SELECT CASE WHEN pg_is_in_recovery()
+		THEN EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())
+		ELSE 0 END`).Scan(&seconds)
+	if err != nil {
+		return 0, false, err
+	}
+	// NULL 表示备库还没有回放过任何事务
+	if !seconds.Valid {
+		return 0, false, nil
+	}
+	return time.Duration(seconds.Float64 * float64(time.Second)), true, nil
+}
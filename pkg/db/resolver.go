@@ -0,0 +1,259 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// resolverName 是读写分离插件及其回调函数的名字
+const resolverName = "miniblog:resolver"
+
+// LagFunc 用来查询只读副本相对主库的复制延迟。ok 为 false 表示复制已经中断（或者无法得知延迟），该副本不可用
+type LagFunc func(ctx context.Context, db *sql.DB) (lag time.Duration, ok bool, err error)
+
+// replica 表示一个只读副本
+type replica struct {
+	host    string
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// resolver 是一个实现读写分离的 gorm 插件：
+//   - 事务外的读操作（Query、Row）会轮询路由到健康的只读副本，没有健康的副本时回退到主库；
+//   - 写操作、事务以及 Raw/Exec 始终访问主库；
+//   - 开启了 read-your-writes 的 ctx（WithReadYourWrites）在写过数据后，后续的读操作也会访问主库。
+type resolver struct {
+	replicas []*replica
+	next     atomic.Uint64
+	maxLag   time.Duration
+	interval time.Duration
+	lag      LagFunc
+	logger   *gorm.DB
+	stop     chan struct{}
+}
+
+// 确保 resolver 实现了 gorm.Plugin 接口
+var _ gorm.Plugin = (*resolver)(nil)
+
+// Name 实现了 gorm.Plugin 接口中的 Name 方法
+func (r *resolver) Name() string {
+	return resolverName
+}
+
+// Initialize 实现了 gorm.Plugin 接口中的 Initialize 方法，注册读写分离相关的回调函数
+func (r *resolver) Initialize(db *gorm.DB) error {
+	r.logger = db
+
+	if err := db.Callback().Query().Before("gorm:query").Register(resolverName, r.switchReplica); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register(resolverName, r.switchReplica); err != nil {
+		return err
+	}
+	if err := db.Callback().Create().After("gorm:create").Register(resolverName, markWritten); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:update").Register(resolverName, markWritten); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().After("gorm:delete").Register(resolverName, markWritten); err != nil {
+		return err
+	}
+	if err := db.Callback().Raw().After("gorm:raw").Register(resolverName, markWritten); err != nil {
+		return err
+	}
+
+	// 先同步检查一次，保证启动后立即可以使用健康的副本
+	r.check()
+	go r.watch()
+
+	return nil
+}
+
+// switchReplica 将事务外的读操作切换到一个健康的只读副本
+func (r *resolver) switchReplica(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+
+	// 事务中的读操作必须与写操作使用同一个连接
+	switch db.Statement.ConnPool.(type) {
+	case gorm.TxCommitter, *gorm.PreparedStmtTX:
+		return
+	}
+
+	if pinned(db.Statement.Context) {
+		return
+	}
+
+	// Raw 在执行前就已经设置了 SQL，可能是带有 RETURNING 的写操作，与 Exec 一样访问主库
+	if db.Statement.SQL.Len() > 0 {
+		return
+	}
+
+	if rep := r.pick(); rep != nil {
+		db.Statement.ConnPool = rep.db
+	}
+}
+
+// pick 轮询选择一个健康的只读副本，没有健康的副本时返回 nil
+func (r *resolver) pick() *replica {
+	n := uint64(len(r.replicas))
+	start := r.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		if rep := r.replicas[(start+i)%n]; rep.healthy.Load() {
+			return rep
+		}
+	}
+	return nil
+}
+
+// watch 定期检查只读副本的健康状态，直到 close 被调用
+func (r *resolver) watch() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.check()
+		}
+	}
+}
+
+// close 停止健康检查，并关闭所有只读副本的连接
+func (r *resolver) close() error {
+	close(r.stop)
+
+	var errs []error
+	for _, rep := range r.replicas {
+		errs = append(errs, rep.db.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// check 检查所有只读副本是否可以连接，以及复制延迟是否超过了 maxLag
+func (r *resolver) check() {
+	for _, rep := range r.replicas {
+		healthy, err := r.probe(rep)
+		if old := rep.healthy.Swap(healthy); old != healthy {
+			if healthy {
+				r.logger.Logger.Info(context.Background(), "replica %s is healthy again, routing reads to it", rep.host)
+			} else {
+				r.logger.Logger.Warn(context.Background(), "replica %s is unhealthy, stop routing reads to it: %v", rep.host, err)
+			}
+		}
+	}
+}
+
+// probe 检查单个只读副本
+func (r *resolver) probe(rep *replica) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.interval)
+	defer cancel()
+
+	if err := rep.db.PingContext(ctx); err != nil {
+		return false, err
+	}
+	if r.lag == nil || r.maxLag <= 0 {
+		return true, nil
+	}
+
+	lag, ok, err := r.lag(ctx, rep.db)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, fmt.Errorf("replication is not running")
+	}
+	if lag > r.maxLag {
+		return false, fmt.Errorf("replication lag %s exceeds %s", lag, r.maxLag)
+	}
+	return true, nil
+}
+
+// mysqlLag 通过 `SHOW REPLICA STATUS`（MySQL 8.0.22 之前为 `SHOW SLAVE STATUS`）查询复制延迟。
+// 结果为空说明该实例不是副本（例如开发环境直接使用主库），视为没有延迟
+func mysqlLag(ctx context.Context, db *sql.DB) (time.Duration, bool, error) {
+	rows, err := db.QueryContext(ctx, "SHOW REPLICA STATUS")
+	if err != nil {
+		if rows, err = db.QueryContext(ctx, "SHOW SLAVE STATUS"); err != nil {
+			return 0, false, err
+		}
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, false, err
+	}
+	if !rows.Next() {
+		return 0, true, rows.Err()
+	}
+
+	values := make([]sql.RawBytes, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return 0, false, err
+	}
+
+	for i, column := range columns {
+		if column != "Seconds_Behind_Source" && column != "Seconds_Behind_Master" {
+			continue
+		}
+		// NULL 表示复制线程没有运行
+		if values[i] == nil {
+			return 0, false, nil
+		}
+		seconds, err := strconv.Atoi(string(values[i]))
+		if err != nil {
+			return 0, false, err
+		}
+		return time.Duration(seconds) * time.Second, true, nil
+	}
+	return 0, true, nil
+}
+
+// pinKey 是 read-your-writes 标记在 context 中的键
+type pinKey struct{}
+
+// pin 记录一个请求是否写过数据
+type pin struct {
+	written atomic.Bool
+}
+
+// WithReadYourWrites 返回一个开启了 read-your-writes 的 ctx：使用该 ctx 执行过写操作后，后续的读操作都会访问主库，
+// 从而保证请求能读到自己刚写入的数据，不受副本复制延迟的影响。通常在每个请求开始时调用
+func WithReadYourWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pinKey{}, &pin{})
+}
+
+// markWritten 在写操作完成后标记 ctx
+func markWritten(db *gorm.DB) {
+	if p, ok := db.Statement.Context.Value(pinKey{}).(*pin); ok {
+		p.written.Store(true)
+	}
+}
+
+// pinned 判断 ctx 是否已经被固定到主库
+func pinned(ctx context.Context) bool {
+	p, ok := ctx.Value(pinKey{}).(*pin)
+	return ok && p.written.Load()
+}
+
+// WithPrimary 返回一个读操作始终访问主库的 ctx，用于不能容忍复制延迟的读操作，例如读取登录失败的次数和锁定时间
+func WithPrimary(ctx context.Context) context.Context {
+	p := &pin{}
+	p.written.Store(true)
+	return context.WithValue(ctx, pinKey{}, p)
+}
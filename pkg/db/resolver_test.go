@@ -0,0 +1,180 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeLag 是测试使用的 LagFunc，返回的延迟和复制状态可以在测试中修改
+type fakeLag struct {
+	lag     atomic.Int64
+	stopped atomic.Bool
+}
+
+func (f *fakeLag) get(ctx context.Context, db *sql.DB) (time.Duration, bool, error) {
+	return time.Duration(f.lag.Load()), !f.stopped.Load(), nil
+}
+
+// newResolverDB 使用两个 SQLite 数据库分别作为主库和只读副本，两个库中 source 表的内容不同，
+// 通过读到的内容就可以判断读操作被路由到了哪个库
+func newResolverDB(t *testing.T, lag LagFunc) *gorm.DB {
+	t.Helper()
+
+	dir := t.TempDir()
+	dsn := func(name string) string {
+		return "file:" + filepath.Join(dir, name+".db") + "?_pragma=busy_timeout(5000)"
+	}
+
+	for _, name := range []string{"primary", "replica"} {
+		db, err := sql.Open("sqlite", dsn(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec("CREATE TABLE source (name TEXT)"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec("INSERT INTO source (name) VALUES (?)", name); err != nil {
+			t.Fatal(err)
+		}
+		_ = db.Close()
+	}
+
+	db, err := gorm.Open(sqlite.Open(dsn("primary")), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = useReplicas(db, "sqlite", poolOptions{maxOpen: 1}, &replicaOptions{
+		hosts:    []string{"replica"},
+		dsn:      dsn,
+		lag:      lag,
+		maxLag:   time.Second,
+		interval: time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Close(db) })
+
+	return db
+}
+
+// readFrom 返回读操作访问的库
+func readFrom(t *testing.T, db *gorm.DB) string {
+	t.Helper()
+
+	var names []string
+	if err := db.Table("source").Pluck("name", &names).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(names) == 0 {
+		t.Fatal("source is empty")
+	}
+	return names[0]
+}
+
+func TestResolver_Routing(t *testing.T) {
+	db := newResolverDB(t, nil)
+	ctx := context.Background()
+
+	if got := readFrom(t, db.WithContext(ctx)); got != "replica" {
+		t.Fatalf("reads should go to the replica, got %s", got)
+	}
+
+	// 写操作访问主库
+	if err := db.WithContext(ctx).Table("source").Where("1 = 1").Update("name", "written").Error; err != nil {
+		t.Fatal(err)
+	}
+	var name string
+	if err := db.Raw("SELECT name FROM source").Scan(&name).Error; err != nil {
+		t.Fatal(err)
+	}
+	if name != "written" {
+		t.Fatalf("writes should go to the primary, got %s", name)
+	}
+	if got := readFrom(t, db.WithContext(ctx)); got != "replica" {
+		t.Fatalf("reads without read-your-writes should still go to the replica, got %s", got)
+	}
+
+	// 事务中的读操作访问主库
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if got := readFrom(t, tx); got != "written" {
+			t.Errorf("reads in a transaction should go to the primary, got %s", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolver_ReadYourWrites(t *testing.T) {
+	db := newResolverDB(t, nil)
+
+	ctx := WithReadYourWrites(context.Background())
+	if got := readFrom(t, db.WithContext(ctx)); got != "replica" {
+		t.Fatalf("reads before any write should go to the replica, got %s", got)
+	}
+	if err := db.WithContext(ctx).Table("source").Create(map[string]any{"name": "written"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got := readFrom(t, db.WithContext(ctx)); got != "primary" {
+		t.Fatalf("reads after a write should go to the primary, got %s", got)
+	}
+
+	// 其他请求不受影响
+	if got := readFrom(t, db.WithContext(WithReadYourWrites(context.Background()))); got != "replica" {
+		t.Fatalf("reads of another request should go to the replica, got %s", got)
+	}
+
+	if got := readFrom(t, db.WithContext(WithPrimary(context.Background()))); got != "primary" {
+		t.Fatalf("reads with WithPrimary should go to the primary, got %s", got)
+	}
+}
+
+func TestResolver_Health(t *testing.T) {
+	lag := &fakeLag{}
+	db := newResolverDB(t, lag.get)
+	r := db.Config.Plugins[resolverName].(*resolver)
+
+	tests := []struct {
+		name    string
+		lag     time.Duration
+		stopped bool
+		want    string
+	}{
+		{name: "healthy", lag: 500 * time.Millisecond, want: "replica"},
+		{name: "lag exceeds max", lag: 2 * time.Second, want: "primary"},
+		{name: "recovered", want: "replica"},
+		{name: "replication stopped", stopped: true, want: "primary"},
+		{name: "replication restarted", want: "replica"},
+	}
+
+	for _, tt := range tests {
+		lag.lag.Store(int64(tt.lag))
+		lag.stopped.Store(tt.stopped)
+		r.check()
+
+		if got := readFrom(t, db); got != tt.want {
+			t.Fatalf("%s: reads should go to the %s, got %s", tt.name, tt.want, got)
+		}
+	}
+
+	// 副本无法连接时回退到主库
+	if err := r.replicas[0].db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r.check()
+	if got := readFrom(t, db); got != "primary" {
+		t.Fatalf("reads should fall back to the primary when the replica is down, got %s", got)
+	}
+	if r.replicas[0].healthy.Load() {
+		t.Fatal("closed replica should be unhealthy")
+	}
+}
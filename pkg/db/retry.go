@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"math/rand"
+	"time"
+)
+
+// RetryOptions 定义了连接数据库失败时的重试策略
+type RetryOptions struct {
+	MaxRetries     int           // 最大重试次数，0 表示不重试，< 0 表示不限制次数（仍然受 MaxWait 限制）
+	InitialBackoff time.Duration // 第一次重试前的等待时间，之后每次翻倍
+	MaxBackoff     time.Duration // 两次重试之间的最长等待时间
+	MaxWait        time.Duration // 从第一次连接开始计算的最长等待时间，0 表示不限制
+
+	// OnRetry 在每次连接失败、准备重试之前调用，可用来记录日志
+	OnRetry func(attempt int, err error, backoff time.Duration)
+}
+
+// Connect 调用 open 建立数据库连接，失败时按照 retry 的策略使用指数退避加随机抖动的方式重试。
+// retry 为 nil 时只尝试一次。传给 open 的 ctx 在超过 MaxWait 后会被取消，open 需要据此中止正在进行的连接
+func Connect(ctx context.Context, open func(ctx context.Context) (*gorm.DB, error), retry *RetryOptions) (*gorm.DB, error) {
+	if retry == nil {
+		return open(ctx)
+	}
+
+	if retry.MaxWait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, retry.MaxWait)
+		defer cancel()
+	}
+
+	backoff := retry.InitialBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	for attempt := 1; ; attempt++ {
+		db, err := open(ctx)
+		if err == nil {
+			return db, nil
+		}
+
+		if retry.MaxRetries >= 0 && attempt > retry.MaxRetries {
+			return nil, fmt.Errorf("failed to connect to database after %d attempts: %w", attempt, err)
+		}
+
+		// 下一次重试会超过 MaxWait 时直接放弃，没有必要等到超时
+		wait := jitter(backoff)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= wait {
+			return nil, fmt.Errorf("gave up connecting to database after %d attempts: %w", attempt, err)
+		}
+		if retry.OnRetry != nil {
+			retry.OnRetry(attempt, err, wait)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("gave up connecting to database after %d attempts: %w", attempt, err)
+		case <-time.After(wait):
+		}
+
+		backoff *= 2
+		if retry.MaxBackoff > 0 && backoff > retry.MaxBackoff {
+			backoff = retry.MaxBackoff
+		}
+	}
+}
+
+// jitter 返回 [d/2, d) 之间的随机时间，避免多个实例同时重启后在同一时刻重连数据库
+func jitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
@@ -0,0 +1,163 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+var errRefused = errors.New("connection refused")
+
+// failingOpen 返回一个总是失败的 open，并记录被调用的次数
+func failingOpen(calls *int) func(ctx context.Context) (*gorm.DB, error) {
+	return func(ctx context.Context) (*gorm.DB, error) {
+		*calls++
+		return nil, errRefused
+	}
+}
+
+func TestJitter(t *testing.T) {
+	for _, d := range []time.Duration{time.Millisecond, time.Second, 30 * time.Second} {
+		for i := 0; i < 1000; i++ {
+			if got := jitter(d); got < d/2 || got >= d {
+				t.Fatalf("jitter(%v) = %v, want [%v, %v)", d, got, d/2, d)
+			}
+		}
+	}
+
+	if got := jitter(1); got != 1 {
+		t.Fatalf("jitter(1) = %v, want 1", got)
+	}
+}
+
+func TestConnect_Backoff(t *testing.T) {
+	var calls int
+	var backoffs []time.Duration
+	retry := &RetryOptions{
+		MaxRetries:     5,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     4 * time.Millisecond,
+		OnRetry: func(attempt int, err error, backoff time.Duration) {
+			if attempt != len(backoffs)+1 || !errors.Is(err, errRefused) {
+				t.Errorf("unexpected retry: attempt=%d, err=%v", attempt, err)
+			}
+			backoffs = append(backoffs, backoff)
+		},
+	}
+
+	_, err := Connect(context.Background(), failingOpen(&calls), retry)
+	if !errors.Is(err, errRefused) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 第一次连接加上 MaxRetries 次重试
+	if calls != 6 {
+		t.Fatalf("unexpected attempts: want 6, got %d", calls)
+	}
+
+	// 每次翻倍，不超过 MaxBackoff，并加上 [d/2, d) 的随机抖动
+	want := []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 4 * time.Millisecond, 4 * time.Millisecond}
+	if len(backoffs) != len(want) {
+		t.Fatalf("unexpected retries: want %d, got %d", len(want), len(backoffs))
+	}
+	for i, d := range want {
+		if backoffs[i] < d/2 || backoffs[i] >= d {
+			t.Errorf("retry %d: backoff %v not in [%v, %v)", i+1, backoffs[i], d/2, d)
+		}
+	}
+}
+
+func TestConnect_MaxRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		retry     *RetryOptions
+		succeedAt int // 第几次连接成功，0 表示一直失败
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "no retry options", wantCalls: 1, wantErr: true},
+		{name: "no retries", retry: &RetryOptions{InitialBackoff: time.Millisecond}, wantCalls: 1, wantErr: true},
+		{name: "succeed after retries", retry: &RetryOptions{MaxRetries: 3, InitialBackoff: time.Millisecond}, succeedAt: 3, wantCalls: 3},
+		{name: "retries exhausted", retry: &RetryOptions{MaxRetries: 3, InitialBackoff: time.Millisecond}, wantCalls: 4, wantErr: true},
+		{name: "unlimited retries", retry: &RetryOptions{MaxRetries: -1, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}, succeedAt: 20, wantCalls: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			open := func(ctx context.Context) (*gorm.DB, error) {
+				calls++
+				if calls == tt.succeedAt {
+					return &gorm.DB{}, nil
+				}
+				return nil, errRefused
+			}
+
+			db, err := Connect(context.Background(), open, tt.retry)
+			if (err != nil) != tt.wantErr || (err == nil && db == nil) {
+				t.Fatalf("unexpected result: db=%v, err=%v", db, err)
+			}
+			if calls != tt.wantCalls {
+				t.Fatalf("unexpected attempts: want %d, got %d", tt.wantCalls, calls)
+			}
+		})
+	}
+}
+
+func TestConnect_MaxWait(t *testing.T) {
+	t.Run("stop retrying", func(t *testing.T) {
+		var calls, retries int
+		retry := &RetryOptions{
+			MaxRetries:     -1,
+			InitialBackoff: 80 * time.Millisecond,
+			MaxWait:        100 * time.Millisecond,
+			OnRetry:        func(attempt int, err error, backoff time.Duration) { retries++ },
+		}
+
+		start := time.Now()
+		_, err := Connect(context.Background(), failingOpen(&calls), retry)
+		if !errors.Is(err, errRefused) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// 第一次重试等待 [40ms, 80ms)，第二次重试需要等待 [80ms, 160ms)，超过了剩余的时间，直接放弃
+		if elapsed := time.Since(start); elapsed > retry.MaxWait {
+			t.Fatalf("Connect did not respect MaxWait: elapsed %v", elapsed)
+		}
+		if calls != 2 || retries != 1 {
+			t.Fatalf("unexpected attempts: want 2 attempts and 1 retry, got %d attempts and %d retries", calls, retries)
+		}
+	})
+
+	t.Run("cancel hanging open", func(t *testing.T) {
+		// 模拟一直没有响应的连接，open 只能通过 ctx 中止
+		open := func(ctx context.Context) (*gorm.DB, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+
+		start := time.Now()
+		_, err := Connect(context.Background(), open, &RetryOptions{MaxRetries: -1, InitialBackoff: time.Millisecond, MaxWait: 50 * time.Millisecond})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("Connect did not respect MaxWait: elapsed %v", elapsed)
+		}
+	})
+}
+
+func TestOpen_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// 连接一个不会响应的地址，ctx 已经取消时应立即返回
+	start := time.Now()
+	_, err := NewMySql(ctx, &MySqlOptions{Host: "10.255.255.1:3306", Username: "root", Database: "miniblog"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("open did not respect ctx: elapsed %v", elapsed)
+	}
+}
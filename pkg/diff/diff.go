@@ -0,0 +1,248 @@
+// Package diff 实现了按行比较两段文本的差异，并输出 unified 格式的结果。
+// 比较使用 Myers 差分算法的线性空间版本（二分查找中间 snake），内存占用与文本长度成正比
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op 是一行文本的差异类型
+type Op int
+
+const (
+	Equal  Op = iota // 两段文本中都有的行
+	Insert           // 只有新文本中有的行
+	Delete           // 只有旧文本中有的行
+)
+
+// String 返回 unified 格式中 Op 对应的行前缀
+func (op Op) String() string {
+	switch op {
+	case Insert:
+		return "+"
+	case Delete:
+		return "-"
+	default:
+		return " "
+	}
+}
+
+// Line 是差异结果中的一行
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Lines 按行比较 a 和 b，返回将 a 修改为 b 的最短编辑序列。行尾的换行符不属于行的内容
+func Lines(a, b string) []Line {
+	d := &differ{a: splitLines(a), b: splitLines(b)}
+	d.diff(0, len(d.a), 0, len(d.b))
+	return d.lines
+}
+
+// Unified 返回 unified 格式的差异，每处修改前后保留 context 行上下文，a 和 b 没有差异时返回空字符串
+func Unified(fromName, toName string, lines []Line, context int) string {
+	// 记录每一行之前 a 和 b 各有多少行，用于计算 hunk 的起始行号
+	aLines, bLines := make([]int, len(lines)+1), make([]int, len(lines)+1)
+	var hunks [][2]int
+	for i, line := range lines {
+		aLines[i+1], bLines[i+1] = aLines[i], bLines[i]
+		if line.Op != Insert {
+			aLines[i+1]++
+		}
+		if line.Op != Delete {
+			bLines[i+1]++
+		}
+		if line.Op == Equal {
+			continue
+		}
+
+		// 相邻修改的上下文重叠时合并为一个 hunk
+		start, end := max(0, i-context), min(len(lines), i+context+1)
+		if n := len(hunks); n > 0 && start <= hunks[n-1][1] {
+			hunks[n-1][1] = end
+		} else {
+			hunks = append(hunks, [2]int{start, end})
+		}
+	}
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+	for _, hunk := range hunks {
+		start, end := hunk[0], hunk[1]
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			hunkRange(aLines[start], aLines[end]-aLines[start]), hunkRange(bLines[start], bLines[end]-bLines[start]))
+		for _, line := range lines[start:end] {
+			sb.WriteString(line.Op.String())
+			sb.WriteString(line.Text)
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// hunkRange 返回 hunk 头中的行范围，before 为 hunk 之前的行数。与 GNU diff 一致，空范围的起始行号为前一行
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+// splitLines 将文本按 `\n` 分割为行，最后一行的换行符可以省略
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// differ 保存比较过程中的状态，差异按顺序追加到 lines 中
+type differ struct {
+	a, b  []string
+	lines []Line
+}
+
+func (d *differ) emit(op Op, texts []string) {
+	for _, text := range texts {
+		d.lines = append(d.lines, Line{Op: op, Text: text})
+	}
+}
+
+// diff 比较 a[a0:a1] 和 b[b0:b1]
+func (d *differ) diff(a0, a1, b0, b1 int) {
+	// 去掉相同的前缀和后缀，剩余部分的首尾行都不相同
+	start := a0
+	for a0 < a1 && b0 < b1 && d.a[a0] == d.b[b0] {
+		a0++
+		b0++
+	}
+	d.emit(Equal, d.a[start:a0])
+
+	end := a1
+	for a0 < a1 && b0 < b1 && d.a[a1-1] == d.b[b1-1] {
+		a1--
+		b1--
+	}
+
+	switch {
+	case a0 == a1:
+		d.emit(Insert, d.b[b0:b1])
+	case b0 == b1:
+		d.emit(Delete, d.a[a0:a1])
+	default:
+		if x, y, ok := d.bisect(a0, a1, b0, b1); ok {
+			d.diff(a0, x, b0, y)
+			d.diff(x, a1, y, b1)
+		} else {
+			d.emit(Delete, d.a[a0:a1])
+			d.emit(Insert, d.b[b0:b1])
+		}
+	}
+
+	d.emit(Equal, d.a[a1:end])
+}
+
+// bisect 从两端同时搜索 a[a0:a1] 到 b[b0:b1] 的最短编辑路径，返回两个方向的路径相遇的位置，
+// 以该位置为界分别比较两部分即可得到完整的编辑序列。两段文本没有相同的行时返回 false
+func (d *differ) bisect(a0, a1, b0, b1 int) (int, int, bool) {
+	n, m := a1-a0, b1-b0
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	// vf[offset+k] 是正向搜索在对角线 k 上到达的最远的 x，vb 是反向搜索从末尾算起的最远距离，-1 表示尚未到达
+	vf, vb := make([]int, 2*maxD+2), make([]int, 2*maxD+2)
+	for i := range vf {
+		vf[i], vb[i] = -1, -1
+	}
+	vf[offset+1], vb[offset+1] = 0, 0
+
+	delta := n - m
+	// delta 为奇数时在正向搜索中检查相遇，否则在反向搜索中检查
+	front := delta%2 != 0
+	// 超出编辑图边界的对角线不再搜索
+	k1start, k1end, k2start, k2end := 0, 0, 0, 0
+	for step := 0; step < maxD; step++ {
+		for k1 := -step + k1start; k1 <= step-k1end; k1 += 2 {
+			i := offset + k1
+			var x int
+			if k1 == -step || (k1 != step && vf[i-1] < vf[i+1]) {
+				x = vf[i+1]
+			} else {
+				x = vf[i-1] + 1
+			}
+			y := x - k1
+			for x < n && y < m && d.a[a0+x] == d.b[b0+y] {
+				x++
+				y++
+			}
+			vf[i] = x
+
+			switch {
+			case x > n:
+				k1end += 2
+			case y > m:
+				k1start += 2
+			case front:
+				j := offset + delta - k1
+				if j >= 0 && j < len(vb) && vb[j] != -1 && x >= n-vb[j] {
+					return a0 + x, b0 + y, true
+				}
+			}
+		}
+
+		for k2 := -step + k2start; k2 <= step-k2end; k2 += 2 {
+			i := offset + k2
+			var x int
+			if k2 == -step || (k2 != step && vb[i-1] < vb[i+1]) {
+				x = vb[i+1]
+			} else {
+				x = vb[i-1] + 1
+			}
+			y := x - k2
+			for x < n && y < m && d.a[a1-x-1] == d.b[b1-y-1] {
+				x++
+				y++
+			}
+			vb[i] = x
+
+			switch {
+			case x > n:
+				k2end += 2
+			case y > m:
+				k2start += 2
+			case !front:
+				j := offset + delta - k2
+				if j >= 0 && j < len(vf) && vf[j] != -1 {
+					fx := vf[j]
+					fy := offset + fx - j
+					if fx >= n-x {
+						return a0 + fx, b0 + fy, true
+					}
+				}
+			}
+		}
+	}
+
+	return 0, 0, false
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,94 @@
+package diff_test
+
+import (
+	"math/rand"
+	"miniblog/pkg/diff"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	a := "title\n\none\ntwo\nthree\nfour\nfive\nsix\nseven\neight\n"
+	b := "title\n\none\n2\nthree\nfour\nfive\nsix\nseven\neight\nnine\n"
+
+	got := diff.Unified("a", "b", diff.Lines(a, b), 1)
+	want := `--- a
++++ b
+@@ -3,3 +3,3 @@
+ one
+-two
++2
+ three
+@@ -10 +10,2 @@
+ eight
++nine
+`
+	if got != want {
+		t.Fatalf("unexpected diff:\n%s", got)
+	}
+
+	if got := diff.Unified("a", "b", diff.Lines(a, a), 3); got != "" {
+		t.Fatalf("want empty diff for same text, got:\n%s", got)
+	}
+	if got := diff.Unified("a", "b", diff.Lines("", "new\n"), 3); got != "--- a\n+++ b\n@@ -0,0 +1 @@\n+new\n" {
+		t.Fatalf("unexpected diff for empty text:\n%s", got)
+	}
+}
+
+// TestLinesMinimal 使用随机文本检查 Lines 返回的编辑序列可以还原两段文本，且编辑次数与最长公共子序列算出的最少次数相同
+func TestLinesMinimal(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomText := func() []string {
+		lines := make([]string, r.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + r.Intn(4)))
+		}
+		return lines
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b := randomText(), randomText()
+		lines := diff.Lines(strings.Join(a, "\n"), strings.Join(b, "\n"))
+
+		var gotA, gotB []string
+		edits := 0
+		for _, line := range lines {
+			if line.Op != diff.Insert {
+				gotA = append(gotA, line.Text)
+			}
+			if line.Op != diff.Delete {
+				gotB = append(gotB, line.Text)
+			}
+			if line.Op != diff.Equal {
+				edits++
+			}
+		}
+		if strings.Join(gotA, "\n") != strings.Join(a, "\n") || strings.Join(gotB, "\n") != strings.Join(b, "\n") {
+			t.Fatalf("edit script does not reproduce input: %q %q", a, b)
+		}
+		if want := len(a) + len(b) - 2*lcs(a, b); edits != want {
+			t.Fatalf("want %d edits for %q -> %q, got %d", want, a, b, edits)
+		}
+	}
+}
+
+// lcs 返回 a 和 b 的最长公共子序列的长度
+func lcs(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				dp[i][j] = dp[i+1][j+1] + 1
+			case dp[i+1][j] > dp[i][j+1]:
+				dp[i][j] = dp[i+1][j]
+			default:
+				dp[i][j] = dp[i][j+1]
+			}
+		}
+	}
+	return dp[0][0]
+}
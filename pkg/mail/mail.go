@@ -0,0 +1,87 @@
+// Package mail 提供了发送邮件的 Sender 接口及其 SMTP、文件和标准输出实现，以及基于 text/template 的邮件模板
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// ErrInvalidHeader 表示收件人或主题中包含换行符等非法字符，用于防止邮件头注入
+var ErrInvalidHeader = errors.New("mail: invalid header value")
+
+// Message 是一封纯文本邮件
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Sender 定义了发送邮件需要实现的方法
+type Sender interface {
+	// Send 发送 msg，发件人由 Sender 的配置决定
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Bytes 将 msg 编码为 RFC 5322 格式的邮件内容，正文使用 quoted-printable 编码
+func (msg *Message) Bytes(from string) ([]byte, error) {
+	if len(msg.To) == 0 {
+		return nil, fmt.Errorf("mail: no recipients")
+	}
+	for _, v := range append([]string{from, msg.Subject}, msg.To...) {
+		if strings.ContainsAny(v, "\r\n") {
+			return nil, ErrInvalidHeader
+		}
+	}
+	for _, to := range msg.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return nil, fmt.Errorf("mail: invalid recipient %q: %w", to, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+	header("From", from)
+	header("To", strings.Join(msg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", messageID(from))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	buf.WriteString("\r\n")
+
+	w := quotedprintable.NewWriter(&buf)
+	if _, err := w.Write([]byte(strings.ReplaceAll(msg.Body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// messageID 生成一个随机的 Message-ID，域名部分取自发件人地址
+func messageID(from string) string {
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if i := strings.LastIndex(addr.Address, "@"); i >= 0 {
+			domain = addr.Address[i+1:]
+		}
+	}
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
@@ -0,0 +1,246 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMessage_Bytes(t *testing.T) {
+	msg := &Message{To: []string{"alice@example.com"}, Subject: "你好", Body: "Hello, 世界\nhttps://example.com/?token=abc"}
+	data, err := msg.Bytes("miniblog <noreply@example.com>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Header.Get("To") != "alice@example.com" || !strings.HasSuffix(parsed.Header.Get("Message-ID"), "@example.com>") {
+		t.Errorf("unexpected headers: %v", parsed.Header)
+	}
+	body, _ := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	if string(body) != "Hello, 世界\r\nhttps://example.com/?token=abc" {
+		t.Errorf("unexpected body: %q", body)
+	}
+
+	for _, bad := range []*Message{
+		{To: []string{"alice@example.com"}, Subject: "hi\r\nBcc: eve@example.com"},
+		{To: []string{"alice@example.com\r\nBcc: eve@example.com"}},
+		{To: []string{"not an address"}},
+		{},
+	} {
+		if _, err := bad.Bytes("noreply@example.com"); err == nil {
+			t.Errorf("expected an error for %+v", bad)
+		}
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	fsys := fstest.MapFS{
+		"welcome.tmpl": {Data: []byte(`{{define "subject"}} Welcome {{.}} {{end}}{{define "body"}}Hi {{.}}{{end}}`)},
+		"broken.txt":   {Data: []byte(`{{define "subject"}}no body{{end}}`)},
+	}
+
+	templates, err := ParseTemplates(fsys, "*.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !templates.Has("welcome") || templates.Has("broken") {
+		t.Fatal("unexpected templates")
+	}
+
+	msg, err := templates.Render("welcome", "alice@example.com", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Subject != "Welcome alice" || msg.Body != "Hi alice\n" || msg.To[0] != "alice@example.com" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+	if _, err := templates.Render("missing", "alice@example.com", nil); err == nil {
+		t.Error("expected an error for a missing template")
+	}
+
+	if _, err := ParseTemplates(fsys, "*.txt"); err == nil {
+		t.Error("expected an error for a template without body")
+	}
+}
+
+func TestFileSender(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	sender, err := NewFileSender(dir, "noreply@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := sender.Send(context.Background(), &Message{To: []string{"alice@example.com"}, Subject: "hi", Body: "hello"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 2 {
+		t.Fatalf("want 2 mails, got %d", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	if !bytes.Contains(data, []byte("Subject: hi\r\n")) {
+		t.Errorf("unexpected mail: %s", data)
+	}
+}
+
+// blockingSender 在 release 关闭之前阻塞 Send，并记录发送的邮件和使用的 ctx 是否已经取消
+type blockingSender struct {
+	started  chan struct{} // 每次开始发送时写入
+	release  chan struct{}
+	mu       sync.Mutex
+	subjects []string
+	canceled bool
+}
+
+func (s *blockingSender) Send(ctx context.Context, msg *Message) error {
+	s.started <- struct{}{}
+	<-s.release
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subjects = append(s.subjects, msg.Subject)
+	s.canceled = s.canceled || ctx.Err() != nil
+	if msg.Subject == "fail" {
+		return errors.New("smtp: 550 mailbox unavailable")
+	}
+	return nil
+}
+
+func TestQueue(t *testing.T) {
+	sender := &blockingSender{started: make(chan struct{}, 3), release: make(chan struct{})}
+	var failed []string
+	q := NewQueue(sender, 2, func(msg *Message, err error) { failed = append(failed, msg.Subject) })
+
+	// 底层的 Sender 阻塞时 Send 也会立即返回，请求的 ctx 取消后邮件仍然会发送
+	ctx, cancel := context.WithCancel(context.Background())
+	for i, subject := range []string{"first", "fail", "second"} {
+		if err := q.Send(ctx, &Message{To: []string{"alice@example.com"}, Subject: subject}); err != nil {
+			t.Fatalf("send %s: %v", subject, err)
+		}
+		if i == 0 {
+			<-sender.started
+		}
+	}
+	cancel()
+	// 第一封邮件正在发送，队列中还有两封，已经满了
+	if err := q.Send(context.Background(), &Message{To: []string{"alice@example.com"}, Subject: "dropped"}); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("want ErrQueueFull, got %v", err)
+	}
+
+	close(sender.release)
+	q.Flush()
+	if strings.Join(sender.subjects, ",") != "first,fail,second" || sender.canceled {
+		t.Fatalf("unexpected sent mails: %v, canceled: %v", sender.subjects, sender.canceled)
+	}
+	if len(failed) != 1 || failed[0] != "fail" {
+		t.Fatalf("unexpected failed mails: %v", failed)
+	}
+
+	q.Close()
+	if err := q.Send(context.Background(), &Message{To: []string{"alice@example.com"}, Subject: "late"}); !errors.Is(err, ErrQueueClosed) {
+		t.Fatalf("want ErrQueueClosed, got %v", err)
+	}
+}
+
+func TestSMTPSender(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	received := make(chan string, 1)
+	go serveSMTP(t, lis, received)
+
+	addr := lis.Addr().(*net.TCPAddr)
+	sender, err := NewSMTPSender(&SMTPOptions{Host: "127.0.0.1", Port: addr.Port, TLS: TLSNone, From: "miniblog <noreply@example.com>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sender.Send(context.Background(), &Message{To: []string{"Alice <alice@example.com>"}, Subject: "hi", Body: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+
+	transcript := <-received
+	for _, want := range []string{"MAIL FROM:<noreply@example.com>", "RCPT TO:<alice@example.com>", "Subject: hi"} {
+		if !strings.Contains(transcript, want) {
+			t.Errorf("transcript does not contain %q:\n%s", want, transcript)
+		}
+	}
+
+	if _, err := NewSMTPSender(&SMTPOptions{Host: "127.0.0.1", TLS: "ssl", From: "noreply@example.com"}); err == nil {
+		t.Error("expected an error for an unsupported tls mode")
+	}
+	if s := (SMTPOptions{Password: "secret"}).String(); strings.Contains(s, "secret") {
+		t.Errorf("password is not redacted: %s", s)
+	}
+}
+
+// serveSMTP 是一个只接收一封邮件的最简 SMTP 服务，收到的所有命令和数据会写入 received
+func serveSMTP(t *testing.T, lis net.Listener, received chan<- string) {
+	conn, err := lis.Accept()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer conn.Close()
+
+	var transcript strings.Builder
+	defer func() { received <- transcript.String() }()
+
+	r := bufio.NewReader(conn)
+	reply := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				t.Error(err)
+			}
+			return
+		}
+		transcript.WriteString(line)
+
+		switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+		case strings.HasPrefix(cmd, "EHLO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "DATA"):
+			reply("354 go ahead")
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				transcript.WriteString(line)
+				if line == ".\r\n" {
+					break
+				}
+			}
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "QUIT"):
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrQueueFull 表示 Queue 中等待发送的邮件已经达到上限
+	ErrQueueFull = errors.New("mail: queue is full")
+	// ErrQueueClosed 表示 Queue 已经关闭，不再接收新的邮件
+	ErrQueueClosed = errors.New("mail: queue is closed")
+)
+
+// Queue 是一个异步的 Sender：Send 只将邮件放入队列后立即返回，由后台 goroutine 调用底层的 Sender 依次发送。
+// 适用于不能让调用方感知发送耗时的场景，例如重置密码邮件，避免通过响应时间判断邮箱是否存在
+type Queue struct {
+	sender  Sender
+	msgs    chan *Message
+	onError func(msg *Message, err error)
+
+	mu     sync.Mutex
+	idle   *sync.Cond // 等待发送的邮件数变为 0 时广播
+	n      int        // 已经放入队列但还没有发送完成的邮件数
+	closed bool
+	done   chan struct{}
+}
+
+// 确保 Queue 实现了 Sender 接口
+var _ Sender = (*Queue)(nil)
+
+// NewQueue 创建一个最多缓存 size 封邮件的 Queue，并启动发送邮件的 goroutine。
+// 发送失败时调用 onError，onError 可以为 nil。不再使用时需要调用 Close
+func NewQueue(sender Sender, size int, onError func(msg *Message, err error)) *Queue {
+	if size <= 0 {
+		size = 100
+	}
+
+	q := &Queue{
+		sender:  sender,
+		msgs:    make(chan *Message, size),
+		onError: onError,
+		done:    make(chan struct{}),
+	}
+	q.idle = sync.NewCond(&q.mu)
+	go q.run()
+
+	return q
+}
+
+// Send 实现了 Sender 接口中的 Send 方法。邮件在后台使用与 ctx 无关的 context 发送，请求结束后不会被取消，
+// 返回 nil 只表示邮件已经放入队列
+func (q *Queue) Send(ctx context.Context, msg *Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	select {
+	case q.msgs <- msg:
+		q.n++
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Flush 等待已经放入队列的邮件全部发送完成
+func (q *Queue) Flush() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.n > 0 {
+		q.idle.Wait()
+	}
+}
+
+// Close 停止接收新的邮件，并等待已经放入队列的邮件全部发送完成
+func (q *Queue) Close() {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.msgs)
+	}
+	q.mu.Unlock()
+
+	<-q.done
+}
+
+// run 依次发送队列中的邮件，直到 Close 被调用且队列为空
+func (q *Queue) run() {
+	defer close(q.done)
+
+	for msg := range q.msgs {
+		if err := q.sender.Send(context.Background(), msg); err != nil && q.onError != nil {
+			q.onError(msg, err)
+		}
+
+		q.mu.Lock()
+		if q.n--; q.n == 0 {
+			q.idle.Broadcast()
+		}
+		q.mu.Unlock()
+	}
+}
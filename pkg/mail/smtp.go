@@ -0,0 +1,162 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTP 连接的加密方式
+const (
+	TLSNone     = "none"     // 不加密，仅适用于本地的中继服务
+	TLSStartTLS = "starttls" // 连接后通过 STARTTLS 升级为加密连接，通常使用 587 端口
+	TLSImplicit = "tls"      // 直接建立 TLS 连接，通常使用 465 端口
+)
+
+// defaultSMTPTimeout 是 ctx 没有截止时间时，发送一封邮件的超时时间
+const defaultSMTPTimeout = 30 * time.Second
+
+// SMTPOptions 包含通过 SMTP 发送邮件的配置项
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	Username string // 为空时不进行认证
+	Password string
+	TLS      string // 可选值：none,starttls,tls，默认 starttls
+	From     string // 发件人，例如 `miniblog <noreply@example.com>`
+}
+
+// String 实现 fmt.Stringer 接口，输出时隐藏密码
+func (o SMTPOptions) String() string {
+	type plain SMTPOptions
+	if o.Password != "" {
+		o.Password = "******"
+	}
+	return fmt.Sprintf("%+v", plain(o))
+}
+
+// GoString 实现 fmt.GoStringer 接口，使 `%#v` 同样隐藏密码
+func (o SMTPOptions) GoString() string {
+	return "mail.SMTPOptions" + o.String()
+}
+
+// SMTPSender 通过 SMTP 服务器发送邮件，每封邮件使用一个新的连接
+type SMTPSender struct {
+	opts SMTPOptions
+}
+
+// 确保 SMTPSender 实现了 Sender 接口
+var _ Sender = (*SMTPSender)(nil)
+
+// NewSMTPSender 根据 opts 创建一个 SMTPSender
+func NewSMTPSender(opts *SMTPOptions) (*SMTPSender, error) {
+	o := *opts
+	if o.TLS == "" {
+		o.TLS = TLSStartTLS
+	}
+	switch o.TLS {
+	case TLSNone, TLSStartTLS, TLSImplicit:
+	default:
+		return nil, fmt.Errorf("mail: unsupported smtp tls mode %q", o.TLS)
+	}
+	if o.Host == "" {
+		return nil, fmt.Errorf("mail: smtp host is empty")
+	}
+	if o.Port == 0 {
+		o.Port = map[string]int{TLSNone: 25, TLSStartTLS: 587, TLSImplicit: 465}[o.TLS]
+	}
+	if _, err := mail.ParseAddress(o.From); err != nil {
+		return nil, fmt.Errorf("mail: invalid from address %q: %w", o.From, err)
+	}
+
+	return &SMTPSender{opts: o}, nil
+}
+
+// Send 实现了 Sender 接口中的 Send 方法
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	data, err := msg.Bytes(s.opts.From)
+	if err != nil {
+		return err
+	}
+	from, _ := mail.ParseAddress(s.opts.From)
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultSMTPTimeout)
+		defer cancel()
+	}
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if s.opts.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.opts.Username, s.opts.Password, s.opts.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, to := range msg.To {
+		addr, _ := mail.ParseAddress(to)
+		if err := client.Rcpt(addr.Address); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// dial 连接 SMTP 服务器并按照配置建立加密连接，连接的截止时间与 ctx 相同
+func (s *SMTPSender) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.opts.Host, strconv.Itoa(s.opts.Port))
+	tlsConfig := &tls.Config{ServerName: s.opts.Host, MinVersion: tls.VersionTLS12}
+
+	var conn net.Conn
+	var err error
+	if s.opts.TLS == TLSImplicit {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.opts.Host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	if s.opts.TLS == TLSStartTLS {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			_ = client.Close()
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
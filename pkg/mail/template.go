@@ -0,0 +1,74 @@
+package mail
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// Templates 是一组邮件模板。每个模板文件需要定义 `subject` 和 `body` 两个模板，例如：
+//
+//	{{define "subject"}}Verify your email{{end}}
+//	{{define "body"}}Hi {{.Username}}, ...{{end}}
+type Templates struct {
+	templates map[string]*template.Template
+}
+
+// ParseTemplates 解析 fsys 中匹配 pattern 的模板文件，模板名为去掉扩展名的文件名，例如 `verify_email.tmpl` 的模板名为 `verify_email`
+func ParseTemplates(fsys fs.FS, pattern string) (*Templates, error) {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("mail: no templates match %q", pattern)
+	}
+
+	t := &Templates{templates: make(map[string]*template.Template, len(files))}
+	for _, file := range files {
+		tmpl, err := template.ParseFS(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range []string{"subject", "body"} {
+			if tmpl.Lookup(name) == nil {
+				return nil, fmt.Errorf("mail: template %s does not define %q", file, name)
+			}
+		}
+
+		base := path.Base(file)
+		t.templates[strings.TrimSuffix(base, path.Ext(base))] = tmpl
+	}
+
+	return t, nil
+}
+
+// Has 判断是否存在名为 name 的模板
+func (t *Templates) Has(name string) bool {
+	_, ok := t.templates[name]
+	return ok
+}
+
+// Render 使用 data 渲染模板 name，返回发送给 to 的邮件
+func (t *Templates) Render(name string, to string, data any) (*Message, error) {
+	tmpl, ok := t.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("mail: template %q not found", name)
+	}
+
+	var subject, body strings.Builder
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		To:      []string{to},
+		Subject: strings.TrimSpace(subject.String()),
+		Body:    strings.TrimSpace(body.String()) + "\n",
+	}, nil
+}
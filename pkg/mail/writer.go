@@ -0,0 +1,75 @@
+package mail
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// WriterSender 将邮件原文写入 io.Writer，例如标准输出，适用于开发环境
+type WriterSender struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+}
+
+// 确保 WriterSender 实现了 Sender 接口
+var _ Sender = (*WriterSender)(nil)
+
+// NewWriterSender 创建一个将邮件写入 w 的 WriterSender
+func NewWriterSender(w io.Writer, from string) *WriterSender {
+	return &WriterSender{w: w, from: from}
+}
+
+// Send 实现了 Sender 接口中的 Send 方法，多封邮件之间以空行分隔
+func (s *WriterSender) Send(ctx context.Context, msg *Message) error {
+	data, err := msg.Bytes(s.from)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = fmt.Fprintf(s.w, "%s\r\n", data)
+	return err
+}
+
+// FileSender 将每封邮件保存为目录下的一个 .eml 文件，适用于开发和测试环境
+type FileSender struct {
+	dir  string
+	from string
+}
+
+// 确保 FileSender 实现了 Sender 接口
+var _ Sender = (*FileSender)(nil)
+
+// NewFileSender 创建一个将邮件保存到 dir 的 FileSender，dir 不存在时会自动创建
+func NewFileSender(dir, from string) (*FileSender, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileSender{dir: dir, from: from}, nil
+}
+
+// Send 实现了 Sender 接口中的 Send 方法，文件名以发送时间开头，按文件名排序即为发送顺序
+func (s *FileSender) Send(ctx context.Context, msg *Message) error {
+	data, err := msg.Bytes(s.from)
+	if err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), hex.EncodeToString(suffix))
+
+	return os.WriteFile(filepath.Join(s.dir, name), data, 0o600)
+}
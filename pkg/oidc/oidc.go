@@ -0,0 +1,227 @@
+// Package oidc 封装了通过 OpenID Connect 授权码模式（带 PKCE）登录外部身份提供方（IdP）的流程
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+	"net/http"
+	"sync"
+)
+
+// 默认请求的 scope 和读取的 claim
+const (
+	defaultUsernameClaim = "preferred_username"
+	defaultGroupsClaim   = "groups"
+)
+
+var defaultScopes = []string{"profile", "email"}
+
+// ErrInvalidToken 表示 IdP 返回的 ID Token 无效，例如签名错误、已过期、audience 或 nonce 不匹配
+var ErrInvalidToken = errors.New("oidc: invalid id token")
+
+// Options 包含连接 IdP 的配置项，IdP 的各个端点通过 `<Issuer>/.well-known/openid-configuration` 自动发现
+type Options struct {
+	Issuer        string
+	ClientID      string
+	ClientSecret  string   // 公共客户端可以为空，此时只依赖 PKCE
+	RedirectURL   string   // IdP 授权后跳转的地址，需要在 IdP 中登记
+	Scopes        []string // 除 openid 外请求的 scope，默认 profile,email
+	UsernameClaim string   // 作为用户名的 claim，默认 preferred_username
+	GroupsClaim   string   // 用户组的 claim，默认 groups
+	HTTPClient    *http.Client
+}
+
+// String 返回隐藏了 ClientSecret 的配置，避免在日志中泄露
+func (o Options) String() string {
+	secret := ""
+	if o.ClientSecret != "" {
+		secret = "******"
+	}
+	return fmt.Sprintf("{Issuer:%s ClientID:%s ClientSecret:%s RedirectURL:%s Scopes:%v UsernameClaim:%s GroupsClaim:%s}",
+		o.Issuer, o.ClientID, secret, o.RedirectURL, o.Scopes, o.UsernameClaim, o.GroupsClaim)
+}
+
+// GoString 与 String 相同，用于 `%#v`
+func (o Options) GoString() string {
+	return o.String()
+}
+
+// Claims 是从 ID Token 中读取的用户信息
+type Claims struct {
+	Issuer        string
+	Subject       string
+	Username      string
+	Name          string
+	Email         string
+	EmailVerified bool
+	Groups        []string
+}
+
+// Provider 是一个 OIDC 客户端。首次使用时才会请求 IdP 的发现端点，IdP 暂时不可用不会影响服务启动
+type Provider struct {
+	opts Options
+
+	mu       sync.Mutex
+	config   *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// New 校验 opts 并创建 Provider，为空的可选字段使用默认值
+func New(opts Options) (*Provider, error) {
+	if opts.Issuer == "" || opts.ClientID == "" || opts.RedirectURL == "" {
+		return nil, errors.New("oidc: issuer, client-id and redirect-url are required")
+	}
+	if len(opts.Scopes) == 0 {
+		opts.Scopes = defaultScopes
+	}
+	if opts.UsernameClaim == "" {
+		opts.UsernameClaim = defaultUsernameClaim
+	}
+	if opts.GroupsClaim == "" {
+		opts.GroupsClaim = defaultGroupsClaim
+	}
+	return &Provider{opts: opts}, nil
+}
+
+// AuthCodeURL 返回跳转到 IdP 授权页面的地址，verifier 是 NewVerifier 生成的 PKCE code verifier
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	config, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return config.AuthCodeURL(state,
+		oidc.Nonce(nonce),
+		oauth2.SetAuthURLParam("code_challenge", challenge(verifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	), nil
+}
+
+// Exchange 使用授权码和 PKCE code verifier 换取 ID Token，校验 ID Token 的签名、audience、有效期和 nonce 后返回其中的用户信息
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*Claims, error) {
+	config, idTokenVerifier, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = p.clientContext(ctx)
+	token, err := config.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+	if err != nil {
+		return nil, fmt.Errorf("oidc: failed to exchange code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: missing id_token in token response", ErrInvalidToken)
+	}
+	idToken, err := idTokenVerifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidToken)
+	}
+
+	var raw map[string]any
+	if err := idToken.Claims(&raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	claims := &Claims{
+		Issuer:        idToken.Issuer,
+		Subject:       idToken.Subject,
+		Username:      stringClaim(raw, p.opts.UsernameClaim),
+		Name:          stringClaim(raw, "name"),
+		Email:         stringClaim(raw, "email"),
+		EmailVerified: raw["email_verified"] == true || raw["email_verified"] == "true",
+		Groups:        stringsClaim(raw, p.opts.GroupsClaim),
+	}
+	return claims, nil
+}
+
+// discover 请求 IdP 的发现端点，成功后缓存结果，失败时下次调用会重试
+func (p *Provider) discover(ctx context.Context) (*oauth2.Config, *oidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.config != nil {
+		return p.config, p.verifier, nil
+	}
+
+	provider, err := oidc.NewProvider(p.clientContext(ctx), p.opts.Issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("oidc: failed to discover provider %s: %w", p.opts.Issuer, err)
+	}
+
+	p.config = &oauth2.Config{
+		ClientID:     p.opts.ClientID,
+		ClientSecret: p.opts.ClientSecret,
+		RedirectURL:  p.opts.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       append([]string{oidc.ScopeOpenID}, p.opts.Scopes...),
+	}
+	p.verifier = provider.Verifier(&oidc.Config{ClientID: p.opts.ClientID})
+	return p.config, p.verifier, nil
+}
+
+// clientContext 在 ctx 中设置 go-oidc 和 oauth2 使用的 HTTP 客户端
+func (p *Provider) clientContext(ctx context.Context) context.Context {
+	if p.opts.HTTPClient == nil {
+		return ctx
+	}
+	return oidc.ClientContext(ctx, p.opts.HTTPClient)
+}
+
+// NewState 生成一个随机的 state，用于将 IdP 的回调与发起登录的客户端对应起来
+func NewState() (string, error) {
+	return randomString(16)
+}
+
+// NewVerifier 生成一个随机的 PKCE code verifier
+func NewVerifier() (string, error) {
+	return randomString(32)
+}
+
+// randomString 返回 n 个随机字节的 base64url 编码
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// challenge 返回 verifier 对应的 S256 code challenge
+func challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// stringClaim 返回字符串类型的 claim，不存在或类型不匹配时返回空字符串
+func stringClaim(raw map[string]any, name string) string {
+	s, _ := raw[name].(string)
+	return s
+}
+
+// stringsClaim 返回字符串数组类型的 claim，单个字符串会被当作只有一个元素的数组
+func stringsClaim(raw map[string]any, name string) []string {
+	switch v := raw[name].(type) {
+	case string:
+		return []string{v}
+	case []any:
+		ret := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				ret = append(ret, s)
+			}
+		}
+		return ret
+	}
+	return nil
+}
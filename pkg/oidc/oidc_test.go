@@ -0,0 +1,110 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"miniblog/pkg/oidc"
+	"miniblog/pkg/oidc/oidctest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func newProvider(t *testing.T, idp *oidctest.Server) *oidc.Provider {
+	p, err := oidc.New(oidc.Options{
+		Issuer:       idp.Issuer(),
+		ClientID:     oidctest.ClientID,
+		ClientSecret: oidctest.ClientSecret,
+		RedirectURL:  "http://127.0.0.1:8080/login/oidc/callback",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestProvider(t *testing.T) {
+	idp := oidctest.NewServer(t)
+	p := newProvider(t, idp)
+	ctx := context.Background()
+
+	verifier, err := oidc.NewVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := p.AuthCodeURL(ctx, "state", "nonce", verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(authURL)
+	if u.Query().Get("code_challenge_method") != "S256" || u.Query().Get("scope") != "openid profile email" {
+		t.Fatalf("unexpected auth url: %s", authURL)
+	}
+
+	code, state := idp.Authorize(t, authURL, map[string]any{
+		"sub":                "1001",
+		"preferred_username": "alice",
+		"email":              "alice@example.com",
+		"email_verified":     true,
+		"groups":             []string{"staff", "blog-admins"},
+	})
+	if state != "state" {
+		t.Fatalf("unexpected state: %s", state)
+	}
+
+	claims, err := p.Exchange(ctx, code, verifier, "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &oidc.Claims{
+		Issuer:        idp.Issuer(),
+		Subject:       "1001",
+		Username:      "alice",
+		Email:         "alice@example.com",
+		EmailVerified: true,
+		Groups:        []string{"staff", "blog-admins"},
+	}
+	if !reflect.DeepEqual(claims, want) {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	// 授权码只能使用一次
+	if _, err := p.Exchange(ctx, code, verifier, "nonce"); err == nil {
+		t.Fatal("authorization code was accepted twice")
+	}
+}
+
+func TestProviderRejectsMismatch(t *testing.T) {
+	idp := oidctest.NewServer(t)
+	p := newProvider(t, idp)
+	ctx := context.Background()
+
+	authorize := func() (string, string) {
+		verifier, _ := oidc.NewVerifier()
+		authURL, err := p.AuthCodeURL(ctx, "state", "nonce", verifier)
+		if err != nil {
+			t.Fatal(err)
+		}
+		code, _ := idp.Authorize(t, authURL, map[string]any{"sub": "1001"})
+		return code, verifier
+	}
+
+	// PKCE code verifier 不匹配
+	code, _ := authorize()
+	other, _ := oidc.NewVerifier()
+	if _, err := p.Exchange(ctx, code, other, "nonce"); err == nil {
+		t.Fatal("mismatched code verifier was accepted")
+	}
+
+	// nonce 不匹配
+	code, verifier := authorize()
+	if _, err := p.Exchange(ctx, code, verifier, "other"); !errors.Is(err, oidc.ErrInvalidToken) {
+		t.Fatalf("unexpected error for mismatched nonce: %v", err)
+	}
+}
+
+func TestNewRequiresOptions(t *testing.T) {
+	if _, err := oidc.New(oidc.Options{Issuer: "https://idp.example.com"}); err == nil {
+		t.Fatal("missing client-id and redirect-url should fail")
+	}
+}
@@ -0,0 +1,1398 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.23.4
+// source: miniblog/v1/miniblog.proto
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	MiniBlog_CreateUser_FullMethodName                = "/v1.MiniBlog/CreateUser"
+	MiniBlog_LoginUser_FullMethodName                 = "/v1.MiniBlog/LoginUser"
+	MiniBlog_ChangeUserPassword_FullMethodName        = "/v1.MiniBlog/ChangeUserPassword"
+	MiniBlog_UnlockUser_FullMethodName                = "/v1.MiniBlog/UnlockUser"
+	MiniBlog_LoginUserTwoFactor_FullMethodName        = "/v1.MiniBlog/LoginUserTwoFactor"
+	MiniBlog_EnrollUserTwoFactor_FullMethodName       = "/v1.MiniBlog/EnrollUserTwoFactor"
+	MiniBlog_ConfirmUserTwoFactor_FullMethodName      = "/v1.MiniBlog/ConfirmUserTwoFactor"
+	MiniBlog_DisableUserTwoFactor_FullMethodName      = "/v1.MiniBlog/DisableUserTwoFactor"
+	MiniBlog_SendUserVerificationEmail_FullMethodName = "/v1.MiniBlog/SendUserVerificationEmail"
+	MiniBlog_VerifyUserEmail_FullMethodName           = "/v1.MiniBlog/VerifyUserEmail"
+	MiniBlog_ResetUserPassword_FullMethodName         = "/v1.MiniBlog/ResetUserPassword"
+	MiniBlog_ConfirmUserPasswordReset_FullMethodName  = "/v1.MiniBlog/ConfirmUserPasswordReset"
+	MiniBlog_CreateUserAPIKey_FullMethodName          = "/v1.MiniBlog/CreateUserAPIKey"
+	MiniBlog_ListUserAPIKeys_FullMethodName           = "/v1.MiniBlog/ListUserAPIKeys"
+	MiniBlog_RevokeUserAPIKey_FullMethodName          = "/v1.MiniBlog/RevokeUserAPIKey"
+	MiniBlog_StartUserOIDCLogin_FullMethodName        = "/v1.MiniBlog/StartUserOIDCLogin"
+	MiniBlog_LoginUserOIDC_FullMethodName             = "/v1.MiniBlog/LoginUserOIDC"
+	MiniBlog_CreateUserInvitation_FullMethodName      = "/v1.MiniBlog/CreateUserInvitation"
+	MiniBlog_ListUserInvitations_FullMethodName       = "/v1.MiniBlog/ListUserInvitations"
+	MiniBlog_DeleteUserInvitation_FullMethodName      = "/v1.MiniBlog/DeleteUserInvitation"
+	MiniBlog_DeleteUser_FullMethodName                = "/v1.MiniBlog/DeleteUser"
+	MiniBlog_ListDeletedUsers_FullMethodName          = "/v1.MiniBlog/ListDeletedUsers"
+	MiniBlog_RestoreUser_FullMethodName               = "/v1.MiniBlog/RestoreUser"
+	MiniBlog_CreatePost_FullMethodName                = "/v1.MiniBlog/CreatePost"
+	MiniBlog_GetPost_FullMethodName                   = "/v1.MiniBlog/GetPost"
+	MiniBlog_ListPosts_FullMethodName                 = "/v1.MiniBlog/ListPosts"
+	MiniBlog_UpdatePost_FullMethodName                = "/v1.MiniBlog/UpdatePost"
+	MiniBlog_DeletePost_FullMethodName                = "/v1.MiniBlog/DeletePost"
+	MiniBlog_ListDeletedPosts_FullMethodName          = "/v1.MiniBlog/ListDeletedPosts"
+	MiniBlog_RestorePost_FullMethodName               = "/v1.MiniBlog/RestorePost"
+	MiniBlog_ListPostRevisions_FullMethodName         = "/v1.MiniBlog/ListPostRevisions"
+	MiniBlog_GetPostRevision_FullMethodName           = "/v1.MiniBlog/GetPostRevision"
+	MiniBlog_DiffPostRevisions_FullMethodName         = "/v1.MiniBlog/DiffPostRevisions"
+	MiniBlog_RestorePostRevision_FullMethodName       = "/v1.MiniBlog/RestorePostRevision"
+)
+
+// MiniBlogClient is the client API for MiniBlog service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MiniBlogClient interface {
+	// CreateUser 创建一个新的用户，对应 `POST /v1/users`
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*CreateUserResponse, error)
+	// LoginUser 校验用户名和密码并签发 JWT Token，对应 `POST /login`
+	LoginUser(ctx context.Context, in *LoginUserRequest, opts ...grpc.CallOption) (*LoginUserResponse, error)
+	// ChangeUserPassword 修改用户密码，对应 `PUT /v1/users/:name/change-password`
+	ChangeUserPassword(ctx context.Context, in *ChangeUserPasswordRequest, opts ...grpc.CallOption) (*ChangeUserPasswordResponse, error)
+	// UnlockUser 解除因连续登录失败而被锁定的账户，只有管理员可以调用，对应 `POST /v1/users/:name/unlock`
+	UnlockUser(ctx context.Context, in *UnlockUserRequest, opts ...grpc.CallOption) (*UnlockUserResponse, error)
+	// LoginUserTwoFactor 使用 LoginUser 返回的 challenge 和验证码（或恢复码）完成两步登录，对应 `POST /login/2fa`
+	LoginUserTwoFactor(ctx context.Context, in *LoginUserTwoFactorRequest, opts ...grpc.CallOption) (*LoginUserResponse, error)
+	// EnrollUserTwoFactor 生成新的 TOTP 密钥，对应 `POST /v1/users/:name/2fa`
+	EnrollUserTwoFactor(ctx context.Context, in *EnrollUserTwoFactorRequest, opts ...grpc.CallOption) (*EnrollUserTwoFactorResponse, error)
+	// ConfirmUserTwoFactor 校验第一个验证码并开启两步验证，对应 `POST /v1/users/:name/2fa/confirm`
+	ConfirmUserTwoFactor(ctx context.Context, in *ConfirmUserTwoFactorRequest, opts ...grpc.CallOption) (*ConfirmUserTwoFactorResponse, error)
+	// DisableUserTwoFactor 校验验证码后关闭两步验证，对应 `DELETE /v1/users/:name/2fa`
+	DisableUserTwoFactor(ctx context.Context, in *DisableUserTwoFactorRequest, opts ...grpc.CallOption) (*DisableUserTwoFactorResponse, error)
+	// SendUserVerificationEmail 向当前登录用户的邮箱发送验证邮件，对应 `POST /v1/users/:name/verify-email`
+	SendUserVerificationEmail(ctx context.Context, in *SendUserVerificationEmailRequest, opts ...grpc.CallOption) (*SendUserVerificationEmailResponse, error)
+	// VerifyUserEmail 校验验证邮件中的 token 并将邮箱标记为已验证，对应 `POST /v1/users/:name/verify-email/confirm`
+	VerifyUserEmail(ctx context.Context, in *VerifyUserEmailRequest, opts ...grpc.CallOption) (*VerifyUserEmailResponse, error)
+	// ResetUserPassword 向使用该邮箱的用户发送重置密码邮件，对应 `POST /v1/password-reset`
+	ResetUserPassword(ctx context.Context, in *ResetUserPasswordRequest, opts ...grpc.CallOption) (*ResetUserPasswordResponse, error)
+	// ConfirmUserPasswordReset 校验重置密码邮件中的 token 并设置新密码，对应 `POST /v1/password-reset/confirm`
+	ConfirmUserPasswordReset(ctx context.Context, in *ConfirmUserPasswordResetRequest, opts ...grpc.CallOption) (*ConfirmUserPasswordResetResponse, error)
+	// CreateUserAPIKey 为当前登录用户创建一个 API Key，对应 `POST /v1/users/:name/api-keys`
+	CreateUserAPIKey(ctx context.Context, in *CreateUserAPIKeyRequest, opts ...grpc.CallOption) (*CreateUserAPIKeyResponse, error)
+	// ListUserAPIKeys 列出当前登录用户的 API Key，对应 `GET /v1/users/:name/api-keys`
+	ListUserAPIKeys(ctx context.Context, in *ListUserAPIKeysRequest, opts ...grpc.CallOption) (*ListUserAPIKeysResponse, error)
+	// RevokeUserAPIKey 删除当前登录用户的一个 API Key，对应 `DELETE /v1/users/:name/api-keys/:prefix`
+	RevokeUserAPIKey(ctx context.Context, in *RevokeUserAPIKeyRequest, opts ...grpc.CallOption) (*RevokeUserAPIKeyResponse, error)
+	// StartUserOIDCLogin 发起 OIDC 登录，返回 IdP 的授权地址，对应 `POST /login/oidc`
+	StartUserOIDCLogin(ctx context.Context, in *StartUserOIDCLoginRequest, opts ...grpc.CallOption) (*StartUserOIDCLoginResponse, error)
+	// LoginUserOIDC 使用 IdP 回调的授权码完成 OIDC 登录，对应 `POST /login/oidc/callback`
+	LoginUserOIDC(ctx context.Context, in *LoginUserOIDCRequest, opts ...grpc.CallOption) (*LoginUserResponse, error)
+	// CreateUserInvitation 生成一个邀请码，只有管理员可以调用，对应 `POST /v1/invitations`
+	CreateUserInvitation(ctx context.Context, in *CreateUserInvitationRequest, opts ...grpc.CallOption) (*CreateUserInvitationResponse, error)
+	// ListUserInvitations 列出所有邀请码，只有管理员可以调用，对应 `GET /v1/invitations`
+	ListUserInvitations(ctx context.Context, in *ListUserInvitationsRequest, opts ...grpc.CallOption) (*ListUserInvitationsResponse, error)
+	// DeleteUserInvitation 删除一个邀请码，只有管理员可以调用，对应 `DELETE /v1/invitations/:code`
+	DeleteUserInvitation(ctx context.Context, in *DeleteUserInvitationRequest, opts ...grpc.CallOption) (*DeleteUserInvitationResponse, error)
+	// DeleteUser 删除用户及其所有博客，用户本人或管理员可以调用，对应 `DELETE /v1/users/:name`
+	DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error)
+	// ListDeletedUsers 列出已删除但尚未永久删除的用户，只有管理员可以调用，对应 `GET /v1/trash/users`
+	ListDeletedUsers(ctx context.Context, in *ListDeletedUsersRequest, opts ...grpc.CallOption) (*ListDeletedUsersResponse, error)
+	// RestoreUser 恢复已删除的用户，只有管理员可以调用，对应 `POST /v1/trash/users/:name/restore`
+	RestoreUser(ctx context.Context, in *RestoreUserRequest, opts ...grpc.CallOption) (*RestoreUserResponse, error)
+	// CreatePost 发布一篇博客，对应 `POST /v1/posts`
+	CreatePost(ctx context.Context, in *CreatePostRequest, opts ...grpc.CallOption) (*CreatePostResponse, error)
+	// GetPost 获取博客的详细信息，对应 `GET /v1/posts/:postID`
+	GetPost(ctx context.Context, in *GetPostRequest, opts ...grpc.CallOption) (*GetPostResponse, error)
+	// ListPosts 分页列出已发布的博客和当前登录用户自己的博客，对应 `GET /v1/posts`
+	ListPosts(ctx context.Context, in *ListPostsRequest, opts ...grpc.CallOption) (*ListPostsResponse, error)
+	// UpdatePost 修改当前登录用户的博客，对应 `PUT /v1/posts/:postID`
+	UpdatePost(ctx context.Context, in *UpdatePostRequest, opts ...grpc.CallOption) (*UpdatePostResponse, error)
+	// DeletePost 删除当前登录用户的博客，对应 `DELETE /v1/posts/:postID`
+	DeletePost(ctx context.Context, in *DeletePostRequest, opts ...grpc.CallOption) (*DeletePostResponse, error)
+	// ListDeletedPosts 列出已删除但尚未永久删除的博客，只有管理员可以调用，对应 `GET /v1/trash/posts`
+	ListDeletedPosts(ctx context.Context, in *ListPostsRequest, opts ...grpc.CallOption) (*ListPostsResponse, error)
+	// RestorePost 恢复已删除的博客，只有管理员可以调用，对应 `POST /v1/trash/posts/:postID/restore`
+	RestorePost(ctx context.Context, in *RestorePostRequest, opts ...grpc.CallOption) (*RestorePostResponse, error)
+	// ListPostRevisions 分页列出博客的历史版本，对应 `GET /v1/posts/:postID/revisions`
+	ListPostRevisions(ctx context.Context, in *ListPostRevisionsRequest, opts ...grpc.CallOption) (*ListPostRevisionsResponse, error)
+	// GetPostRevision 获取博客的指定版本，对应 `GET /v1/posts/:postID/revisions/:rev`
+	GetPostRevision(ctx context.Context, in *GetPostRevisionRequest, opts ...grpc.CallOption) (*GetPostRevisionResponse, error)
+	// DiffPostRevisions 按行比较博客的两个版本，对应 `GET /v1/posts/:postID/diff`
+	DiffPostRevisions(ctx context.Context, in *DiffPostRevisionsRequest, opts ...grpc.CallOption) (*DiffPostRevisionsResponse, error)
+	// RestorePostRevision 将当前登录用户的博客回滚到指定版本，对应 `POST /v1/posts/:postID/revisions/:rev/restore`
+	RestorePostRevision(ctx context.Context, in *RestorePostRevisionRequest, opts ...grpc.CallOption) (*RestorePostRevisionResponse, error)
+}
+
+type miniBlogClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMiniBlogClient(cc grpc.ClientConnInterface) MiniBlogClient {
+	return &miniBlogClient{cc}
+}
+
+func (c *miniBlogClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*CreateUserResponse, error) {
+	out := new(CreateUserResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_CreateUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) LoginUser(ctx context.Context, in *LoginUserRequest, opts ...grpc.CallOption) (*LoginUserResponse, error) {
+	out := new(LoginUserResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_LoginUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) ChangeUserPassword(ctx context.Context, in *ChangeUserPasswordRequest, opts ...grpc.CallOption) (*ChangeUserPasswordResponse, error) {
+	out := new(ChangeUserPasswordResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_ChangeUserPassword_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) UnlockUser(ctx context.Context, in *UnlockUserRequest, opts ...grpc.CallOption) (*UnlockUserResponse, error) {
+	out := new(UnlockUserResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_UnlockUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) LoginUserTwoFactor(ctx context.Context, in *LoginUserTwoFactorRequest, opts ...grpc.CallOption) (*LoginUserResponse, error) {
+	out := new(LoginUserResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_LoginUserTwoFactor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) EnrollUserTwoFactor(ctx context.Context, in *EnrollUserTwoFactorRequest, opts ...grpc.CallOption) (*EnrollUserTwoFactorResponse, error) {
+	out := new(EnrollUserTwoFactorResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_EnrollUserTwoFactor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) ConfirmUserTwoFactor(ctx context.Context, in *ConfirmUserTwoFactorRequest, opts ...grpc.CallOption) (*ConfirmUserTwoFactorResponse, error) {
+	out := new(ConfirmUserTwoFactorResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_ConfirmUserTwoFactor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) DisableUserTwoFactor(ctx context.Context, in *DisableUserTwoFactorRequest, opts ...grpc.CallOption) (*DisableUserTwoFactorResponse, error) {
+	out := new(DisableUserTwoFactorResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_DisableUserTwoFactor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) SendUserVerificationEmail(ctx context.Context, in *SendUserVerificationEmailRequest, opts ...grpc.CallOption) (*SendUserVerificationEmailResponse, error) {
+	out := new(SendUserVerificationEmailResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_SendUserVerificationEmail_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) VerifyUserEmail(ctx context.Context, in *VerifyUserEmailRequest, opts ...grpc.CallOption) (*VerifyUserEmailResponse, error) {
+	out := new(VerifyUserEmailResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_VerifyUserEmail_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) ResetUserPassword(ctx context.Context, in *ResetUserPasswordRequest, opts ...grpc.CallOption) (*ResetUserPasswordResponse, error) {
+	out := new(ResetUserPasswordResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_ResetUserPassword_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) ConfirmUserPasswordReset(ctx context.Context, in *ConfirmUserPasswordResetRequest, opts ...grpc.CallOption) (*ConfirmUserPasswordResetResponse, error) {
+	out := new(ConfirmUserPasswordResetResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_ConfirmUserPasswordReset_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) CreateUserAPIKey(ctx context.Context, in *CreateUserAPIKeyRequest, opts ...grpc.CallOption) (*CreateUserAPIKeyResponse, error) {
+	out := new(CreateUserAPIKeyResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_CreateUserAPIKey_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) ListUserAPIKeys(ctx context.Context, in *ListUserAPIKeysRequest, opts ...grpc.CallOption) (*ListUserAPIKeysResponse, error) {
+	out := new(ListUserAPIKeysResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_ListUserAPIKeys_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) RevokeUserAPIKey(ctx context.Context, in *RevokeUserAPIKeyRequest, opts ...grpc.CallOption) (*RevokeUserAPIKeyResponse, error) {
+	out := new(RevokeUserAPIKeyResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_RevokeUserAPIKey_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) StartUserOIDCLogin(ctx context.Context, in *StartUserOIDCLoginRequest, opts ...grpc.CallOption) (*StartUserOIDCLoginResponse, error) {
+	out := new(StartUserOIDCLoginResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_StartUserOIDCLogin_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) LoginUserOIDC(ctx context.Context, in *LoginUserOIDCRequest, opts ...grpc.CallOption) (*LoginUserResponse, error) {
+	out := new(LoginUserResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_LoginUserOIDC_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) CreateUserInvitation(ctx context.Context, in *CreateUserInvitationRequest, opts ...grpc.CallOption) (*CreateUserInvitationResponse, error) {
+	out := new(CreateUserInvitationResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_CreateUserInvitation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) ListUserInvitations(ctx context.Context, in *ListUserInvitationsRequest, opts ...grpc.CallOption) (*ListUserInvitationsResponse, error) {
+	out := new(ListUserInvitationsResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_ListUserInvitations_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) DeleteUserInvitation(ctx context.Context, in *DeleteUserInvitationRequest, opts ...grpc.CallOption) (*DeleteUserInvitationResponse, error) {
+	out := new(DeleteUserInvitationResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_DeleteUserInvitation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error) {
+	out := new(DeleteUserResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_DeleteUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) ListDeletedUsers(ctx context.Context, in *ListDeletedUsersRequest, opts ...grpc.CallOption) (*ListDeletedUsersResponse, error) {
+	out := new(ListDeletedUsersResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_ListDeletedUsers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) RestoreUser(ctx context.Context, in *RestoreUserRequest, opts ...grpc.CallOption) (*RestoreUserResponse, error) {
+	out := new(RestoreUserResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_RestoreUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) CreatePost(ctx context.Context, in *CreatePostRequest, opts ...grpc.CallOption) (*CreatePostResponse, error) {
+	out := new(CreatePostResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_CreatePost_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) GetPost(ctx context.Context, in *GetPostRequest, opts ...grpc.CallOption) (*GetPostResponse, error) {
+	out := new(GetPostResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_GetPost_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) ListPosts(ctx context.Context, in *ListPostsRequest, opts ...grpc.CallOption) (*ListPostsResponse, error) {
+	out := new(ListPostsResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_ListPosts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) UpdatePost(ctx context.Context, in *UpdatePostRequest, opts ...grpc.CallOption) (*UpdatePostResponse, error) {
+	out := new(UpdatePostResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_UpdatePost_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) DeletePost(ctx context.Context, in *DeletePostRequest, opts ...grpc.CallOption) (*DeletePostResponse, error) {
+	out := new(DeletePostResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_DeletePost_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) ListDeletedPosts(ctx context.Context, in *ListPostsRequest, opts ...grpc.CallOption) (*ListPostsResponse, error) {
+	out := new(ListPostsResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_ListDeletedPosts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) RestorePost(ctx context.Context, in *RestorePostRequest, opts ...grpc.CallOption) (*RestorePostResponse, error) {
+	out := new(RestorePostResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_RestorePost_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) ListPostRevisions(ctx context.Context, in *ListPostRevisionsRequest, opts ...grpc.CallOption) (*ListPostRevisionsResponse, error) {
+	out := new(ListPostRevisionsResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_ListPostRevisions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) GetPostRevision(ctx context.Context, in *GetPostRevisionRequest, opts ...grpc.CallOption) (*GetPostRevisionResponse, error) {
+	out := new(GetPostRevisionResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_GetPostRevision_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) DiffPostRevisions(ctx context.Context, in *DiffPostRevisionsRequest, opts ...grpc.CallOption) (*DiffPostRevisionsResponse, error) {
+	out := new(DiffPostRevisionsResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_DiffPostRevisions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miniBlogClient) RestorePostRevision(ctx context.Context, in *RestorePostRevisionRequest, opts ...grpc.CallOption) (*RestorePostRevisionResponse, error) {
+	out := new(RestorePostRevisionResponse)
+	err := c.cc.Invoke(ctx, MiniBlog_RestorePostRevision_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MiniBlogServer is the server API for MiniBlog service.
+// All implementations must embed UnimplementedMiniBlogServer
+// for forward compatibility
+type MiniBlogServer interface {
+	// CreateUser 创建一个新的用户，对应 `POST /v1/users`
+	CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error)
+	// LoginUser 校验用户名和密码并签发 JWT Token，对应 `POST /login`
+	LoginUser(context.Context, *LoginUserRequest) (*LoginUserResponse, error)
+	// ChangeUserPassword 修改用户密码，对应 `PUT /v1/users/:name/change-password`
+	ChangeUserPassword(context.Context, *ChangeUserPasswordRequest) (*ChangeUserPasswordResponse, error)
+	// UnlockUser 解除因连续登录失败而被锁定的账户，只有管理员可以调用，对应 `POST /v1/users/:name/unlock`
+	UnlockUser(context.Context, *UnlockUserRequest) (*UnlockUserResponse, error)
+	// LoginUserTwoFactor 使用 LoginUser 返回的 challenge 和验证码（或恢复码）完成两步登录，对应 `POST /login/2fa`
+	LoginUserTwoFactor(context.Context, *LoginUserTwoFactorRequest) (*LoginUserResponse, error)
+	// EnrollUserTwoFactor 生成新的 TOTP 密钥，对应 `POST /v1/users/:name/2fa`
+	EnrollUserTwoFactor(context.Context, *EnrollUserTwoFactorRequest) (*EnrollUserTwoFactorResponse, error)
+	// ConfirmUserTwoFactor 校验第一个验证码并开启两步验证，对应 `POST /v1/users/:name/2fa/confirm`
+	ConfirmUserTwoFactor(context.Context, *ConfirmUserTwoFactorRequest) (*ConfirmUserTwoFactorResponse, error)
+	// DisableUserTwoFactor 校验验证码后关闭两步验证，对应 `DELETE /v1/users/:name/2fa`
+	DisableUserTwoFactor(context.Context, *DisableUserTwoFactorRequest) (*DisableUserTwoFactorResponse, error)
+	// SendUserVerificationEmail 向当前登录用户的邮箱发送验证邮件，对应 `POST /v1/users/:name/verify-email`
+	SendUserVerificationEmail(context.Context, *SendUserVerificationEmailRequest) (*SendUserVerificationEmailResponse, error)
+	// VerifyUserEmail 校验验证邮件中的 token 并将邮箱标记为已验证，对应 `POST /v1/users/:name/verify-email/confirm`
+	VerifyUserEmail(context.Context, *VerifyUserEmailRequest) (*VerifyUserEmailResponse, error)
+	// ResetUserPassword 向使用该邮箱的用户发送重置密码邮件，对应 `POST /v1/password-reset`
+	ResetUserPassword(context.Context, *ResetUserPasswordRequest) (*ResetUserPasswordResponse, error)
+	// ConfirmUserPasswordReset 校验重置密码邮件中的 token 并设置新密码，对应 `POST /v1/password-reset/confirm`
+	ConfirmUserPasswordReset(context.Context, *ConfirmUserPasswordResetRequest) (*ConfirmUserPasswordResetResponse, error)
+	// CreateUserAPIKey 为当前登录用户创建一个 API Key，对应 `POST /v1/users/:name/api-keys`
+	CreateUserAPIKey(context.Context, *CreateUserAPIKeyRequest) (*CreateUserAPIKeyResponse, error)
+	// ListUserAPIKeys 列出当前登录用户的 API Key，对应 `GET /v1/users/:name/api-keys`
+	ListUserAPIKeys(context.Context, *ListUserAPIKeysRequest) (*ListUserAPIKeysResponse, error)
+	// RevokeUserAPIKey 删除当前登录用户的一个 API Key，对应 `DELETE /v1/users/:name/api-keys/:prefix`
+	RevokeUserAPIKey(context.Context, *RevokeUserAPIKeyRequest) (*RevokeUserAPIKeyResponse, error)
+	// StartUserOIDCLogin 发起 OIDC 登录，返回 IdP 的授权地址，对应 `POST /login/oidc`
+	StartUserOIDCLogin(context.Context, *StartUserOIDCLoginRequest) (*StartUserOIDCLoginResponse, error)
+	// LoginUserOIDC 使用 IdP 回调的授权码完成 OIDC 登录，对应 `POST /login/oidc/callback`
+	LoginUserOIDC(context.Context, *LoginUserOIDCRequest) (*LoginUserResponse, error)
+	// CreateUserInvitation 生成一个邀请码，只有管理员可以调用，对应 `POST /v1/invitations`
+	CreateUserInvitation(context.Context, *CreateUserInvitationRequest) (*CreateUserInvitationResponse, error)
+	// ListUserInvitations 列出所有邀请码，只有管理员可以调用，对应 `GET /v1/invitations`
+	ListUserInvitations(context.Context, *ListUserInvitationsRequest) (*ListUserInvitationsResponse, error)
+	// DeleteUserInvitation 删除一个邀请码，只有管理员可以调用，对应 `DELETE /v1/invitations/:code`
+	DeleteUserInvitation(context.Context, *DeleteUserInvitationRequest) (*DeleteUserInvitationResponse, error)
+	// DeleteUser 删除用户及其所有博客，用户本人或管理员可以调用，对应 `DELETE /v1/users/:name`
+	DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error)
+	// ListDeletedUsers 列出已删除但尚未永久删除的用户，只有管理员可以调用，对应 `GET /v1/trash/users`
+	ListDeletedUsers(context.Context, *ListDeletedUsersRequest) (*ListDeletedUsersResponse, error)
+	// RestoreUser 恢复已删除的用户，只有管理员可以调用，对应 `POST /v1/trash/users/:name/restore`
+	RestoreUser(context.Context, *RestoreUserRequest) (*RestoreUserResponse, error)
+	// CreatePost 发布一篇博客，对应 `POST /v1/posts`
+	CreatePost(context.Context, *CreatePostRequest) (*CreatePostResponse, error)
+	// GetPost 获取博客的详细信息，对应 `GET /v1/posts/:postID`
+	GetPost(context.Context, *GetPostRequest) (*GetPostResponse, error)
+	// ListPosts 分页列出已发布的博客和当前登录用户自己的博客，对应 `GET /v1/posts`
+	ListPosts(context.Context, *ListPostsRequest) (*ListPostsResponse, error)
+	// UpdatePost 修改当前登录用户的博客，对应 `PUT /v1/posts/:postID`
+	UpdatePost(context.Context, *UpdatePostRequest) (*UpdatePostResponse, error)
+	// DeletePost 删除当前登录用户的博客，对应 `DELETE /v1/posts/:postID`
+	DeletePost(context.Context, *DeletePostRequest) (*DeletePostResponse, error)
+	// ListDeletedPosts 列出已删除但尚未永久删除的博客，只有管理员可以调用，对应 `GET /v1/trash/posts`
+	ListDeletedPosts(context.Context, *ListPostsRequest) (*ListPostsResponse, error)
+	// RestorePost 恢复已删除的博客，只有管理员可以调用，对应 `POST /v1/trash/posts/:postID/restore`
+	RestorePost(context.Context, *RestorePostRequest) (*RestorePostResponse, error)
+	// ListPostRevisions 分页列出博客的历史版本，对应 `GET /v1/posts/:postID/revisions`
+	ListPostRevisions(context.Context, *ListPostRevisionsRequest) (*ListPostRevisionsResponse, error)
+	// GetPostRevision 获取博客的指定版本，对应 `GET /v1/posts/:postID/revisions/:rev`
+	GetPostRevision(context.Context, *GetPostRevisionRequest) (*GetPostRevisionResponse, error)
+	// DiffPostRevisions 按行比较博客的两个版本，对应 `GET /v1/posts/:postID/diff`
+	DiffPostRevisions(context.Context, *DiffPostRevisionsRequest) (*DiffPostRevisionsResponse, error)
+	// RestorePostRevision 将当前登录用户的博客回滚到指定版本，对应 `POST /v1/posts/:postID/revisions/:rev/restore`
+	RestorePostRevision(context.Context, *RestorePostRevisionRequest) (*RestorePostRevisionResponse, error)
+	mustEmbedUnimplementedMiniBlogServer()
+}
+
+// UnimplementedMiniBlogServer must be embedded to have forward compatible implementations.
+type UnimplementedMiniBlogServer struct {
+}
+
+func (UnimplementedMiniBlogServer) CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedMiniBlogServer) LoginUser(context.Context, *LoginUserRequest) (*LoginUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoginUser not implemented")
+}
+func (UnimplementedMiniBlogServer) ChangeUserPassword(context.Context, *ChangeUserPasswordRequest) (*ChangeUserPasswordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeUserPassword not implemented")
+}
+func (UnimplementedMiniBlogServer) UnlockUser(context.Context, *UnlockUserRequest) (*UnlockUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlockUser not implemented")
+}
+func (UnimplementedMiniBlogServer) LoginUserTwoFactor(context.Context, *LoginUserTwoFactorRequest) (*LoginUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoginUserTwoFactor not implemented")
+}
+func (UnimplementedMiniBlogServer) EnrollUserTwoFactor(context.Context, *EnrollUserTwoFactorRequest) (*EnrollUserTwoFactorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollUserTwoFactor not implemented")
+}
+func (UnimplementedMiniBlogServer) ConfirmUserTwoFactor(context.Context, *ConfirmUserTwoFactorRequest) (*ConfirmUserTwoFactorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmUserTwoFactor not implemented")
+}
+func (UnimplementedMiniBlogServer) DisableUserTwoFactor(context.Context, *DisableUserTwoFactorRequest) (*DisableUserTwoFactorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableUserTwoFactor not implemented")
+}
+func (UnimplementedMiniBlogServer) SendUserVerificationEmail(context.Context, *SendUserVerificationEmailRequest) (*SendUserVerificationEmailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendUserVerificationEmail not implemented")
+}
+func (UnimplementedMiniBlogServer) VerifyUserEmail(context.Context, *VerifyUserEmailRequest) (*VerifyUserEmailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyUserEmail not implemented")
+}
+func (UnimplementedMiniBlogServer) ResetUserPassword(context.Context, *ResetUserPasswordRequest) (*ResetUserPasswordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetUserPassword not implemented")
+}
+func (UnimplementedMiniBlogServer) ConfirmUserPasswordReset(context.Context, *ConfirmUserPasswordResetRequest) (*ConfirmUserPasswordResetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmUserPasswordReset not implemented")
+}
+func (UnimplementedMiniBlogServer) CreateUserAPIKey(context.Context, *CreateUserAPIKeyRequest) (*CreateUserAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUserAPIKey not implemented")
+}
+func (UnimplementedMiniBlogServer) ListUserAPIKeys(context.Context, *ListUserAPIKeysRequest) (*ListUserAPIKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserAPIKeys not implemented")
+}
+func (UnimplementedMiniBlogServer) RevokeUserAPIKey(context.Context, *RevokeUserAPIKeyRequest) (*RevokeUserAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeUserAPIKey not implemented")
+}
+func (UnimplementedMiniBlogServer) StartUserOIDCLogin(context.Context, *StartUserOIDCLoginRequest) (*StartUserOIDCLoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartUserOIDCLogin not implemented")
+}
+func (UnimplementedMiniBlogServer) LoginUserOIDC(context.Context, *LoginUserOIDCRequest) (*LoginUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoginUserOIDC not implemented")
+}
+func (UnimplementedMiniBlogServer) CreateUserInvitation(context.Context, *CreateUserInvitationRequest) (*CreateUserInvitationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUserInvitation not implemented")
+}
+func (UnimplementedMiniBlogServer) ListUserInvitations(context.Context, *ListUserInvitationsRequest) (*ListUserInvitationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserInvitations not implemented")
+}
+func (UnimplementedMiniBlogServer) DeleteUserInvitation(context.Context, *DeleteUserInvitationRequest) (*DeleteUserInvitationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUserInvitation not implemented")
+}
+func (UnimplementedMiniBlogServer) DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedMiniBlogServer) ListDeletedUsers(context.Context, *ListDeletedUsersRequest) (*ListDeletedUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeletedUsers not implemented")
+}
+func (UnimplementedMiniBlogServer) RestoreUser(context.Context, *RestoreUserRequest) (*RestoreUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreUser not implemented")
+}
+func (UnimplementedMiniBlogServer) CreatePost(context.Context, *CreatePostRequest) (*CreatePostResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePost not implemented")
+}
+func (UnimplementedMiniBlogServer) GetPost(context.Context, *GetPostRequest) (*GetPostResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPost not implemented")
+}
+func (UnimplementedMiniBlogServer) ListPosts(context.Context, *ListPostsRequest) (*ListPostsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPosts not implemented")
+}
+func (UnimplementedMiniBlogServer) UpdatePost(context.Context, *UpdatePostRequest) (*UpdatePostResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePost not implemented")
+}
+func (UnimplementedMiniBlogServer) DeletePost(context.Context, *DeletePostRequest) (*DeletePostResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePost not implemented")
+}
+func (UnimplementedMiniBlogServer) ListDeletedPosts(context.Context, *ListPostsRequest) (*ListPostsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeletedPosts not implemented")
+}
+func (UnimplementedMiniBlogServer) RestorePost(context.Context, *RestorePostRequest) (*RestorePostResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestorePost not implemented")
+}
+func (UnimplementedMiniBlogServer) ListPostRevisions(context.Context, *ListPostRevisionsRequest) (*ListPostRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPostRevisions not implemented")
+}
+func (UnimplementedMiniBlogServer) GetPostRevision(context.Context, *GetPostRevisionRequest) (*GetPostRevisionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPostRevision not implemented")
+}
+func (UnimplementedMiniBlogServer) DiffPostRevisions(context.Context, *DiffPostRevisionsRequest) (*DiffPostRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffPostRevisions not implemented")
+}
+func (UnimplementedMiniBlogServer) RestorePostRevision(context.Context, *RestorePostRevisionRequest) (*RestorePostRevisionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestorePostRevision not implemented")
+}
+func (UnimplementedMiniBlogServer) mustEmbedUnimplementedMiniBlogServer() {}
+
+// UnsafeMiniBlogServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MiniBlogServer will
+// result in compilation errors.
+type UnsafeMiniBlogServer interface {
+	mustEmbedUnimplementedMiniBlogServer()
+}
+
+func RegisterMiniBlogServer(s grpc.ServiceRegistrar, srv MiniBlogServer) {
+	s.RegisterService(&MiniBlog_ServiceDesc, srv)
+}
+
+func _MiniBlog_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_LoginUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).LoginUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_LoginUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).LoginUser(ctx, req.(*LoginUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_ChangeUserPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeUserPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).ChangeUserPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_ChangeUserPassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).ChangeUserPassword(ctx, req.(*ChangeUserPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_UnlockUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).UnlockUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_UnlockUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).UnlockUser(ctx, req.(*UnlockUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_LoginUserTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginUserTwoFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).LoginUserTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_LoginUserTwoFactor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).LoginUserTwoFactor(ctx, req.(*LoginUserTwoFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_EnrollUserTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollUserTwoFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).EnrollUserTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_EnrollUserTwoFactor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).EnrollUserTwoFactor(ctx, req.(*EnrollUserTwoFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_ConfirmUserTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmUserTwoFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).ConfirmUserTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_ConfirmUserTwoFactor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).ConfirmUserTwoFactor(ctx, req.(*ConfirmUserTwoFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_DisableUserTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableUserTwoFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).DisableUserTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_DisableUserTwoFactor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).DisableUserTwoFactor(ctx, req.(*DisableUserTwoFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_SendUserVerificationEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendUserVerificationEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).SendUserVerificationEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_SendUserVerificationEmail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).SendUserVerificationEmail(ctx, req.(*SendUserVerificationEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_VerifyUserEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyUserEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).VerifyUserEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_VerifyUserEmail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).VerifyUserEmail(ctx, req.(*VerifyUserEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_ResetUserPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetUserPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).ResetUserPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_ResetUserPassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).ResetUserPassword(ctx, req.(*ResetUserPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_ConfirmUserPasswordReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmUserPasswordResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).ConfirmUserPasswordReset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_ConfirmUserPasswordReset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).ConfirmUserPasswordReset(ctx, req.(*ConfirmUserPasswordResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_CreateUserAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).CreateUserAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_CreateUserAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).CreateUserAPIKey(ctx, req.(*CreateUserAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_ListUserAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserAPIKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).ListUserAPIKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_ListUserAPIKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).ListUserAPIKeys(ctx, req.(*ListUserAPIKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_RevokeUserAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeUserAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).RevokeUserAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_RevokeUserAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).RevokeUserAPIKey(ctx, req.(*RevokeUserAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_StartUserOIDCLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartUserOIDCLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).StartUserOIDCLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_StartUserOIDCLogin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).StartUserOIDCLogin(ctx, req.(*StartUserOIDCLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_LoginUserOIDC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginUserOIDCRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).LoginUserOIDC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_LoginUserOIDC_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).LoginUserOIDC(ctx, req.(*LoginUserOIDCRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_CreateUserInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserInvitationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).CreateUserInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_CreateUserInvitation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).CreateUserInvitation(ctx, req.(*CreateUserInvitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_ListUserInvitations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserInvitationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).ListUserInvitations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_ListUserInvitations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).ListUserInvitations(ctx, req.(*ListUserInvitationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_DeleteUserInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserInvitationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).DeleteUserInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_DeleteUserInvitation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).DeleteUserInvitation(ctx, req.(*DeleteUserInvitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_DeleteUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).DeleteUser(ctx, req.(*DeleteUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_ListDeletedUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeletedUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).ListDeletedUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_ListDeletedUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).ListDeletedUsers(ctx, req.(*ListDeletedUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_RestoreUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).RestoreUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_RestoreUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).RestoreUser(ctx, req.(*RestoreUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_CreatePost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).CreatePost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_CreatePost_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).CreatePost(ctx, req.(*CreatePostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_GetPost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).GetPost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_GetPost_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).GetPost(ctx, req.(*GetPostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_ListPosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPostsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).ListPosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_ListPosts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).ListPosts(ctx, req.(*ListPostsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_UpdatePost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).UpdatePost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_UpdatePost_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).UpdatePost(ctx, req.(*UpdatePostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_DeletePost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).DeletePost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_DeletePost_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).DeletePost(ctx, req.(*DeletePostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_ListDeletedPosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPostsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).ListDeletedPosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_ListDeletedPosts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).ListDeletedPosts(ctx, req.(*ListPostsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_RestorePost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestorePostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).RestorePost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_RestorePost_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).RestorePost(ctx, req.(*RestorePostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_ListPostRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPostRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).ListPostRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_ListPostRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).ListPostRevisions(ctx, req.(*ListPostRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_GetPostRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPostRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).GetPostRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_GetPostRevision_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).GetPostRevision(ctx, req.(*GetPostRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_DiffPostRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffPostRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).DiffPostRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_DiffPostRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).DiffPostRevisions(ctx, req.(*DiffPostRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiniBlog_RestorePostRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestorePostRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiniBlogServer).RestorePostRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MiniBlog_RestorePostRevision_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiniBlogServer).RestorePostRevision(ctx, req.(*RestorePostRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MiniBlog_ServiceDesc is the grpc.ServiceDesc for MiniBlog service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MiniBlog_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.MiniBlog",
+	HandlerType: (*MiniBlogServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateUser",
+			Handler:    _MiniBlog_CreateUser_Handler,
+		},
+		{
+			MethodName: "LoginUser",
+			Handler:    _MiniBlog_LoginUser_Handler,
+		},
+		{
+			MethodName: "ChangeUserPassword",
+			Handler:    _MiniBlog_ChangeUserPassword_Handler,
+		},
+		{
+			MethodName: "UnlockUser",
+			Handler:    _MiniBlog_UnlockUser_Handler,
+		},
+		{
+			MethodName: "LoginUserTwoFactor",
+			Handler:    _MiniBlog_LoginUserTwoFactor_Handler,
+		},
+		{
+			MethodName: "EnrollUserTwoFactor",
+			Handler:    _MiniBlog_EnrollUserTwoFactor_Handler,
+		},
+		{
+			MethodName: "ConfirmUserTwoFactor",
+			Handler:    _MiniBlog_ConfirmUserTwoFactor_Handler,
+		},
+		{
+			MethodName: "DisableUserTwoFactor",
+			Handler:    _MiniBlog_DisableUserTwoFactor_Handler,
+		},
+		{
+			MethodName: "SendUserVerificationEmail",
+			Handler:    _MiniBlog_SendUserVerificationEmail_Handler,
+		},
+		{
+			MethodName: "VerifyUserEmail",
+			Handler:    _MiniBlog_VerifyUserEmail_Handler,
+		},
+		{
+			MethodName: "ResetUserPassword",
+			Handler:    _MiniBlog_ResetUserPassword_Handler,
+		},
+		{
+			MethodName: "ConfirmUserPasswordReset",
+			Handler:    _MiniBlog_ConfirmUserPasswordReset_Handler,
+		},
+		{
+			MethodName: "CreateUserAPIKey",
+			Handler:    _MiniBlog_CreateUserAPIKey_Handler,
+		},
+		{
+			MethodName: "ListUserAPIKeys",
+			Handler:    _MiniBlog_ListUserAPIKeys_Handler,
+		},
+		{
+			MethodName: "RevokeUserAPIKey",
+			Handler:    _MiniBlog_RevokeUserAPIKey_Handler,
+		},
+		{
+			MethodName: "StartUserOIDCLogin",
+			Handler:    _MiniBlog_StartUserOIDCLogin_Handler,
+		},
+		{
+			MethodName: "LoginUserOIDC",
+			Handler:    _MiniBlog_LoginUserOIDC_Handler,
+		},
+		{
+			MethodName: "CreateUserInvitation",
+			Handler:    _MiniBlog_CreateUserInvitation_Handler,
+		},
+		{
+			MethodName: "ListUserInvitations",
+			Handler:    _MiniBlog_ListUserInvitations_Handler,
+		},
+		{
+			MethodName: "DeleteUserInvitation",
+			Handler:    _MiniBlog_DeleteUserInvitation_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _MiniBlog_DeleteUser_Handler,
+		},
+		{
+			MethodName: "ListDeletedUsers",
+			Handler:    _MiniBlog_ListDeletedUsers_Handler,
+		},
+		{
+			MethodName: "RestoreUser",
+			Handler:    _MiniBlog_RestoreUser_Handler,
+		},
+		{
+			MethodName: "CreatePost",
+			Handler:    _MiniBlog_CreatePost_Handler,
+		},
+		{
+			MethodName: "GetPost",
+			Handler:    _MiniBlog_GetPost_Handler,
+		},
+		{
+			MethodName: "ListPosts",
+			Handler:    _MiniBlog_ListPosts_Handler,
+		},
+		{
+			MethodName: "UpdatePost",
+			Handler:    _MiniBlog_UpdatePost_Handler,
+		},
+		{
+			MethodName: "DeletePost",
+			Handler:    _MiniBlog_DeletePost_Handler,
+		},
+		{
+			MethodName: "ListDeletedPosts",
+			Handler:    _MiniBlog_ListDeletedPosts_Handler,
+		},
+		{
+			MethodName: "RestorePost",
+			Handler:    _MiniBlog_RestorePost_Handler,
+		},
+		{
+			MethodName: "ListPostRevisions",
+			Handler:    _MiniBlog_ListPostRevisions_Handler,
+		},
+		{
+			MethodName: "GetPostRevision",
+			Handler:    _MiniBlog_GetPostRevision_Handler,
+		},
+		{
+			MethodName: "DiffPostRevisions",
+			Handler:    _MiniBlog_DiffPostRevisions_Handler,
+		},
+		{
+			MethodName: "RestorePostRevision",
+			Handler:    _MiniBlog_RestorePostRevision_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "miniblog/v1/miniblog.proto",
+}
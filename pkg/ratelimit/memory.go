@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval 定义了清理空闲令牌桶的最小间隔
+const sweepInterval = time.Minute
+
+// tokenBucket 是一个令牌桶的状态
+type tokenBucket struct {
+	tokens   float64   // 桶内当前的令牌数
+	last     time.Time // 上一次补充令牌的时间
+	capacity float64
+	rate     float64
+}
+
+// refill 根据距离上一次补充的时间补充令牌
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(b.capacity, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+// full 判断令牌桶是否已经补满，补满的令牌桶与新建的令牌桶等价，可以被清理
+func (b *tokenBucket) full() bool {
+	return b.tokens >= b.capacity
+}
+
+// MemoryLimiter 是 Limiter 的内存实现，限流状态只在当前进程内有效
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time // 测试中用来控制时间
+}
+
+// 确保 MemoryLimiter 实现了 Limiter 接口
+var _ Limiter = (*MemoryLimiter)(nil)
+
+// NewMemoryLimiter 创建一个 MemoryLimiter 实例
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{buckets: make(map[string]*tokenBucket), now: time.Now}
+}
+
+// Take 实现了 Limiter 接口中的 Take 方法
+func (l *MemoryLimiter) Take(ctx context.Context, buckets ...Bucket) ([]*Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	states := make([]*tokenBucket, len(buckets))
+	allowed := true
+	for i, bucket := range buckets {
+		capacity, rate := float64(bucket.Policy.Capacity()), bucket.Policy.Rate()
+		b, ok := l.buckets[bucket.Key]
+		if !ok || b.capacity != capacity || b.rate != rate {
+			// 新的 key 或者策略发生了变化，使用一个满的令牌桶
+			b = &tokenBucket{tokens: capacity, last: now, capacity: capacity, rate: rate}
+			l.buckets[bucket.Key] = b
+		}
+		b.refill(now)
+
+		states[i] = b
+		allowed = allowed && b.tokens >= 1
+	}
+
+	results := make([]*Result, len(buckets))
+	for i, b := range states {
+		result := &Result{Limit: buckets[i].Policy.Capacity(), Allowed: b.tokens >= 1}
+		if allowed {
+			b.tokens--
+		} else if !result.Allowed {
+			result.RetryAfter = secondsToDuration((1-b.tokens)/b.rate, b.rate)
+		}
+		result.Remaining = int(math.Floor(b.tokens))
+		result.ResetAfter = secondsToDuration((b.capacity-b.tokens)/b.rate, b.rate)
+		results[i] = result
+	}
+
+	return results, nil
+}
+
+// sweep 清理已经补满的令牌桶，防止 key 过多导致内存无限增长
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		b.refill(now)
+		if b.full() {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// secondsToDuration 将秒数转换为 time.Duration，rate 为 0（永不补充令牌）时返回最大值
+func secondsToDuration(seconds, rate float64) time.Duration {
+	if rate <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
@@ -0,0 +1,149 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock 是测试使用的时钟，只有调用 advance 时才会前进
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// newTestLimiter 创建一个使用 fakeClock 的 MemoryLimiter
+func newTestLimiter() (*MemoryLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := NewMemoryLimiter()
+	l.now = func() time.Time { return clock.now }
+	return l, clock
+}
+
+// take 从 key 对应的令牌桶中取一个令牌
+func take(t *testing.T, l *MemoryLimiter, key string, policy Policy) *Result {
+	t.Helper()
+
+	results, err := l.Take(context.Background(), Bucket{Key: key, Policy: policy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return results[0]
+}
+
+func TestMemoryLimiter_Burst(t *testing.T) {
+	l, _ := newTestLimiter()
+	policy := Policy{Name: "test", Limit: 10, Period: time.Minute, Burst: 3}
+
+	for i := 2; i >= 0; i-- {
+		result := take(t, l, "alice", policy)
+		if !result.Allowed || result.Limit != 3 || result.Remaining != i {
+			t.Fatalf("unexpected result: %+v", result)
+		}
+	}
+	if result := take(t, l, "alice", policy); result.Allowed {
+		t.Fatalf("request exceeding burst should be denied: %+v", result)
+	}
+
+	// 不同的 key 使用不同的令牌桶
+	if result := take(t, l, "bob", policy); !result.Allowed {
+		t.Fatalf("other keys should not be affected: %+v", result)
+	}
+
+	// Burst 为 0 时桶容量等于 Limit
+	if result := take(t, l, "carol", Policy{Limit: 2, Period: time.Minute}); result.Limit != 2 || result.Remaining != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestMemoryLimiter_Refill(t *testing.T) {
+	l, clock := newTestLimiter()
+	// 每 6s 补充一个令牌
+	policy := Policy{Name: "test", Limit: 10, Period: time.Minute, Burst: 2}
+
+	take(t, l, "alice", policy)
+	take(t, l, "alice", policy)
+
+	result := take(t, l, "alice", policy)
+	if result.Allowed || result.RetryAfter != 6*time.Second || result.ResetAfter != 12*time.Second {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	clock.advance(3 * time.Second)
+	result = take(t, l, "alice", policy)
+	if result.Allowed || result.RetryAfter != 3*time.Second || result.ResetAfter != 9*time.Second {
+		t.Fatalf("unexpected result after half a token: %+v", result)
+	}
+
+	clock.advance(3 * time.Second)
+	result = take(t, l, "alice", policy)
+	if !result.Allowed || result.Remaining != 0 || result.ResetAfter != 12*time.Second {
+		t.Fatalf("unexpected result after one token: %+v", result)
+	}
+
+	// 补充的令牌数不会超过桶容量
+	clock.advance(time.Hour)
+	result = take(t, l, "alice", policy)
+	if !result.Allowed || result.Remaining != 1 || result.ResetAfter != 6*time.Second {
+		t.Fatalf("unexpected result after a long idle: %+v", result)
+	}
+}
+
+func TestMemoryLimiter_AllOrNothing(t *testing.T) {
+	l, _ := newTestLimiter()
+	loose := Policy{Name: "loose", Limit: 10, Period: time.Minute}
+	strict := Policy{Name: "strict", Limit: 1, Period: time.Minute}
+
+	buckets := []Bucket{{Key: "loose", Policy: loose}, {Key: "strict", Policy: strict}}
+	results, err := l.Take(context.Background(), buckets...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].Allowed || !results[1].Allowed || results[0].Remaining != 9 || results[1].Remaining != 0 {
+		t.Fatalf("unexpected results: %+v %+v", results[0], results[1])
+	}
+
+	// strict 没有令牌时，请求被拒绝，loose 的令牌也不会被消耗
+	for i := 0; i < 5; i++ {
+		results, err = l.Take(context.Background(), buckets...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !results[0].Allowed || results[1].Allowed || results[0].Remaining != 9 {
+			t.Fatalf("unexpected results: %+v %+v", results[0], results[1])
+		}
+	}
+	if result := take(t, l, "loose", loose); result.Remaining != 8 {
+		t.Fatalf("denied requests should not consume tokens: %+v", result)
+	}
+}
+
+func TestMemoryLimiter_PolicyChanged(t *testing.T) {
+	l, _ := newTestLimiter()
+
+	take(t, l, "alice", Policy{Limit: 1, Period: time.Minute})
+	// 策略变化后使用一个新的满的令牌桶
+	if result := take(t, l, "alice", Policy{Limit: 5, Period: time.Minute}); !result.Allowed || result.Remaining != 4 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestMemoryLimiter_Sweep(t *testing.T) {
+	l, clock := newTestLimiter()
+	policy := Policy{Limit: 10, Period: time.Minute}
+
+	take(t, l, "alice", policy)
+	clock.advance(sweepInterval)
+	take(t, l, "bob", policy)
+
+	// alice 的令牌桶已经补满，被清理；bob 的令牌桶刚刚使用过，保留
+	if _, ok := l.buckets["alice"]; ok {
+		t.Fatal("full bucket should be swept")
+	}
+	if _, ok := l.buckets["bob"]; !ok {
+		t.Fatal("bucket in use should not be swept")
+	}
+}
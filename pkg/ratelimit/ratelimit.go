@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// KeyType 定义了限流的维度
+type KeyType string
+
+const (
+	// KeyIP 按客户端 IP 限流
+	KeyIP KeyType = "ip"
+	// KeyUsername 按认证后的用户名限流，未认证的请求退化为按 IP 限流
+	KeyUsername KeyType = "username"
+	// KeyRoute 按路由限流，即所有客户端共享同一个令牌桶
+	KeyRoute KeyType = "route"
+)
+
+// Policy 定义了一条令牌桶限流策略：每个 Period 内补充 Limit 个令牌，桶容量为 Burst
+type Policy struct {
+	Name   string        `mapstructure:"name"`   // 策略名，会作为令牌桶 key 的一部分，不同策略之间互不影响
+	Key    KeyType       `mapstructure:"key"`    // 限流维度，可选值：ip,username,route
+	Limit  int           `mapstructure:"limit"`  // 每个 Period 内补充的令牌数
+	Period time.Duration `mapstructure:"period"` // 令牌补充周期
+	Burst  int           `mapstructure:"burst"`  // 桶容量，即允许的最大突发请求数。为 0 时等于 Limit
+}
+
+// Capacity 返回令牌桶的容量
+func (p Policy) Capacity() int {
+	if p.Burst > 0 {
+		return p.Burst
+	}
+	return p.Limit
+}
+
+// Rate 返回每秒补充的令牌数
+func (p Policy) Rate() float64 {
+	if p.Period <= 0 {
+		return 0
+	}
+	return float64(p.Limit) / p.Period.Seconds()
+}
+
+// Result 是一次取令牌操作的结果
+type Result struct {
+	Allowed    bool          // 是否允许本次请求
+	Limit      int           // 令牌桶容量
+	Remaining  int           // 本次请求后桶内剩余的令牌数
+	RetryAfter time.Duration // 请求被拒绝时，距离下一个令牌可用的时间
+	ResetAfter time.Duration // 距离令牌桶被补满的时间
+}
+
+// Bucket 表示一个使用 Policy 限流、以 Key 区分的令牌桶
+type Bucket struct {
+	Key    string
+	Policy Policy
+}
+
+// Limiter 定义了限流后端需要实现的方法。
+// 除了内置的内存实现外，可以基于 Redis 等共享存储实现该接口，使多个 miniblog 实例共享限流状态
+type Limiter interface {
+	// Take 尝试从每个令牌桶中各取出一个令牌，返回的结果与 buckets 一一对应。
+	// 只有所有令牌桶中都有令牌时才会取出，否则不消耗任何令牌，避免被其他策略拒绝的请求占用本策略的配额
+	Take(ctx context.Context, buckets ...Bucket) ([]*Result, error)
+}
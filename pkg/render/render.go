@@ -0,0 +1,64 @@
+// Package render 将博客内容渲染为可以安全地嵌入页面的 HTML。
+// 所有渲染结果都会经过 Sanitize 过滤，去掉脚本、事件属性和 `javascript:` 链接等危险内容
+package render
+
+import (
+	"bytes"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	stdhtml "html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// markdown 支持 CommonMark 和 GFM 表格。允许 Markdown 中内嵌 HTML，内嵌的 HTML 由 Sanitize 过滤
+	markdown = goldmark.New(
+		goldmark.WithExtensions(extension.Table),
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+
+	// policy 在 UGC 策略的基础上保留代码块的语言和表格的对齐方式。bluemonday.Policy 创建后可以并发使用
+	policy = func() *bluemonday.Policy {
+		p := bluemonday.UGCPolicy()
+		p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+		p.AllowStyles("text-align").MatchingEnum("left", "center", "right").OnElements("th", "td")
+		return p
+	}()
+
+	// blankLines 匹配分隔段落的空行
+	blankLines = regexp.MustCompile(`\n\s*\n`)
+)
+
+// Markdown 将 Markdown 渲染为 HTML
+func Markdown(source string) string {
+	var buf bytes.Buffer
+	// 写入 bytes.Buffer 不会失败，goldmark 只会返回写入时的错误
+	if err := markdown.Convert([]byte(source), &buf); err != nil {
+		return Plain(source)
+	}
+	return Sanitize(buf.String())
+}
+
+// Plain 将纯文本渲染为 HTML，空行分隔的文本渲染为段落，段落内的换行渲染为 `<br>`
+func Plain(source string) string {
+	source = strings.TrimSpace(strings.ReplaceAll(source, "\r\n", "\n"))
+	if source == "" {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, paragraph := range blankLines.Split(source, -1) {
+		sb.WriteString("<p>")
+		sb.WriteString(strings.ReplaceAll(stdhtml.EscapeString(strings.TrimSpace(paragraph)), "\n", "<br>\n"))
+		sb.WriteString("</p>\n")
+	}
+	return sb.String()
+}
+
+// Sanitize 过滤 HTML，去掉脚本、样式表、事件属性、`javascript:` 链接等可能导致 XSS 的内容，只保留常用的排版标签
+func Sanitize(source string) string {
+	return policy.Sanitize(source)
+}
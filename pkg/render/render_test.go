@@ -0,0 +1,44 @@
+package render_test
+
+import (
+	"miniblog/pkg/render"
+	"strings"
+	"testing"
+)
+
+func TestMarkdown(t *testing.T) {
+	source := "# Title\n\n| a | b |\n|:-|-:|\n| 1 | 2 |\n\n```go\nfmt.Println(\"<x>\")\n```\n"
+	got := render.Markdown(source)
+	for _, want := range []string{
+		"<h1>Title</h1>",
+		`<th style="text-align: left">a</th>`,
+		`<td style="text-align: right">2</td>`,
+		`<pre><code class="language-go">fmt.Println(&#34;&lt;x&gt;&#34;)`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("want %q in rendered markdown:\n%s", want, got)
+		}
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	source := "<p onclick=\"steal()\">hello</p><script>alert(1)</script>" +
+		"<a href=\"javascript:alert(1)\">x</a><img src=\"a.png\" onerror=\"alert(1)\"><iframe src=\"https://evil.example\"></iframe>"
+	got := render.Sanitize(source)
+	if want := `<p>hello</p>x<img src="a.png">`; got != want {
+		t.Fatalf("want %q, got %q", want, got)
+	}
+
+	// Markdown 中内嵌的 HTML 同样会被过滤
+	got = render.Markdown("[x](javascript:alert(1))\n\n<script>alert(1)</script>\n")
+	if strings.Contains(got, "javascript") || strings.Contains(got, "script") {
+		t.Fatalf("dangerous content was not removed: %q", got)
+	}
+}
+
+func TestPlain(t *testing.T) {
+	got := render.Plain("a <b>\nc\n\n\nd\r\n")
+	if want := "<p>a &lt;b&gt;<br>\nc</p>\n<p>d</p>\n"; got != want {
+		t.Fatalf("want %q, got %q", want, got)
+	}
+}
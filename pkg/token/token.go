@@ -0,0 +1,160 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"time"
+)
+
+// Config 包括签发和解析 token 的配置，使用 New 创建，可以被多个 goroutine 并发使用
+type Config struct {
+	key         string        // 签发 token 时使用的密钥
+	identityKey string        // token 中用来标识身份的 claim 名
+	expire      time.Duration // token 的有效期
+}
+
+// ErrMissingHeader 表示 `Authorization` 请求头为空
+var ErrMissingHeader = errors.New("the length of the `Authorization` header is zero")
+
+// ErrMissingKey 表示创建 Config 时没有指定密钥
+var ErrMissingKey = errors.New("the token signing key is empty")
+
+// identityKey 和 expire 未指定时使用的默认值
+const (
+	defaultIdentityKey = "identityKey"
+	defaultExpire      = 2 * time.Hour
+)
+
+// New 创建一个 Config，签发和解析 token 时使用 key 作为密钥，identityKey 作为标识身份的 claim 名，签发的 token 有效期为 expire。
+// key 不能为空，identityKey 和 expire 为空时使用默认值
+func New(key string, identityKey string, expire time.Duration) (*Config, error) {
+	if key == "" {
+		return nil, ErrMissingKey
+	}
+
+	c := &Config{key: key, identityKey: defaultIdentityKey, expire: defaultExpire}
+	if identityKey != "" {
+		c.identityKey = identityKey
+	}
+	if expire > 0 {
+		c.expire = expire
+	}
+	return c, nil
+}
+
+// 用途受限的 token 中存放用途和指纹的 claim 名，见 SignPurpose
+const (
+	purposeClaim     = "purpose"
+	fingerprintClaim = "fingerprint"
+)
+
+// Parse 解析 token，解析成功返回 token 上下文，否则报错。
+// 由 SignPurpose 签发的用途受限的 token 不能通过 Parse 的校验
+func (c *Config) Parse(tokenString string) (string, error) {
+	claims, err := c.parse(tokenString)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := claims[purposeClaim]; ok {
+		return "", jwt.ErrTokenInvalidClaims
+	}
+
+	return c.identity(claims)
+}
+
+// ParsePurpose 解析由 SignPurpose 签发、用途为 purpose 的 token，解析成功返回 token 上下文和签发时传入的指纹，否则报错
+func (c *Config) ParsePurpose(tokenString string, purpose string) (identityKey string, fingerprint string, err error) {
+	claims, err := c.parse(tokenString)
+	if err != nil {
+		return "", "", err
+	}
+	if p, _ := claims[purposeClaim].(string); p == "" || p != purpose {
+		return "", "", jwt.ErrTokenInvalidClaims
+	}
+
+	identityKey, err = c.identity(claims)
+	if err != nil {
+		return "", "", err
+	}
+	fingerprint, _ = claims[fingerprintClaim].(string)
+	return identityKey, fingerprint, nil
+}
+
+// parse 校验 token 的签名和有效期，返回 token 的 claims
+func (c *Config) parse(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
+		// 确保 token 加密算法是预期的加密算法
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return []byte(c.key), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+	return claims, nil
+}
+
+// identity 从 claims 中取出 token 的主题
+func (c *Config) identity(claims jwt.MapClaims) (string, error) {
+	identityKey, _ := claims[c.identityKey].(string)
+	if identityKey == "" {
+		return "", jwt.ErrTokenInvalidClaims
+	}
+
+	return identityKey, nil
+}
+
+// ParseHeader 从 `Authorization: Bearer <token>` 格式的请求头中解析出 token 并校验
+func (c *Config) ParseHeader(header string) (string, error) {
+	if len(header) == 0 {
+		return "", ErrMissingHeader
+	}
+
+	var t string
+	// 从请求头中取出 token
+	if _, err := fmt.Sscanf(header, "Bearer %s", &t); err != nil {
+		return "", err
+	}
+
+	return c.Parse(t)
+}
+
+// Sign 签发 token，token 的 claims 中会存放传入的 identityKey
+func (c *Config) Sign(identityKey string) (tokenString string, err error) {
+	now := time.Now()
+	// Token 的内容
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		c.identityKey: identityKey,
+		"nbf":         now.Unix(),
+		"iat":         now.Unix(),
+		"exp":         now.Add(c.expire).Unix(),
+	})
+	// 签发 token
+	return token.SignedString([]byte(c.key))
+}
+
+// SignPurpose 签发一个只能用于 purpose 的 token，有效期为 expire，例如两步登录中的 challenge、重置密码的链接。
+// 这类 token 只能通过 ParsePurpose 解析，不能作为 `Authorization` 请求头访问接口。
+// fingerprint 会原样存放在 token 中，调用方可以用它绑定签发时的状态（例如密码的哈希值），状态变化后 token 随之失效，从而实现一次性 token
+func (c *Config) SignPurpose(identityKey string, purpose string, fingerprint string, expire time.Duration) (tokenString string, err error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		c.identityKey: identityKey,
+		purposeClaim:  purpose,
+		"nbf":         now.Unix(),
+		"iat":         now.Unix(),
+		"exp":         now.Add(expire).Unix(),
+	}
+	if fingerprint != "" {
+		claims[fingerprintClaim] = fingerprint
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(c.key))
+}